@@ -0,0 +1,94 @@
+package rowboat
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// NewAppender creates a Writer adding rows to the end of an existing CSV
+// file. It verifies that the file's header matches the columns of T,
+// including their order, and does not write the header again. An empty file
+// gets a header instead. Compressed files cannot be appended to, and
+// WithChecksum and WithFooter are rejected since they end the file with a
+// row that appended rows would follow.
+func NewAppender[T any](rw io.ReadWriteSeeker, opts ...Option) (*Writer[T], error) {
+	w, err := NewWriter[T](rw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if w.opts.gzip {
+		return nil, errors.New("cannot append to a compressed file")
+	}
+	if w.opts.checksum != 0 || w.opts.footer != nil {
+		return nil, errors.New("cannot append with a checksum trailer or footer")
+	}
+
+	if _, err := rw.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(rw)
+	if _, err := br.Peek(1); err == io.EOF {
+		if err := w.WriteHeader(); err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	if err := w.verifyHeader(br); err != nil {
+		return nil, err
+	}
+
+	// Terminate a last row lacking a line break before appending
+	if _, err := rw.Seek(-1, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	var last [1]byte
+	if _, err := io.ReadFull(rw, last[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rw.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+	if last[0] != '\n' {
+		if _, err := io.WriteString(rw, "\n"); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// verifyHeader reads the schema comment and header of an existing file and
+// checks them against the written columns
+func (rw *Writer[T]) verifyHeader(br *bufio.Reader) error {
+	if rw.opts.schemaHash {
+		if _, err := verifySchemaComment(br, rw.fields); err != nil {
+			return err
+		}
+	}
+	r, err := rw.opts.wrapInput(br)
+	if err != nil {
+		return err
+	}
+	cr := csv.NewReader(r)
+	rw.opts.configureReader(cr)
+	headers, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("error reading header: %w", err)
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSpace(headers[i])
+	}
+
+	expected := rw.Headers()
+	if slices.Equal(headers, expected) {
+		return nil
+	}
+	if err := ValidateHeaders[T](headers); err != nil {
+		return err
+	}
+	return fmt.Errorf("header columns %v are not in the expected order %v", headers, expected)
+}
@@ -0,0 +1,88 @@
+package rowboat_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestAppender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.csv")
+	if err := os.WriteFile(path, []byte("Name,Email,Age\nAlice,alice@example.com,30"), 0o644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer f.Close()
+	writer, err := rowboat.NewAppender[Person](f)
+	if err != nil {
+		t.Fatalf("Failed to create Appender: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Bob", Email: "bob@example.com", Age: 25}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if want := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,25\n"; string(data) != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, data)
+	}
+}
+
+func TestAppenderEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.csv")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	defer f.Close()
+	writer, err := rowboat.NewAppender[Person](f)
+	if err != nil {
+		t.Fatalf("Failed to create Appender: %v", err)
+	}
+	if err := writer.WriteSlice([]Person{{Name: "Alice", Age: 30}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "Name,Email,Age\nAlice,,30\n"; string(data) != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, data)
+	}
+}
+
+func TestAppenderHeaderMismatch(t *testing.T) {
+	for name, content := range map[string]string{
+		"order":   "Email,Name,Age\n",
+		"missing": "Name,Email\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "log.csv")
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatalf("Failed to create file: %v", err)
+			}
+			f, err := os.OpenFile(path, os.O_RDWR, 0)
+			if err != nil {
+				t.Fatalf("Failed to open file: %v", err)
+			}
+			defer f.Close()
+			_, err = rowboat.NewAppender[Person](f)
+			if err == nil {
+				t.Fatalf("Expected header mismatch error")
+			}
+			var headerErr *rowboat.HeaderError
+			if name == "missing" && !errors.As(err, &headerErr) {
+				t.Errorf("Expected HeaderError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package rowboat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset identifies the character encoding of the input
+type Charset int
+
+const (
+	// UTF8 input is read as is. This is the default.
+	UTF8 Charset = iota
+	// Latin1 is ISO 8859-1
+	Latin1
+	// Windows1252 is the Windows Western European code page used by Excel
+	Windows1252
+	// UTF16 detects the byte order from a byte order mark, defaulting to
+	// little endian as written by Windows tools
+	UTF16
+	// UTF16LE is little endian UTF-16
+	UTF16LE
+	// UTF16BE is big endian UTF-16
+	UTF16BE
+)
+
+// utf8BOM is the byte order mark some tools, notably Excel, prepend to UTF-8 files
+const utf8BOM = "\ufeff"
+
+// skipBOM drops a UTF-8 byte order mark from the start of r before the CSV
+// parser sees it, so that a quoted first header parses. It returns the
+// number of bytes dropped.
+func skipBOM(r io.Reader) (io.Reader, int64, error) {
+	br := bufio.NewReaderSize(r, minBufferSize)
+	prefix, err := br.Peek(len(utf8BOM))
+	if err != nil && err != io.EOF {
+		return nil, 0, err
+	}
+	if string(prefix) != utf8BOM {
+		return br, 0, nil
+	}
+	n, err := br.Discard(len(utf8BOM))
+	return br, int64(n), err
+}
+
+// WithCharset makes the Reader decode input in the given character
+// encoding to UTF-8 before parsing
+func WithCharset(c Charset) Option {
+	return func(o *options) {
+		o.charset = c
+	}
+}
+
+// WithDecoder makes the Reader pass its input through decode before
+// parsing. It allows plugging in other encodings, for example
+// golang.org/x/text decoders:
+//
+//	rowboat.WithDecoder(charmap.ISO8859_15.NewDecoder().Reader)
+func WithDecoder(decode func(io.Reader) io.Reader) Option {
+	return func(o *options) {
+		o.decoder = decode
+	}
+}
+
+// decodeCharset wraps r in a reader converting c to UTF-8
+func decodeCharset(r io.Reader, c Charset) io.Reader {
+	switch c {
+	case Latin1:
+		return &singleByteReader{r: r, table: &latin1Table}
+	case Windows1252:
+		return &singleByteReader{r: r, table: &windows1252Table}
+	case UTF16:
+		return &utf16Reader{r: r, order: binary.LittleEndian, detectBOM: true}
+	case UTF16LE:
+		return &utf16Reader{r: r, order: binary.LittleEndian}
+	case UTF16BE:
+		return &utf16Reader{r: r, order: binary.BigEndian}
+	default:
+		return r
+	}
+}
+
+var latin1Table, windows1252Table [256]rune
+
+func init() {
+	for i := range latin1Table {
+		latin1Table[i] = rune(i)
+		windows1252Table[i] = rune(i)
+	}
+	// Windows-1252 differs from Latin-1 in 0x80-0x9F; the five undefined
+	// bytes keep their Latin-1 control code points
+	for i, r := range [32]rune{
+		'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+		0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+	} {
+		windows1252Table[0x80+i] = r
+	}
+}
+
+// singleByteReader decodes a single-byte character set to UTF-8
+type singleByteReader struct {
+	r     io.Reader
+	table *[256]rune
+	buf   []byte
+	out   []byte
+}
+
+func (s *singleByteReader) Read(p []byte) (int, error) {
+	if len(s.out) == 0 {
+		if s.buf == nil {
+			s.buf = make([]byte, 4096)
+		}
+		n, err := s.r.Read(s.buf)
+		s.out = s.out[:0]
+		for _, b := range s.buf[:n] {
+			s.out = utf8.AppendRune(s.out, s.table[b])
+		}
+		if len(s.out) == 0 {
+			return 0, err
+		}
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	return n, nil
+}
+
+// utf16Reader decodes UTF-16 to UTF-8
+type utf16Reader struct {
+	r         io.Reader
+	order     binary.ByteOrder
+	detectBOM bool
+	started   bool
+	in        []byte
+	out       []byte
+	err       error
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.out) == 0 {
+		if u.err != nil {
+			return 0, u.err
+		}
+		buf := make([]byte, 4096)
+		n, err := u.r.Read(buf)
+		u.in = append(u.in, buf[:n]...)
+		u.err = err
+		u.decode(err != nil)
+	}
+	n := copy(p, u.out)
+	u.out = u.out[n:]
+	return n, nil
+}
+
+// decode converts the complete code units buffered in u.in. When final is
+// set, trailing incomplete input is replaced with U+FFFD.
+func (u *utf16Reader) decode(final bool) {
+	if !u.started {
+		if len(u.in) < 2 && !final {
+			return
+		}
+		u.started = true
+		if u.detectBOM && len(u.in) >= 2 {
+			switch {
+			case u.in[0] == 0xff && u.in[1] == 0xfe:
+				u.order, u.in = binary.LittleEndian, u.in[2:]
+			case u.in[0] == 0xfe && u.in[1] == 0xff:
+				u.order, u.in = binary.BigEndian, u.in[2:]
+			}
+		}
+	}
+
+	i := 0
+	for i+2 <= len(u.in) {
+		r1 := rune(u.order.Uint16(u.in[i:]))
+		if !utf16.IsSurrogate(r1) {
+			u.out = utf8.AppendRune(u.out, r1)
+			i += 2
+			continue
+		}
+		if i+4 > len(u.in) {
+			if !final {
+				break
+			}
+			u.out = utf8.AppendRune(u.out, utf8.RuneError)
+			i += 2
+			continue
+		}
+		r2 := rune(u.order.Uint16(u.in[i+2:]))
+		if r := utf16.DecodeRune(r1, r2); r != utf8.RuneError {
+			u.out = utf8.AppendRune(u.out, r)
+			i += 4
+		} else {
+			u.out = utf8.AppendRune(u.out, utf8.RuneError)
+			i += 2
+		}
+	}
+	u.in = u.in[i:]
+	if final && len(u.in) > 0 {
+		u.out = utf8.AppendRune(u.out, utf8.RuneError)
+		u.in = nil
+	}
+}
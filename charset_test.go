@@ -0,0 +1,112 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestUTF8BOM(t *testing.T) {
+	csvData := "\ufeffName,Email,Age\nAlice,alice@example.com,30\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	// A quoted first header follows the byte order mark
+	quoted := "\ufeff\"Name\",Email,Age\nAlice,alice@example.com,30\n"
+	rb, err = rowboat.NewReader[Person](strings.NewReader(quoted))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results = slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, results)
+	}
+	if offset := rb.Offset(); offset != int64(len(quoted)) {
+		t.Errorf("Expected: %+v\nGot: %+v", len(quoted), offset)
+	}
+}
+
+func TestCharsets(t *testing.T) {
+	expected := []Person{{Name: "Zoë “Zo” Müller", Email: "zoe@example.com", Age: 30}}
+
+	utf16Encode := func(s string, order binary.ByteOrder, bom bool) []byte {
+		var buf bytes.Buffer
+		if bom {
+			binary.Write(&buf, order, uint16(0xfeff))
+		}
+		for _, u := range utf16.Encode([]rune(s)) {
+			binary.Write(&buf, order, u)
+		}
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		charset rowboat.Charset
+		data    []byte
+	}{
+		{
+			name:    "windows-1252",
+			charset: rowboat.Windows1252,
+			data:    []byte("Name,Email,Age\nZo\xeb \x93Zo\x94 M\xfcller,zoe@example.com,30\n"),
+		},
+		{
+			name:    "utf-16 with bom",
+			charset: rowboat.UTF16,
+			data:    utf16Encode("Name,Email,Age\nZoë “Zo” Müller,zoe@example.com,30\n", binary.BigEndian, true),
+		},
+		{
+			name:    "utf-16le",
+			charset: rowboat.UTF16LE,
+			data:    utf16Encode("Name,Email,Age\r\nZoë “Zo” Müller,zoe@example.com,30\r\n", binary.LittleEndian, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Read one byte at a time to exercise partial code units
+			r := io.MultiReader(slices.Collect(func(yield func(io.Reader) bool) {
+				for _, b := range tt.data {
+					if !yield(bytes.NewReader([]byte{b})) {
+						return
+					}
+				}
+			})...)
+			rb, err := rowboat.NewReader[Person](r, rowboat.WithCharset(tt.charset))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			results := slices.Collect(rb.All())
+			if !reflect.DeepEqual(results, expected) {
+				t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+			}
+		})
+	}
+}
+
+func TestLatin1(t *testing.T) {
+	data := "Name,Email,Age\nJos\xe9,jose@example.com,41\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(data), rowboat.WithCharset(rowboat.Latin1))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	expected := []Person{{Name: "José", Email: "jose@example.com", Age: 41}}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
@@ -0,0 +1,117 @@
+package rowboat
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// Checksum selects the algorithm of the trailer written by WithChecksum
+type Checksum int
+
+const (
+	// CRC32 is the IEEE CRC-32 checksum, fast but not tamper-proof
+	CRC32 Checksum = iota + 1
+	// SHA256 is the SHA-256 digest, which also detects deliberate edits
+	SHA256
+)
+
+// String returns the name used for the algorithm in trailer records
+func (c Checksum) String() string {
+	switch c {
+	case CRC32:
+		return "crc32"
+	case SHA256:
+		return "sha256"
+	default:
+		return fmt.Sprintf("Checksum(%d)", int(c))
+	}
+}
+
+// ChecksumTrailer is the first cell of trailer records written by
+// WithChecksum
+const ChecksumTrailer = "TRAILER"
+
+// ErrChecksum is returned when a checksum trailer is missing or does not
+// match the rows read
+var ErrChecksum = errors.New("checksum mismatch")
+
+// WithChecksum makes the Writer append a trailer record on Close holding
+// the number of data rows and their checksum, e.g. TRAILER,2,crc32:1a2b3c4d,
+// and makes the Reader verify that trailer. The checksum covers the rows as
+// written with a comma delimiter and \n line endings, independently of the
+// file's own format; the header is not included.
+func WithChecksum(alg Checksum) Option {
+	return func(o *options) {
+		o.checksum = alg
+	}
+}
+
+// bodyHash computes the row count and checksum of data rows
+type bodyHash struct {
+	alg  Checksum
+	hash hash.Hash
+	w    *csv.Writer
+	rows int
+}
+
+// newBodyHash returns a bodyHash for alg, or nil if alg is not set
+func newBodyHash(alg Checksum) *bodyHash {
+	var h hash.Hash
+	switch alg {
+	case CRC32:
+		h = crc32.NewIEEE()
+	case SHA256:
+		h = sha256.New()
+	default:
+		return nil
+	}
+	return &bodyHash{alg: alg, hash: h, w: csv.NewWriter(h)}
+}
+
+// add adds a data row to the checksum
+func (b *bodyHash) add(record []string) {
+	b.w.Write(record)
+	b.rows++
+}
+
+// trailer returns the trailer record describing the rows added so far
+func (b *bodyHash) trailer() []string {
+	b.w.Flush()
+	sum := b.alg.String() + ":" + hex.EncodeToString(b.hash.Sum(nil))
+	return []string{ChecksumTrailer, strconv.Itoa(b.rows), sum}
+}
+
+// isChecksumTrailer reports whether record has the shape of a trailer
+// written by WithChecksum: the ChecksumTrailer marker, a row count and an
+// algorithm-prefixed checksum
+func isChecksumTrailer(record []string) bool {
+	if len(record) != 3 || record[0] != ChecksumTrailer {
+		return false
+	}
+	if n, err := strconv.Atoi(record[1]); err != nil || n < 0 {
+		return false
+	}
+	alg, _, ok := strings.Cut(record[2], ":")
+	return ok && (alg == CRC32.String() || alg == SHA256.String())
+}
+
+// verify checks a trailer record against the rows added so far
+func (b *bodyHash) verify(trailer []string) error {
+	want := b.trailer()
+	switch {
+	case len(trailer) != len(want):
+		return fmt.Errorf("%w: malformed trailer %v", ErrChecksum, trailer)
+	case trailer[1] != want[1]:
+		return fmt.Errorf("%w: trailer counts %s rows, read %s", ErrChecksum, trailer[1], want[1])
+	case trailer[2] != want[2]:
+		return fmt.Errorf("%w: trailer has %s, computed %s", ErrChecksum, trailer[2], want[2])
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestChecksumTrailer(t *testing.T) {
+	rows := []Settlement{{"A1", 10.5}, {"A2", 20}}
+	for _, alg := range []rowboat.Checksum{rowboat.CRC32, rowboat.SHA256} {
+		t.Run(alg.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := rowboat.NewWriter[Settlement](&buf, rowboat.WithChecksum(alg))
+			if err != nil {
+				t.Fatalf("Failed to create Writer: %v", err)
+			}
+			if err := writer.WriteHeader(); err != nil {
+				t.Fatalf("Failed to write header: %v", err)
+			}
+			if err := writer.WriteSlice(rows); err != nil {
+				t.Fatalf("Failed to write records: %v", err)
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Failed to close Writer: %v", err)
+			}
+
+			if alg == rowboat.CRC32 {
+				sum := crc32.ChecksumIEEE([]byte("A1,10.5\nA2,20\n"))
+				expected := fmt.Sprintf("account,amount\nA1,10.5\nA2,20\nTRAILER,2,crc32:%08x\n", sum)
+				if buf.String() != expected {
+					t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+				}
+			}
+
+			// CRLF line endings do not change the checksum
+			input := strings.ReplaceAll(buf.String(), "\n", "\r\n")
+			rb, err := rowboat.NewReader[Settlement](strings.NewReader(input), rowboat.WithChecksum(alg))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			got, err := rb.ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to verify checksum: %v", err)
+			}
+			if !reflect.DeepEqual(got, rows) {
+				t.Errorf("Expected: %+v\nGot: %+v", rows, got)
+			}
+
+			tampered := strings.Replace(buf.String(), "10.5", "10.6", 1)
+			rb, err = rowboat.NewReader[Settlement](strings.NewReader(tampered), rowboat.WithChecksum(alg))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			if _, err := rb.ReadAll(); !errors.Is(err, rowboat.ErrChecksum) {
+				t.Errorf("Expected ErrChecksum, got %v", err)
+			}
+		})
+	}
+
+	rb, err := rowboat.NewReader[Settlement](strings.NewReader("account,amount\nA1,10.5\n"), rowboat.WithChecksum(rowboat.CRC32))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := rb.ReadAll(); !errors.Is(err, rowboat.ErrChecksum) {
+		t.Errorf("Expected ErrChecksum for a missing trailer, got %v", err)
+	}
+}
+
+func TestChecksumTrailerShape(t *testing.T) {
+	// A data row starting with the trailer marker is not the trailer
+	rows := []Settlement{{"TRAILER", 5}, {"A1", 10.5}}
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Settlement](&buf, rowboat.WithChecksum(rowboat.CRC32))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(rows); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	rb, err := rowboat.NewReader[Settlement](strings.NewReader(buf.String()), rowboat.WithChecksum(rowboat.CRC32))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to verify checksum: %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Expected: %+v\nGot: %+v", rows, got)
+	}
+	if trailer := rb.Trailer(); len(trailer) != 3 || trailer[1] != "2" {
+		t.Errorf("Expected the trailer to count 2 rows, got %v", trailer)
+	}
+}
+
+func TestChecksumRowsAfterTrailer(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Settlement](&buf, rowboat.WithChecksum(rowboat.SHA256))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(Settlement{"A1", 10.5}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "settlement.csv")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	defer f.Close()
+	if _, err := rowboat.NewAppender[Settlement](f, rowboat.WithChecksum(rowboat.SHA256)); err == nil {
+		t.Errorf("Expected an error appending with a checksum trailer")
+	}
+
+	// Rows appended after the trailer fail verification
+	input := buf.String() + "A2,20\n"
+	rb, err := rowboat.NewReader[Settlement](strings.NewReader(input), rowboat.WithChecksum(rowboat.SHA256))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := rb.ReadAll(); !errors.Is(err, rowboat.ErrChecksum) {
+		t.Errorf("Expected ErrChecksum for rows after the trailer, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package rowboat
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// Encrypter encrypts the cells of fields with the encrypt tag option on
+// write and decrypts them on read
+type Encrypter interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// ColumnEncrypter is an Encrypter binding each ciphertext to its column, so
+// that cells swapped between columns fail to decrypt. The Reader and Writer
+// use these methods when the Encrypter implements them.
+type ColumnEncrypter interface {
+	Encrypter
+	EncryptColumn(column, plaintext string) (string, error)
+	DecryptColumn(column, ciphertext string) (string, error)
+}
+
+// WithCipher sets the Encrypter for fields with the encrypt tag option.
+// Empty and null cells are left as they are.
+func WithCipher(e Encrypter) Option {
+	return func(o *options) {
+		o.cipher = e
+	}
+}
+
+// checkCipher reports an error if fields need an Encrypter and none is set
+func (o *options) checkCipher(fields []fieldInfo) error {
+	for _, fi := range fields {
+		if fi.Encrypt && o.cipher == nil {
+			return fmt.Errorf("encrypted field '%s' requires WithCipher", fi.Field.Name)
+		}
+	}
+	return nil
+}
+
+// encrypt encrypts a cell of the named column
+func (o *options) encrypt(column, plaintext string) (string, error) {
+	if ce, ok := o.cipher.(ColumnEncrypter); ok {
+		return ce.EncryptColumn(column, plaintext)
+	}
+	return o.cipher.Encrypt(plaintext)
+}
+
+// decrypt decrypts a cell of the named column
+func (o *options) decrypt(column, ciphertext string) (string, error) {
+	if ce, ok := o.cipher.(ColumnEncrypter); ok {
+		return ce.DecryptColumn(column, ciphertext)
+	}
+	return o.cipher.Decrypt(ciphertext)
+}
+
+// aesGCM is an Encrypter using AES-GCM
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns an Encrypter using AES-GCM with a 16, 24 or 32 byte
+// key. Cells are encrypted with a random nonce and written as base64, so
+// equal values produce different ciphertexts. It is a ColumnEncrypter
+// authenticating the column name as associated data.
+func NewAESGCM(key []byte) (Encrypter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return aesGCM{aead: aead}, nil
+}
+
+func (c aesGCM) Encrypt(plaintext string) (string, error) {
+	return c.seal(plaintext, nil)
+}
+
+func (c aesGCM) Decrypt(ciphertext string) (string, error) {
+	return c.open(ciphertext, nil)
+}
+
+func (c aesGCM) EncryptColumn(column, plaintext string) (string, error) {
+	return c.seal(plaintext, []byte(column))
+}
+
+func (c aesGCM) DecryptColumn(column, ciphertext string) (string, error) {
+	return c.open(ciphertext, []byte(column))
+}
+
+// seal encrypts plaintext authenticating data
+func (c aesGCM) seal(plaintext string, data []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), data)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open decrypts ciphertext sealed with data
+func (c aesGCM) open(ciphertext string, data []byte) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
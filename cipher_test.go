@@ -0,0 +1,169 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type CardPayment struct {
+	ID     int     `csv:"id"`
+	Card   string  `csv:"card,encrypt"`
+	Amount float64 `csv:"amount"`
+}
+
+func TestCipher(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	cipher, err := rowboat.NewAESGCM(key)
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	payments := []CardPayment{
+		{ID: 1, Card: "4111111111111111", Amount: 9.5},
+		{ID: 2, Card: "", Amount: 3},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[CardPayment](&buf, rowboat.WithCipher(cipher))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(payments); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if strings.Contains(buf.String(), "4111") {
+		t.Fatalf("Expected the card number to be encrypted, got %q", buf.String())
+	}
+	if !strings.HasSuffix(buf.String(), "\n2,,3\n") {
+		t.Errorf("Expected empty cells to stay empty, got %q", buf.String())
+	}
+	encrypted := buf.String()
+
+	reader, err := rowboat.NewReader[CardPayment](strings.NewReader(encrypted), rowboat.WithCipher(cipher))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(got, payments) {
+		t.Errorf("Expected: %+v\nGot: %+v", payments, got)
+	}
+
+	// A different key fails to decrypt
+	other, err := rowboat.NewAESGCM([]byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+	reader, err = rowboat.NewReader[CardPayment](strings.NewReader(encrypted), rowboat.WithCipher(other))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	var rowErr *rowboat.RowError
+	if _, err := reader.ReadAll(); !errors.As(err, &rowErr) || rowErr.Column != "card" {
+		t.Errorf("Expected a RowError for column card, got %v", err)
+	}
+
+	if _, err := rowboat.NewWriter[CardPayment](&buf); err == nil {
+		t.Errorf("Expected an error for an encrypted field without a cipher")
+	}
+}
+
+func TestCipherNullsAndColumns(t *testing.T) {
+	type Secret struct {
+		ID   int     `csv:"id"`
+		PIN  *string `csv:"pin,encrypt"`
+		Code string  `csv:"code,encrypt"`
+	}
+	cipher, err := rowboat.NewAESGCM([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Failed to create cipher: %v", err)
+	}
+
+	// Nulls reach the database as nulls
+	var buf bytes.Buffer
+	copyWriter, err := rowboat.NewCopyTextWriter[Secret](&buf, rowboat.WithCipher(cipher))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := copyWriter.WriteSlice([]Secret{{ID: 1}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if expected := "1\t\\N\t\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+	got, err := rowboat.NewCopyTextReader[Secret](strings.NewReader(buf.String()), rowboat.WithCipher(cipher))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if records, err := got.ReadAll(); err != nil || len(records) != 1 || records[0].PIN != nil {
+		t.Errorf("Expected a nil PIN, got %+v, %v", records, err)
+	}
+
+	// Ciphertexts are bound to their column
+	buf.Reset()
+	pin := "1234"
+	writer, err := rowboat.NewWriter[Secret](&buf, rowboat.WithCipher(cipher))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice([]Secret{{ID: 1, PIN: &pin, Code: "5678"}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	swapped := "id,code,pin\n" + buf.String()
+	if _, err := mustReader[Secret](t, swapped, rowboat.WithCipher(cipher)).ReadAll(); err == nil {
+		t.Errorf("Expected cells swapped between columns to fail to decrypt")
+	}
+	if _, err := mustReader[Secret](t, "id,pin,code\n"+buf.String(), rowboat.WithCipher(cipher)).ReadAll(); err != nil {
+		t.Errorf("Failed to read records: %v", err)
+	}
+}
+
+// prefixCipher produces ciphertexts starting with a formula trigger
+type prefixCipher struct{}
+
+func (prefixCipher) Encrypt(plaintext string) (string, error) { return "+" + plaintext, nil }
+
+func (prefixCipher) Decrypt(ciphertext string) (string, error) {
+	plaintext, ok := strings.CutPrefix(ciphertext, "+")
+	if !ok {
+		return "", errors.New("malformed ciphertext")
+	}
+	return plaintext, nil
+}
+
+func TestCipherEscapeFormulas(t *testing.T) {
+	type Note struct {
+		Body   string `csv:"body"`
+		Secret string `csv:"secret,encrypt"`
+	}
+	records := []Note{{Body: "=SUM(A1)", Secret: "=SUM(B1)"}}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Note](&buf, rowboat.WithCipher(prefixCipher{}), rowboat.WithEscapeFormulas("'"))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(records); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if expected := "'=SUM(A1),+=SUM(B1)\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+
+	got, err := mustReader[Note](t, "body,secret\n"+buf.String(), rowboat.WithCipher(prefixCipher{})).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if expected := []Note{{Body: "'=SUM(A1)", Secret: "=SUM(B1)"}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
@@ -0,0 +1,108 @@
+// Command rowboat-gen generates Go code for rowboat.
+//
+// By default it generates a struct definition for a CSV file. It reads the
+// header and a sample of the rows, infers the type of every column and
+// prints a struct with csv tags matching the header:
+//
+//	rowboat-gen -type Order -pkg orders -o order.go orders.csv
+//
+// The file is read from standard input when no path is given.
+//
+// With -codec it instead reads a Go source file and generates
+// reflection-free MarshalCSVRow and UnmarshalCSVRow methods for its tagged
+// structs, which rowboat.Reader and rowboat.Writer use when present:
+//
+//	//go:generate rowboat-gen -codec -type Order $GOFILE
+//
+// The output defaults to the source file name with a _rowboat.go suffix.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/notnil/rowboat"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the generated struct (default Record), or comma-separated structs to generate codecs for")
+	pkg := flag.String("pkg", "main", "package of the generated file")
+	out := flag.String("o", "", "output file (default standard output, or <file>_rowboat.go with -codec)")
+	sample := flag.Int("sample", 1000, "number of rows sampled to infer types, 0 for all")
+	delimiter := flag.String("delim", ",", "field delimiter")
+	codec := flag.Bool("codec", false, "generate codecs for the structs of a Go source file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: rowboat-gen [flags] [file.csv]\n       rowboat-gen -codec [-type names] file.go\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var err error
+	if *codec {
+		err = runCodec(flag.Arg(0), *out, *typeName)
+	} else {
+		if *typeName == "" {
+			*typeName = "Record"
+		}
+		err = run(flag.Arg(0), *out, *pkg, *typeName, *sample, *delimiter)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rowboat-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(path, out, pkg, typeName string, sample int, delimiter string) error {
+	var in io.Reader = os.Stdin
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	delim := []rune(delimiter)
+	if len(delim) != 1 {
+		return fmt.Errorf("delimiter must be a single character")
+	}
+
+	schema, err := rowboat.InferSchema(in, sample, rowboat.WithDecompression(), rowboat.WithDelimiter(delim[0]))
+	if err != nil {
+		return err
+	}
+	src, err := rowboat.GenerateStruct(pkg, typeName, schema)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
+
+func runCodec(path, out, typeNames string) error {
+	if path == "" {
+		return fmt.Errorf("-codec requires a Go source file")
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var names []string
+	if typeNames != "" {
+		names = strings.Split(typeNames, ",")
+	}
+	code, err := rowboat.GenerateCodecs(src, names...)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		out = strings.TrimSuffix(path, ".go") + "_rowboat.go"
+	}
+	return os.WriteFile(out, code, 0o644)
+}
@@ -0,0 +1,337 @@
+// Command rowboat selects, filters and converts CSV files.
+//
+//	rowboat select -c name,age people.csv
+//	rowboat filter 'age>25' 'country=NL' people.csv
+//	rowboat convert -to jsonl people.csv
+//
+// Input is read from the file given as the last argument, or from standard
+// input, and may be gzip compressed. Output is written to standard output.
+// Filters compare numerically when both sides are numbers and as strings
+// otherwise; the operators are =, !=, <, <=, >, >= and ~ (contains). Empty
+// cells only match = and !=.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/notnil/rowboat"
+)
+
+const usage = `usage: rowboat <command> [flags] [file]
+
+commands:
+  select -c cols   keep the named columns, in the given order
+  filter expr...   keep the rows matching every expression, e.g. 'age>25'
+  convert -to fmt  convert to csv, tsv or jsonl
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "rowboat:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command in args
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(strings.TrimSpace(usage))
+	}
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	delim := fs.String("d", ",", "field delimiter of the input")
+
+	var run func(in io.Reader, c config) error
+	switch cmd {
+	case "select":
+		columns := fs.String("c", "", "comma-separated columns to keep")
+		run = func(in io.Reader, c config) error {
+			return selectColumns(in, stdout, strings.Split(*columns, ","), c)
+		}
+	case "filter":
+		run = func(in io.Reader, c config) error {
+			exprs := fs.Args()
+			if len(exprs) > 0 && !isExpr(exprs[len(exprs)-1]) {
+				exprs = exprs[:len(exprs)-1] // the input file
+			}
+			return filterRows(in, stdout, exprs, c)
+		}
+	case "convert":
+		to := fs.String("to", "csv", "output format: csv, tsv or jsonl")
+		sample := fs.Int("sample", 1000, "rows sampled to infer JSON value types, 0 for all")
+		run = func(in io.Reader, c config) error {
+			return convert(in, stdout, *to, *sample, c)
+		}
+	default:
+		return fmt.Errorf("unknown command %q\n%s", cmd, usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	d := []rune(*delim)
+	if len(d) != 1 {
+		return errors.New("delimiter must be a single character")
+	}
+	c := config{
+		in:  []rowboat.Option{rowboat.WithDecompression(), rowboat.WithDelimiter(d[0])},
+		out: []rowboat.Option{rowboat.WithDelimiter(d[0])},
+	}
+
+	in := stdin
+	if path := fs.Arg(fs.NArg() - 1); path != "" && !isExpr(path) {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	return run(in, c)
+}
+
+// config holds the reader and writer options of a command
+type config struct {
+	in, out []rowboat.Option
+}
+
+// peekSchema infers the schema from a sample of in and returns a reader
+// replaying the whole input
+func peekSchema(in io.Reader, sample int, opts []rowboat.Option) (rowboat.Schema, io.Reader, error) {
+	var buf bytes.Buffer
+	schema, err := rowboat.InferSchema(io.TeeReader(in, &buf), sample, opts...)
+	return schema, io.MultiReader(&buf, in), err
+}
+
+// stringSchema returns a schema of string columns with the given names, so
+// cells pass through unchanged
+func stringSchema(names []string) rowboat.Schema {
+	schema := rowboat.NewSchema()
+	for _, name := range names {
+		schema = schema.Field(name, rowboat.String)
+	}
+	return schema
+}
+
+// columnNames returns the names of the columns of schema
+func columnNames(schema rowboat.Schema) []string {
+	var names []string
+	for _, col := range schema.Columns() {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// checkColumns reports an error if a column is missing from schema
+func checkColumns(schema rowboat.Schema, columns []string) error {
+	names := columnNames(schema)
+	for _, name := range columns {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return nil
+}
+
+// copyRows writes the rows of in matching keep, or all rows if keep is nil,
+// with the columns of schema
+func copyRows(in io.Reader, out io.Writer, schema rowboat.Schema, keep func(map[string]any) bool, c config) error {
+	dr, err := rowboat.NewSchemaReader(in, schema, c.in...)
+	if err != nil {
+		return err
+	}
+	dw, err := rowboat.NewDynamicWriter(out, schema, c.out...)
+	if err != nil {
+		return err
+	}
+	if err := dw.WriteHeader(); err != nil {
+		return err
+	}
+	for row, err := range dr.All() {
+		if err != nil {
+			return err
+		}
+		if keep != nil && !keep(row) {
+			continue
+		}
+		if err := dw.Write(row); err != nil {
+			return err
+		}
+	}
+	return dw.Close()
+}
+
+// selectColumns copies the named columns of in to out
+func selectColumns(in io.Reader, out io.Writer, columns []string, c config) error {
+	if len(columns) == 0 || columns[0] == "" {
+		return errors.New("select requires -c")
+	}
+	schema, in, err := peekSchema(in, 1, c.in)
+	if err != nil {
+		return err
+	}
+	if err := checkColumns(schema, columns); err != nil {
+		return err
+	}
+	return copyRows(in, out, stringSchema(columns), nil, c)
+}
+
+// exprPattern matches filter expressions such as age>=25
+var exprPattern = regexp.MustCompile(`^\s*([^<>=!~]+?)\s*(>=|<=|!=|=|<|>|~)\s*(.*?)\s*$`)
+
+// isExpr reports whether s is a filter expression
+func isExpr(s string) bool {
+	return exprPattern.MatchString(s)
+}
+
+// filter is a parsed filter expression
+type filter struct {
+	column, op, value string
+}
+
+// match reports whether the cell satisfies the filter
+func (f filter) match(cell string) bool {
+	switch {
+	case f.op == "~":
+		return strings.Contains(cell, f.value)
+	case cell == "" && f.op != "=" && f.op != "!=":
+		return false
+	}
+	cmp := strings.Compare(cell, f.value)
+	a, errA := strconv.ParseFloat(cell, 64)
+	b, errB := strconv.ParseFloat(f.value, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		default:
+			cmp = 0
+		}
+	}
+	switch f.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// filterRows copies the rows of in matching every expression to out
+func filterRows(in io.Reader, out io.Writer, exprs []string, c config) error {
+	if len(exprs) == 0 {
+		return errors.New("filter requires an expression")
+	}
+	filters := make([]filter, len(exprs))
+	columns := make([]string, len(exprs))
+	for i, expr := range exprs {
+		m := exprPattern.FindStringSubmatch(expr)
+		if m == nil {
+			return fmt.Errorf("invalid expression %q", expr)
+		}
+		filters[i] = filter{column: m[1], op: m[2], value: m[3]}
+		columns[i] = m[1]
+	}
+
+	schema, in, err := peekSchema(in, 1, c.in)
+	if err != nil {
+		return err
+	}
+	if err := checkColumns(schema, columns); err != nil {
+		return err
+	}
+	return copyRows(in, out, stringSchema(columnNames(schema)), func(row map[string]any) bool {
+		for _, f := range filters {
+			if !f.match(row[f.column].(string)) {
+				return false
+			}
+		}
+		return true
+	}, c)
+}
+
+// convert writes in to out in the given format
+func convert(in io.Reader, out io.Writer, format string, sample int, c config) error {
+	switch format {
+	case "csv", "tsv":
+		schema, in, err := peekSchema(in, 1, c.in)
+		if err != nil {
+			return err
+		}
+		c.out = nil
+		if format == "tsv" {
+			c.out = []rowboat.Option{rowboat.TSV()}
+		}
+		return copyRows(in, out, stringSchema(columnNames(schema)), nil, c)
+	case "jsonl":
+		schema, in, err := peekSchema(in, sample, c.in)
+		if err != nil {
+			return err
+		}
+		dr, err := rowboat.NewSchemaReader(in, schema, c.in...)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(out)
+		for row, err := range dr.All() {
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(orderedRow{columns: dr.Columns(), values: row}); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// orderedRow encodes a row as a JSON object keeping the column order
+type orderedRow struct {
+	columns []rowboat.Column
+	values  map[string]any
+}
+
+func (r orderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, col := range r.columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(col.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[col.Name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const people = `name,age,joined,score
+Alice,30,2024-01-02,9.5
+Bob,22,2024-02-03,
+Carol,41,2024-03-04,7
+`
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"select", "-c", "age,name"}, "age,name\n30,Alice\n22,Bob\n41,Carol\n"},
+		{[]string{"filter", "age>25", "name!=Carol"}, "name,age,joined,score\nAlice,30,2024-01-02,9.5\n"},
+		{[]string{"filter", "score<8"}, "name,age,joined,score\nCarol,41,2024-03-04,7\n"},
+		{[]string{"filter", "name~o"}, "name,age,joined,score\nBob,22,2024-02-03,\nCarol,41,2024-03-04,7\n"},
+		{[]string{"convert", "-to", "jsonl"}, `{"name":"Alice","age":30,"joined":"2024-01-02T00:00:00Z","score":9.5}
+{"name":"Bob","age":22,"joined":"2024-02-03T00:00:00Z","score":null}
+{"name":"Carol","age":41,"joined":"2024-03-04T00:00:00Z","score":7}
+`},
+		{[]string{"convert", "-to", "tsv"}, "name\tage\tjoined\tscore\nAlice\t30\t2024-01-02\t9.5\nBob\t22\t2024-02-03\t\nCarol\t41\t2024-03-04\t7\n"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(tt.args, strings.NewReader(people), &out); err != nil {
+				t.Fatalf("Command failed: %v", err)
+			}
+			if out.String() != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"select"},
+		{"select", "-c", "missing"},
+		{"filter", "missing=1"},
+		{"convert", "-to", "xml"},
+	} {
+		if err := run(args, strings.NewReader(people), &bytes.Buffer{}); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package rowboat
+
+import "reflect"
+
+// RowMarshaler is implemented by types with generated encoders (see
+// GenerateCodecs). The Writer calls MarshalCSVRow instead of encoding
+// fields through reflection.
+type RowMarshaler interface {
+	// CSVColumns returns the column names in the order of the record
+	CSVColumns() []string
+	// CSVTags returns the csv struct tags of the fields in the same order
+	CSVTags() []string
+	// MarshalCSVRow stores the cells of the value in record, which has one
+	// empty element per column
+	MarshalCSVRow(record []string) error
+}
+
+// RowUnmarshaler is implemented by pointers to types with generated
+// decoders (see GenerateCodecs). The Reader calls UnmarshalCSVRow instead
+// of decoding fields through reflection.
+type RowUnmarshaler interface {
+	// CSVColumns returns the column names in the order of index
+	CSVColumns() []string
+	// CSVTags returns the csv struct tags of the fields in the same order
+	CSVTags() []string
+	// UnmarshalCSVRow decodes record into the value. index holds the
+	// position in record of each column, or -1 for absent columns.
+	UnmarshalCSVRow(record []string, index []int) error
+}
+
+// codecCompatible reports whether generated codecs may replace reflection.
+// Generated code only implements the struct tags, not options changing how
+// fields are encoded.
+func (o *options) codecCompatible() bool {
+	return len(o.converters) == 0 && len(o.valueMaps) == 0 && len(o.columns) == 0 &&
+		o.timeLayout == "" && !o.omitEmpty && o.locale == "" && !o.stripCurrency &&
+		o.nonFinite == NonFiniteString && !o.hasNull && !o.trimSpace
+}
+
+// codecMatches reports whether generated codecs producing columns from
+// fields with tags agree with fields, so stale generated code, including
+// code generated before a tag option changed, falls back to reflection
+func codecMatches(columns, tags []string, fields []fieldInfo) bool {
+	if len(columns) != len(fields) || len(tags) != len(fields) {
+		return false
+	}
+	for i, fi := range fields {
+		if fi.Name != columns[i] || fi.Field.Tag.Get("csv") != tags[i] || fi.Decode != nil || fi.Encode != nil || fi.Values != nil ||
+			fi.Mask != nil || fi.Encrypt || lookupEnum(fi.Field.Type) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// codecIndex returns the record position of each field for the generated
+// decoder of T, or nil when records are decoded through reflection
+func (rb *Reader[T]) codecIndex() []int {
+	u, ok := reflect.New(recordType[T]()).Interface().(RowUnmarshaler)
+	if !ok || !rb.opts.codecCompatible() || !codecMatches(u.CSVColumns(), u.CSVTags(), rb.fields) {
+		return nil
+	}
+	return rb.fieldColumns()
+}
+
+// useCodec reports whether the generated encoder of T writes the fields
+func (rw *Writer[T]) useCodec(fields []fieldInfo) bool {
+	m, ok := reflect.New(recordType[T]()).Interface().(RowMarshaler)
+	return ok && rw.opts.codecCompatible() && codecMatches(m.CSVColumns(), m.CSVTags(), fields)
+}
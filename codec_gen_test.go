@@ -0,0 +1,104 @@
+// Code generated by rowboat-gen. DO NOT EDIT.
+
+package rowboat_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+// CSVColumns returns the columns of Parcel in the order of its generated codecs
+func (Parcel) CSVColumns() []string {
+	return []string{"ref", "id", "weight", "qty", "fragile", "shipped", "delivered", "origin", "notes"}
+}
+
+// CSVTags returns the csv tags of the fields of Parcel in column order
+func (Parcel) CSVTags() []string {
+	return []string{"ref,index=0", "id", "weight,prec=2", "qty", "fragile", "shipped,format=2006-01-02", "delivered,omitempty", "origin", "notes,raw"}
+}
+
+// MarshalCSVRow stores the cells of v in record
+func (v Parcel) MarshalCSVRow(record []string) error {
+	record[0] = v.Ref
+	record[1] = strconv.FormatUint(uint64(v.ID), 10)
+	record[2] = strconv.FormatFloat(float64(v.Weight), 'f', 2, 32)
+	record[3] = strconv.FormatInt(int64(v.Qty), 10)
+	record[4] = strconv.FormatBool(v.Fragile)
+	record[5] = v.Shipped.Format("2006-01-02")
+	if !reflect.ValueOf(v.Delivered).IsZero() {
+		record[6] = v.Delivered.Format(time.RFC3339)
+	}
+	{
+		s, err := v.Origin.MarshalCSV()
+		if err != nil {
+			return fmt.Errorf("error marshaling field Origin: %w", err)
+		}
+		record[7] = s
+	}
+	record[8] = string(v.Notes)
+	return nil
+}
+
+// UnmarshalCSVRow sets the fields of v from the cells of record at the
+// positions in index
+func (v *Parcel) UnmarshalCSVRow(record []string, index []int) error {
+	*v = Parcel{}
+	if j := index[0]; j >= 0 && j < len(record) {
+		v.Ref = record[j]
+	}
+	if j := index[1]; j >= 0 && j < len(record) {
+		n, err := strconv.ParseUint(record[j], 10, 64)
+		if err != nil {
+			return &rowboat.RowError{Column: "id", Err: fmt.Errorf("error setting field ID: %w", err)}
+		}
+		v.ID = uint64(n)
+	}
+	if j := index[2]; j >= 0 && j < len(record) {
+		n, err := strconv.ParseFloat(record[j], 32)
+		if err != nil {
+			return &rowboat.RowError{Column: "weight", Err: fmt.Errorf("error setting field Weight: %w", err)}
+		}
+		v.Weight = float32(n)
+	}
+	if j := index[3]; j >= 0 && j < len(record) {
+		n, err := strconv.ParseInt(record[j], 10, 64)
+		if err != nil {
+			return &rowboat.RowError{Column: "qty", Err: fmt.Errorf("error setting field Qty: %w", err)}
+		}
+		v.Qty = int8(n)
+	}
+	if j := index[4]; j >= 0 && j < len(record) {
+		b, err := strconv.ParseBool(record[j])
+		if err != nil {
+			return &rowboat.RowError{Column: "fragile", Err: fmt.Errorf("error setting field Fragile: %w", err)}
+		}
+		v.Fragile = b
+	}
+	if j := index[5]; j >= 0 && j < len(record) {
+		t, err := time.Parse("2006-01-02", record[j])
+		if err != nil {
+			return &rowboat.RowError{Column: "shipped", Err: fmt.Errorf("error setting field Shipped: %w", err)}
+		}
+		v.Shipped = t
+	}
+	if j := index[6]; j >= 0 && j < len(record) && record[j] != "" {
+		t, err := time.Parse(time.RFC3339, record[j])
+		if err != nil {
+			return &rowboat.RowError{Column: "delivered", Err: fmt.Errorf("error setting field Delivered: %w", err)}
+		}
+		v.Delivered = t
+	}
+	if j := index[7]; j >= 0 && j < len(record) {
+		if err := v.Origin.UnmarshalCSV(record[j]); err != nil {
+			return &rowboat.RowError{Column: "origin", Err: fmt.Errorf("error setting field Origin: %w", err)}
+		}
+	}
+	if j := index[8]; j >= 0 && j < len(record) {
+		v.Notes = []byte(record[j])
+	}
+	return nil
+}
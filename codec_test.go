@@ -0,0 +1,160 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+//go:generate go run ./cmd/rowboat-gen -codec -type Parcel -o codec_gen_test.go codec_test.go
+
+type Parcel struct {
+	ID        uint64    `csv:"id"`
+	Ref       string    `csv:"ref,index=0"`
+	Weight    float32   `csv:"weight,prec=2"`
+	Qty       int8      `csv:"qty"`
+	Fragile   bool      `csv:"fragile"`
+	Shipped   time.Time `csv:"shipped,format=2006-01-02"`
+	Delivered time.Time `csv:"delivered,omitempty"`
+	Origin    Point     `csv:"origin"`
+	Notes     []byte    `csv:"notes,raw"`
+	Internal  string    `csv:"-"`
+}
+
+// plainParcel has the fields of Parcel without the generated codecs
+type plainParcel Parcel
+
+func TestGeneratedCodecsUpToDate(t *testing.T) {
+	src, err := os.ReadFile("codec_test.go")
+	if err != nil {
+		t.Fatalf("Failed to read source: %v", err)
+	}
+	generated, err := rowboat.GenerateCodecs(src, "Parcel")
+	if err != nil {
+		t.Fatalf("Failed to generate codecs: %v", err)
+	}
+	golden, err := os.ReadFile("codec_gen_test.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !bytes.Equal(generated, golden) {
+		t.Errorf("codec_gen_test.go is stale, run go generate:\n%s", generated)
+	}
+}
+
+func TestGeneratedCodecs(t *testing.T) {
+	shipped := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	parcels := []Parcel{
+		{Ref: "A1", ID: 1, Weight: 1.5, Qty: -3, Fragile: true, Shipped: shipped,
+			Delivered: shipped.Add(48 * time.Hour), Origin: Point{X: 1, Y: 2}, Notes: []byte("  keep dry")},
+		{Ref: "B2", ID: 2, Weight: 0.25, Shipped: shipped, Notes: []byte{}},
+	}
+
+	// Generated codecs write the same output as reflection
+	var fast, slow, pointers bytes.Buffer
+	fw, err := rowboat.NewWriter[Parcel](&fast)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	sw, err := rowboat.NewWriter[plainParcel](&slow)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	pw, err := rowboat.NewWriter[*Parcel](&pointers)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	for _, err := range []error{fw.WriteHeader(), sw.WriteHeader(), pw.WriteHeader()} {
+		if err != nil {
+			t.Fatalf("Failed to write header: %v", err)
+		}
+	}
+	for _, p := range parcels {
+		if err := pw.Write(&p); err != nil {
+			t.Fatalf("Failed to write record: %v", err)
+		}
+		if err := fw.Write(p); err != nil {
+			t.Fatalf("Failed to write record: %v", err)
+		}
+		if err := sw.Write(plainParcel(p)); err != nil {
+			t.Fatalf("Failed to write record: %v", err)
+		}
+	}
+	for _, err := range []error{fw.Flush(), sw.Flush(), pw.Flush()} {
+		if err != nil {
+			t.Fatalf("Failed to flush Writer: %v", err)
+		}
+	}
+	if fast.String() != slow.String() {
+		t.Fatalf("Expected:\n%s\nGot:\n%s", slow.String(), fast.String())
+	}
+	if pointers.String() != slow.String() {
+		t.Fatalf("Expected:\n%s\nGot:\n%s", slow.String(), pointers.String())
+	}
+
+	rb, err := rowboat.NewReader[Parcel](&fast)
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	records, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(records, parcels) {
+		t.Errorf("Expected: %+v\nGot: %+v", parcels, records)
+	}
+}
+
+func TestGeneratedCodecsRowError(t *testing.T) {
+	csvData := "ref,id,qty\nA1,1,x\n"
+	rb, err := rowboat.NewReader[Parcel](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	_, err = rb.ReadAll()
+	want := `line 2, column "qty": error setting field Qty: strconv.ParseInt: parsing "x": invalid syntax`
+	if err == nil || err.Error() != want {
+		t.Errorf("Expected: %s\nGot: %v", want, err)
+	}
+}
+
+func TestGenerateCodecsUnsupported(t *testing.T) {
+	src := []byte("package p\n\ntype R struct {\n\tAmount int `csv:\"amount,pad=5\"`\n}\n")
+	if _, err := rowboat.GenerateCodecs(src, "R"); err == nil {
+		t.Errorf("Expected error for unsupported pad tag")
+	}
+}
+
+// Label has codecs generated before the trim tag option was added
+type Label struct {
+	Name string `csv:"name,trim"`
+}
+
+func (Label) CSVColumns() []string { return []string{"name"} }
+
+func (Label) CSVTags() []string { return []string{"name"} }
+
+func (v Label) MarshalCSVRow(record []string) error {
+	record[0] = v.Name
+	return nil
+}
+
+func (v *Label) UnmarshalCSVRow(record []string, index []int) error {
+	v.Name = record[index[0]]
+	return nil
+}
+
+func TestGeneratedCodecsStaleTags(t *testing.T) {
+	got, err := mustReader[Label](t, "name\n  Bob  \n").ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if expected := []Label{{Name: "Bob"}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
@@ -0,0 +1,382 @@
+package rowboat
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// codecField is a struct field handled by generated codecs
+type codecField struct {
+	goName  string
+	column  string
+	tag     string // csv struct tag
+	typ     string // Go type expression
+	indexed bool   // index is set by the index tag option
+	index   int
+	omit    bool
+	raw     bool
+	prec    int
+	layout  string
+}
+
+// GenerateCodecs returns the source of a Go file implementing RowMarshaler
+// and RowUnmarshaler for the named struct types declared in src, a Go
+// source file. Without type names, every struct type with csv tags is
+// handled. Fields may be strings, integers, floats, booleans, time.Time
+// values, raw []byte fields, or named types implementing CSVMarshaler and
+// CSVUnmarshaler. Structs using locale, pad, fmt, currency or ISO 8601
+// tags, or embedded fields, are rejected.
+//
+// Generated codecs are only used with options that do not change field
+// encoding, and fall back to reflection when the struct no longer matches
+// them.
+func GenerateCodecs(src []byte, typeNames ...string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	imports := make(map[string]bool)
+	found := make(map[string]bool)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || (len(typeNames) > 0 && !slices.Contains(typeNames, ts.Name.Name)) {
+				continue
+			}
+			if len(typeNames) == 0 && !hasCSVTags(st) {
+				continue
+			}
+			if ts.TypeParams != nil {
+				return nil, fmt.Errorf("type %s: generic structs are not supported", ts.Name.Name)
+			}
+			fields, err := codecFields(st)
+			if err != nil {
+				return nil, fmt.Errorf("type %s: %w", ts.Name.Name, err)
+			}
+			writeCodecs(&body, ts.Name.Name, fields, imports)
+			found[ts.Name.Name] = true
+		}
+	}
+	for _, name := range typeNames {
+		if !found[name] {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+	}
+	if len(found) == 0 {
+		return nil, errors.New("no struct types with csv tags found")
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by rowboat-gen. DO NOT EDIT.\n\npackage %s\n", file.Name.Name)
+	if len(imports) > 0 {
+		paths := make([]string, 0, len(imports))
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Slice(paths, func(i, j int) bool {
+			si, sj := strings.Contains(paths[i], "."), strings.Contains(paths[j], ".")
+			if si != sj {
+				return sj
+			}
+			return paths[i] < paths[j]
+		})
+		out.WriteString("\nimport (\n")
+		for i, path := range paths {
+			// Separate the standard library from other packages
+			if i > 0 && strings.Contains(path, ".") && !strings.Contains(paths[i-1], ".") {
+				out.WriteString("\n")
+			}
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n")
+	}
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// hasCSVTags reports whether any field of st has a csv tag
+func hasCSVTags(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if f.Tag != nil {
+			if tag, err := strconv.Unquote(f.Tag.Value); err == nil {
+				if _, ok := reflect.StructTag(tag).Lookup("csv"); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// codecFields parses the fields of st in column order, following the same
+// rules as parseFields
+func codecFields(st *ast.StructType) ([]codecField, error) {
+	var fields []codecField
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("embedded field %s is not supported", types.ExprString(f.Type))
+		}
+		var tag string
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(unquoted).Get("csv")
+		}
+		for _, name := range f.Names {
+			if tag == "-" || !name.IsExported() {
+				continue
+			}
+			cf := codecField{
+				goName: name.Name,
+				column: name.Name,
+				tag:    tag,
+				typ:    types.ExprString(f.Type),
+				prec:   -1,
+				layout: "time.RFC3339",
+			}
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				cf.column = parts[0]
+			}
+			for _, part := range parts[1:] {
+				part = strings.TrimSpace(part)
+				switch {
+				case strings.HasPrefix(part, "index="):
+					idx, err := strconv.Atoi(strings.TrimPrefix(part, "index="))
+					if err != nil || idx < 0 {
+						return nil, fmt.Errorf("invalid index in field '%s'", name.Name)
+					}
+					cf.index = idx
+					cf.indexed = true
+				case strings.HasPrefix(part, "alias="), part == "quote":
+					// Aliases and quoting do not affect cell values
+				case part == "omitempty":
+					cf.omit = true
+				case part == "raw":
+					cf.raw = true
+				case strings.HasPrefix(part, "prec="):
+					prec, err := strconv.Atoi(strings.TrimPrefix(part, "prec="))
+					if err != nil || prec < 0 {
+						return nil, fmt.Errorf("invalid prec in field '%s'", name.Name)
+					}
+					cf.prec = prec
+				case strings.HasPrefix(part, "format="):
+					layout := strings.TrimPrefix(part, "format=")
+					if layout == tagISODuration || layout == tagISOWeek {
+						return nil, fmt.Errorf("format %s in field '%s' is not supported", layout, name.Name)
+					}
+					cf.layout = strconv.Quote(layout)
+				case part != "":
+					return nil, fmt.Errorf("tag option '%s' in field '%s' is not supported", part, name.Name)
+				}
+			}
+			if err := cf.check(); err != nil {
+				return nil, err
+			}
+			fields = append(fields, cf)
+		}
+	}
+
+	taken := make(map[int]string, len(fields))
+	for _, cf := range fields {
+		if !cf.indexed {
+			continue
+		}
+		if other, ok := taken[cf.index]; ok {
+			return nil, fmt.Errorf("%w: fields '%s' and '%s' both have index %d", ErrDuplicateIndex, other, cf.goName, cf.index)
+		}
+		taken[cf.index] = cf.goName
+	}
+	next := 0
+	for i := range fields {
+		if fields[i].indexed {
+			continue
+		}
+		for taken[next] != "" {
+			next++
+		}
+		fields[i].index = next
+		taken[next] = fields[i].goName
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].index < fields[j].index
+	})
+	for i, cf := range fields {
+		if cf.index != i {
+			return nil, fmt.Errorf("gap before index %d of field '%s' is not supported", cf.index, cf.goName)
+		}
+	}
+	return fields, nil
+}
+
+// kind classifies the field's type for code generation
+func (cf codecField) kind() string {
+	switch cf.typ {
+	case "string", "bool", "time.Time", "[]byte":
+		return cf.typ
+	case "int", "int8", "int16", "int32", "int64":
+		return "int"
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return "uint"
+	case "float32", "float64":
+		return "float"
+	}
+	if token.IsIdentifier(cf.typ) || strings.Count(cf.typ, ".") == 1 && !strings.ContainsAny(cf.typ, "[]*()") {
+		return "marshaler"
+	}
+	return ""
+}
+
+// check rejects field types and tag combinations generated code cannot handle
+func (cf codecField) check() error {
+	switch kind := cf.kind(); {
+	case kind == "":
+		return fmt.Errorf("field '%s' of type %s is not supported", cf.goName, cf.typ)
+	case kind == "[]byte" && !cf.raw:
+		return fmt.Errorf("field '%s' of type []byte requires the raw tag", cf.goName)
+	case cf.raw && kind != "string" && kind != "[]byte":
+		return fmt.Errorf("raw field '%s' must be a string or []byte", cf.goName)
+	case cf.prec >= 0 && kind != "float":
+		return fmt.Errorf("prec field '%s' must be a float", cf.goName)
+	case cf.layout != "time.RFC3339" && kind != "time.Time":
+		return fmt.Errorf("format field '%s' must be a time.Time", cf.goName)
+	}
+	return nil
+}
+
+// bits returns the bit size of a float field
+func (cf codecField) bits() int {
+	if cf.typ == "float32" {
+		return 32
+	}
+	return 64
+}
+
+// writeCodecs writes the CSVColumns, MarshalCSVRow and UnmarshalCSVRow
+// methods of a struct type
+func writeCodecs(b *bytes.Buffer, typeName string, fields []codecField, imports map[string]bool) {
+	fmt.Fprintf(b, "\n// CSVColumns returns the columns of %s in the order of its generated codecs\n", typeName)
+	fmt.Fprintf(b, "func (%s) CSVColumns() []string {\n\treturn []string{", typeName)
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "%q", f.column)
+	}
+	b.WriteString("}\n}\n")
+
+	fmt.Fprintf(b, "\n// CSVTags returns the csv tags of the fields of %s in column order\n", typeName)
+	fmt.Fprintf(b, "func (%s) CSVTags() []string {\n\treturn []string{", typeName)
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "%q", f.tag)
+	}
+	b.WriteString("}\n}\n")
+
+	fmt.Fprintf(b, "\n// MarshalCSVRow stores the cells of v in record\n")
+	fmt.Fprintf(b, "func (v %s) MarshalCSVRow(record []string) error {\n", typeName)
+	for i, f := range fields {
+		cell := fmt.Sprintf("record[%d]", i)
+		field := "v." + f.goName
+		var stmt string
+		switch f.kind() {
+		case "string":
+			stmt = fmt.Sprintf("%s = %s\n", cell, field)
+		case "[]byte":
+			stmt = fmt.Sprintf("%s = string(%s)\n", cell, field)
+		case "int":
+			imports["strconv"] = true
+			stmt = fmt.Sprintf("%s = strconv.FormatInt(int64(%s), 10)\n", cell, field)
+		case "uint":
+			imports["strconv"] = true
+			stmt = fmt.Sprintf("%s = strconv.FormatUint(uint64(%s), 10)\n", cell, field)
+		case "float":
+			imports["strconv"] = true
+			stmt = fmt.Sprintf("%s = strconv.FormatFloat(float64(%s), 'f', %d, %d)\n", cell, field, f.prec, f.bits())
+		case "bool":
+			imports["strconv"] = true
+			stmt = fmt.Sprintf("%s = strconv.FormatBool(%s)\n", cell, field)
+		case "time.Time":
+			imports["time"] = true
+			stmt = fmt.Sprintf("%s = %s.Format(%s)\n", cell, field, f.layout)
+		case "marshaler":
+			imports["fmt"] = true
+			stmt = fmt.Sprintf("s, err := %s.MarshalCSV()\nif err != nil {\nreturn fmt.Errorf(\"error marshaling field %s: %%w\", err)\n}\n%s = s\n",
+				field, f.goName, cell)
+		}
+		if f.omit {
+			imports["reflect"] = true
+			fmt.Fprintf(b, "if !reflect.ValueOf(%s).IsZero() {\n%s}\n", field, stmt)
+		} else if f.kind() == "marshaler" {
+			fmt.Fprintf(b, "{\n%s}\n", stmt)
+		} else {
+			b.WriteString(stmt)
+		}
+	}
+	b.WriteString("return nil\n}\n")
+
+	fmt.Fprintf(b, "\n// UnmarshalCSVRow sets the fields of v from the cells of record at the\n// positions in index\n")
+	fmt.Fprintf(b, "func (v *%s) UnmarshalCSVRow(record []string, index []int) error {\n", typeName)
+	fmt.Fprintf(b, "*v = %s{}\n", typeName)
+	for i, f := range fields {
+		field := "v." + f.goName
+		rowErr := fmt.Sprintf("return &rowboat.RowError{Column: %q, Err: fmt.Errorf(\"error setting field %s: %%w\", err)}", f.column, f.goName)
+		fmt.Fprintf(b, "if j := index[%d]; j >= 0 && j < len(record) ", i)
+		if f.omit {
+			b.WriteString("&& record[j] != \"\" ")
+		}
+		b.WriteString("{\n")
+		if k := f.kind(); k != "string" && k != "[]byte" {
+			imports["fmt"] = true
+			imports["github.com/notnil/rowboat"] = true
+		}
+		switch f.kind() {
+		case "string":
+			fmt.Fprintf(b, "%s = record[j]\n", field)
+		case "[]byte":
+			fmt.Fprintf(b, "%s = []byte(record[j])\n", field)
+		case "int":
+			imports["strconv"] = true
+			fmt.Fprintf(b, "n, err := strconv.ParseInt(record[j], 10, 64)\nif err != nil {\n%s\n}\n%s = %s(n)\n", rowErr, field, f.typ)
+		case "uint":
+			imports["strconv"] = true
+			fmt.Fprintf(b, "n, err := strconv.ParseUint(record[j], 10, 64)\nif err != nil {\n%s\n}\n%s = %s(n)\n", rowErr, field, f.typ)
+		case "float":
+			imports["strconv"] = true
+			fmt.Fprintf(b, "n, err := strconv.ParseFloat(record[j], %d)\nif err != nil {\n%s\n}\n%s = %s(n)\n", f.bits(), rowErr, field, f.typ)
+		case "bool":
+			imports["strconv"] = true
+			fmt.Fprintf(b, "b, err := strconv.ParseBool(record[j])\nif err != nil {\n%s\n}\n%s = b\n", rowErr, field)
+		case "time.Time":
+			imports["time"] = true
+			fmt.Fprintf(b, "t, err := time.Parse(%s, record[j])\nif err != nil {\n%s\n}\n%s = t\n", f.layout, rowErr, field)
+		case "marshaler":
+			fmt.Fprintf(b, "if err := %s.UnmarshalCSV(record[j]); err != nil {\n%s\n}\n", field, rowErr)
+		}
+		b.WriteString("}\n")
+	}
+	b.WriteString("return nil\n}\n")
+}
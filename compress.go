@@ -0,0 +1,83 @@
+package rowboat
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Decompressor wraps a compressed stream in a reader of its contents
+type Decompressor func(io.Reader) (io.Reader, error)
+
+// compressionFormat describes a compression format recognized by its magic bytes
+type compressionFormat struct {
+	name  string
+	magic []byte
+	fn    Decompressor
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = []compressionFormat{
+		{name: "gzip", magic: []byte{0x1f, 0x8b}, fn: func(r io.Reader) (io.Reader, error) {
+			return gzip.NewReader(r)
+		}},
+		{name: "zstd", magic: []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	}
+)
+
+// RegisterDecompressor registers a decompressor for input starting with
+// magic, replacing any format registered under the same name. The standard
+// library has no zstd support, so zstd input is only decoded after
+// registering a decompressor for it, e.g. one based on
+// github.com/klauspost/compress/zstd.
+func RegisterDecompressor(name string, magic []byte, fn Decompressor) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	for i := range formats {
+		if formats[i].name == name {
+			formats[i] = compressionFormat{name: name, magic: magic, fn: fn}
+			return
+		}
+	}
+	formats = append(formats, compressionFormat{name: name, magic: magic, fn: fn})
+}
+
+// WithDecompression makes the Reader sniff the input for the magic bytes
+// of a registered compression format, such as gzip, and transparently
+// decompress it. Uncompressed input is read as is.
+func WithDecompression() Option {
+	return func(o *options) {
+		o.decompress = true
+	}
+}
+
+// WithGzip makes the Writer gzip its output. Call Writer.Close to flush
+// and terminate the compressed stream.
+func WithGzip() Option {
+	return func(o *options) {
+		o.gzip = true
+	}
+}
+
+// decompress detects the compression format of r and wraps it accordingly
+func decompress(r io.Reader) (io.Reader, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	br := bufio.NewReader(r)
+	for _, f := range formats {
+		head, _ := br.Peek(len(f.magic))
+		if len(f.magic) == 0 || !bytes.Equal(head, f.magic) {
+			continue
+		}
+		if f.fn == nil {
+			return nil, fmt.Errorf("%s input requires a registered decompressor", f.name)
+		}
+		return f.fn(br)
+	}
+	return br, nil
+}
@@ -0,0 +1,87 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestGzipRoundTrip(t *testing.T) {
+	people := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithGzip())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(people)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Fatalf("Expected gzip output")
+	}
+
+	rb, err := rowboat.NewReader[Person](&buf, rowboat.WithDecompression())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, people) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", people, results)
+	}
+}
+
+func TestDecompressionPassthrough(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithDecompression())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestRegisterDecompressor(t *testing.T) {
+	zstdData := append([]byte{0x28, 0xb5, 0x2f, 0xfd}, "Name,Email,Age\nAlice,alice@example.com,30\n"...)
+
+	if _, err := rowboat.NewReader[Person](bytes.NewReader(zstdData), rowboat.WithDecompression()); err == nil {
+		t.Fatalf("Expected error for zstd input without a registered decompressor")
+	}
+
+	// A fake decompressor that strips the magic bytes
+	rowboat.RegisterDecompressor("zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.Reader, error) {
+		if _, err := io.CopyN(io.Discard, r, 4); err != nil {
+			return nil, err
+		}
+		return r, nil
+	})
+	defer rowboat.RegisterDecompressor("zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, nil)
+
+	rb, err := rowboat.NewReader[Person](bytes.NewReader(zstdData), rowboat.WithDecompression())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
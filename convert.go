@@ -0,0 +1,86 @@
+package rowboat
+
+import (
+	"io"
+	"iter"
+)
+
+// ReadAs reads records of type Src from r and converts each one to Dst.
+// Decoding errors and conversion errors are both yielded as the second
+// element; decoding errors end the sequence while conversion errors are
+// reported as a *RowError and iteration continues with the next row.
+func ReadAs[Src, Dst any](r io.Reader, convert func(Src) (Dst, error), opts ...Option) iter.Seq2[Dst, error] {
+	return func(yield func(Dst, error) bool) {
+		var zero Dst
+		rb, err := NewReader[Src](r, opts...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		for src, err := range rb.rows() {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			dst, err := convert(src)
+			if err != nil {
+				if !yield(zero, &RowError{Line: rb.line, Err: err}) {
+					return
+				}
+				continue
+			}
+			if !yield(dst, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Copy streams the records of src to dst, writing the header first and
+// flushing dst when done, and returns the number of records written.
+// Transforms run in order on each record; one returning false drops it.
+// Since the Reader and Writer carry their own options, Copy also converts
+// between dialects, e.g. from semicolons to commas.
+func Copy[T any](dst *Writer[T], src *Reader[T], transforms ...func(T) (T, bool)) (int64, error) {
+	return stream(src, func(record T) (T, bool) {
+		keep := true
+		for _, transform := range transforms {
+			if record, keep = transform(record); !keep {
+				break
+			}
+		}
+		return record, keep
+	}, dst)
+}
+
+// Convert streams the records of src to dst like Copy, mapping each one to
+// the destination schema with fn. Records for which fn returns false are
+// dropped.
+func Convert[In, Out any](src *Reader[In], fn func(In) (Out, bool), dst *Writer[Out]) error {
+	_, err := stream(src, fn, dst)
+	return err
+}
+
+// stream writes the header of dst and the records of src mapped by fn, and
+// returns the number of records written
+func stream[In, Out any](src *Reader[In], fn func(In) (Out, bool), dst *Writer[Out]) (int64, error) {
+	if err := dst.WriteHeader(); err != nil {
+		return 0, err
+	}
+	var n int64
+	for record, err := range src.rows() {
+		if err != nil {
+			dst.Flush()
+			return n, err
+		}
+		out, keep := fn(record)
+		if !keep {
+			continue
+		}
+		if err := dst.Write(out); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, dst.Flush()
+}
@@ -0,0 +1,157 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Contact struct {
+	DisplayName string
+	Adult       bool
+}
+
+func TestReadAs(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+,nobody@example.com,40
+Bob,bob@example.com,15`
+
+	var results []Contact
+	var errs []error
+	for c, err := range rowboat.ReadAs(strings.NewReader(csvData), func(p Person) (Contact, error) {
+		if p.Name == "" {
+			return Contact{}, fmt.Errorf("missing name for %s", p.Email)
+		}
+		return Contact{DisplayName: p.Name + " <" + p.Email + ">", Adult: p.Age >= 18}, nil
+	}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, c)
+	}
+
+	expected := []Contact{
+		{DisplayName: "Alice <alice@example.com>", Adult: true},
+		{DisplayName: "Bob <bob@example.com>", Adult: false},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Converted results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(errs[0], &rowErr) || rowErr.Line != 3 {
+		t.Errorf("Expected RowError on line 3, got %v", errs[0])
+	}
+}
+
+func TestReadAsDecodeError(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,thirty
+Bob,bob@example.com,25`
+
+	var errs []error
+	count := 0
+	for _, err := range rowboat.ReadAs(strings.NewReader(csvData), func(p Person) (string, error) {
+		return p.Name, nil
+	}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count++
+	}
+
+	if count != 0 || len(errs) != 1 {
+		t.Fatalf("Expected decoding to stop at the first error, got %d records and errors %v", count, errs)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(errs[0], &rowErr) || rowErr.Column != "Age" {
+		t.Errorf("Expected RowError for column Age, got %v", errs[0])
+	}
+}
+
+func TestCopy(t *testing.T) {
+	input := "Name;Email;Age\nAlice;alice@example.com;30\nBob;bob@example.com;17\nCarol;carol@example.com;41\n"
+	src, err := rowboat.NewReader[Person](strings.NewReader(input), rowboat.WithDelimiter(';'))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	var buf bytes.Buffer
+	dst, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+
+	adults := func(p Person) (Person, bool) { return p, p.Age >= 18 }
+	upper := func(p Person) (Person, bool) {
+		p.Name = strings.ToUpper(p.Name)
+		return p, true
+	}
+	n, err := rowboat.Copy(dst, src, adults, upper)
+	if err != nil {
+		t.Fatalf("Failed to copy records: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected: %+v\nGot: %+v", 2, n)
+	}
+	expected := "Name,Email,Age\nALICE,alice@example.com,30\nCAROL,carol@example.com,41\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
+
+func TestCopyDecodeError(t *testing.T) {
+	input := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,old\n"
+	src, err := rowboat.NewReader[Person](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	var buf bytes.Buffer
+	dst, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	n, err := rowboat.Copy(dst, src)
+	if err == nil {
+		t.Fatalf("Expected a decoding error")
+	}
+	if n != 1 {
+		t.Errorf("Expected: %+v\nGot: %+v", 1, n)
+	}
+	if expected := "Name,Email,Age\nAlice,alice@example.com,30\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
+
+func TestConvert(t *testing.T) {
+	input := "Name,Email,Age\nAlice,alice@example.com,30\n,nobody@example.com,40\nBob,bob@example.com,15\n"
+	src, err := rowboat.NewReader[Person](strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	var buf bytes.Buffer
+	dst, err := rowboat.NewWriter[Contact](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	err = rowboat.Convert(src, func(p Person) (Contact, bool) {
+		return Contact{DisplayName: p.Name + " <" + p.Email + ">", Adult: p.Age >= 18}, p.Name != ""
+	}, dst)
+	if err != nil {
+		t.Fatalf("Failed to convert records: %v", err)
+	}
+	expected := "DisplayName,Adult\nAlice <alice@example.com>,true\nBob <bob@example.com>,false\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
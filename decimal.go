@@ -0,0 +1,213 @@
+package rowboat
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number for monetary columns, which lose
+// precision when parsed as float64. It holds an unscaled integer and the
+// number of digits after the decimal point, so "19.90" is 1990 at scale 2.
+// The zero value is 0.
+//
+// Decimal fields honor the currency and locale tags, WithStripCurrency and
+// WithLocale on read, and the prec tag on write.
+type Decimal struct {
+	unscaled int64
+	scale    int32
+}
+
+// decimalType is the reflect.Type of Decimal
+var decimalType = reflect.TypeOf(Decimal{})
+
+// NewDecimal returns unscaled * 10^-scale, e.g. NewDecimal(1990, 2) is 19.90
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	if scale < 0 {
+		panic("rowboat: negative decimal scale")
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// ParseDecimal parses a decimal number such as "-1234.50". The scale is
+// the number of digits after the decimal point, so trailing zeros are kept.
+// Exponents, thousands separators and currency symbols are not accepted.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := s
+	neg := false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		neg = digits[0] == '-'
+		digits = digits[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if (intPart == "" && fracPart == "") || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	u, err := strconv.ParseUint(intPart+fracPart, 10, 64)
+	if err != nil || u > math.MaxInt64 && !(neg && u == math.MaxInt64+1) {
+		return Decimal{}, fmt.Errorf("decimal %q out of range", s)
+	}
+	d := Decimal{unscaled: int64(u), scale: int32(len(fracPart))}
+	if neg {
+		d.unscaled = -d.unscaled
+	}
+	return d, nil
+}
+
+// isDigits reports whether s holds only ASCII digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Unscaled returns the integer value of d without its decimal point
+func (d Decimal) Unscaled() int64 {
+	return d.unscaled
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	switch {
+	case d.unscaled < 0:
+		return -1
+	case d.unscaled > 0:
+		return 1
+	}
+	return 0
+}
+
+// Cmp compares d and e by value, so 1.5 and 1.50 are equal. It returns -1
+// if d < e, 0 if d == e and +1 if d > e.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.rat().Cmp(e.rat())
+}
+
+// rat returns d as an exact rational number
+func (d Decimal) rat() *big.Rat {
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale)), nil)
+	return new(big.Rat).SetFrac(big.NewInt(d.unscaled), denom)
+}
+
+// Float64 returns the float64 nearest to d
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String formats d with all digits of its scale, e.g. "-19.90"
+func (d Decimal) String() string {
+	s := strconv.FormatUint(absUint(d.unscaled), 10)
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(s); pad > 0 {
+			s = strings.Repeat("0", pad) + s
+		}
+		s = s[:len(s)-int(d.scale)] + "." + s[len(s)-int(d.scale):]
+	}
+	if d.unscaled < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// StringFixed formats d with exactly places digits after the decimal point,
+// rounding half away from zero or padding with zeros. Negative places are
+// treated as zero.
+func (d Decimal) StringFixed(places int32) string {
+	places = max(places, 0)
+	if places >= d.scale {
+		s := d.String()
+		if places > d.scale {
+			if d.scale == 0 {
+				s += "."
+			}
+			s += strings.Repeat("0", int(places-d.scale))
+		}
+		return s
+	}
+	// Round with big integers, since rounding up may overflow int64
+	div := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale-places)), nil)
+	q, r := new(big.Int).QuoRem(big.NewInt(d.unscaled), div, new(big.Int))
+	if new(big.Int).Abs(r).Cmp(new(big.Int).Rsh(div, 1)) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+	s := q.String()
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if places > 0 {
+		if pad := int(places) + 1 - len(s); pad > 0 {
+			s = strings.Repeat("0", pad) + s
+		}
+		s = s[:len(s)-int(places)] + "." + s[len(s)-int(places):]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// absUint returns the absolute value of n, which fits in uint64 even for
+// math.MinInt64
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
+
+// MarshalCSV implements CSVMarshaler
+func (d Decimal) MarshalCSV() (string, error) {
+	return d.String(), nil
+}
+
+// UnmarshalCSV implements CSVUnmarshaler
+func (d *Decimal) UnmarshalCSV(s string) error {
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value implements driver.Valuer, passing d to databases as its exact
+// decimal text
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// decodeDecimal parses a Decimal cell after stripping currency symbols and
+// applying the field's locale
+func decodeDecimal(field reflect.Value, value string, fi fieldInfo, opts *options) error {
+	d, err := ParseDecimal(strings.TrimSpace(fi.normalizeNumber(value, opts)))
+	if err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(d))
+	return nil
+}
+
+// encodeDecimal formats a Decimal cell with the field's prec and locale
+func encodeDecimal(field reflect.Value, fi fieldInfo) string {
+	d := field.Interface().(Decimal)
+	s := d.String()
+	if fi.Prec >= 0 {
+		s = d.StringFixed(int32(fi.Prec))
+	}
+	if fi.Locale != nil {
+		s = fi.Locale.formatNumber(s)
+	}
+	return s
+}
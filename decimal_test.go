@@ -0,0 +1,116 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type LedgerEntry struct {
+	Account string           `csv:"account"`
+	Amount  rowboat.Decimal  `csv:"amount,currency"`
+	Local   rowboat.Decimal  `csv:"local,currency,locale=de"`
+	Fee     *rowboat.Decimal `csv:"fee,prec=2"`
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		unscaled int64
+		scale    int32
+		str      string
+	}{
+		{"19.90", 1990, 2, "19.90"},
+		{"-0.05", -5, 2, "-0.05"},
+		{"+7", 7, 0, "7"},
+		{".5", 5, 1, "0.5"},
+		{"9223372036854775.807", 9223372036854775807, 3, "9223372036854775.807"},
+		{"-9223372036854775808", -9223372036854775808, 0, "-9223372036854775808"},
+	}
+	for _, tt := range tests {
+		d, err := rowboat.ParseDecimal(tt.input)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.input, err)
+		}
+		if d.Unscaled() != tt.unscaled || d.Scale() != tt.scale || d.String() != tt.str {
+			t.Errorf("Expected: %d %d %s\nGot: %d %d %s", tt.unscaled, tt.scale, tt.str, d.Unscaled(), d.Scale(), d)
+		}
+	}
+
+	for _, input := range []string{"", ".", "1.2.3", "1e5", "1,000", "$5", "9223372036854775808"} {
+		if _, err := rowboat.ParseDecimal(input); err == nil {
+			t.Errorf("Expected an error parsing %q", input)
+		}
+	}
+}
+
+func TestDecimalStringFixed(t *testing.T) {
+	tests := []struct {
+		d      rowboat.Decimal
+		places int32
+		str    string
+	}{
+		{rowboat.NewDecimal(12345, 3), 2, "12.35"},
+		{rowboat.NewDecimal(-12345, 3), 2, "-12.35"},
+		{rowboat.NewDecimal(12344, 3), 2, "12.34"},
+		{rowboat.NewDecimal(-4, 2), 1, "0.0"},
+		{rowboat.NewDecimal(5, 1), 0, "1"},
+		{rowboat.NewDecimal(15, 1), 3, "1.500"},
+		{rowboat.NewDecimal(7, 0), 2, "7.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.StringFixed(tt.places); got != tt.str {
+			t.Errorf("Expected: %s\nGot: %s", tt.str, got)
+		}
+	}
+
+	if rowboat.NewDecimal(15, 1).Cmp(rowboat.NewDecimal(150, 2)) != 0 {
+		t.Errorf("Expected 1.5 and 1.50 to be equal")
+	}
+	if rowboat.NewDecimal(-1, 0).Cmp(rowboat.NewDecimal(1, 3)) != -1 {
+		t.Errorf("Expected -1 to be less than 0.001")
+	}
+}
+
+func TestDecimalFields(t *testing.T) {
+	csvData := `account,amount,local,fee
+cash,$1234567890123.45,"1.234,50 €",0.125
+bank,-€0.10,"0,01",`
+
+	records, err := collect(mustReader[LedgerEntry](t, csvData))
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	fee := rowboat.NewDecimal(125, 3)
+	expected := []LedgerEntry{
+		{Account: "cash", Amount: rowboat.NewDecimal(123456789012345, 2), Local: rowboat.NewDecimal(123450, 2), Fee: &fee},
+		{Account: "bank", Amount: rowboat.NewDecimal(-10, 2), Local: rowboat.NewDecimal(1, 2)},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("Expected: %+v\nGot: %+v", expected, records)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[LedgerEntry](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(records); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	writer.Close()
+	expectedCSV := "account,amount,local,fee\ncash,1234567890123.45,\"1234,50\",0.13\nbank,-0.10,\"0,01\",\n"
+	if buf.String() != expectedCSV {
+		t.Errorf("Expected: %q\nGot: %q", expectedCSV, buf.String())
+	}
+
+	if _, err := collect(mustReader[LedgerEntry](t, "account,amount,local,fee\ncash,1.2.3,0,0\n")); err == nil || !strings.Contains(err.Error(), "invalid decimal") {
+		t.Errorf("Expected an invalid decimal error, got %v", err)
+	}
+}
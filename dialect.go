@@ -0,0 +1,49 @@
+package rowboat
+
+// Dialect describes the format of a CSV file. It packages the format
+// options in one value that can be shared by Readers and Writers with
+// WithDialect. Quotes are always '"' and are escaped by doubling them, as
+// in encoding/csv.
+type Dialect struct {
+	Delimiter        rune        // field delimiter; zero means a comma
+	LazyQuotes       bool        // quotes appear in unquoted fields
+	UseCRLF          bool        // lines end with \r\n
+	Quoting          QuotePolicy // fields the Writer quotes
+	Comment          rune        // lines starting with it are skipped when reading; zero for none
+	TrimLeadingSpace bool        // leading white space in fields is ignored when reading
+}
+
+// Dialect presets for common producers and consumers of CSV files
+var (
+	// DialectExcel is the comma-separated format Excel saves, with \r\n
+	// line endings. Excel opens files with stray quotes, so reading
+	// accepts them too.
+	DialectExcel = Dialect{Delimiter: ',', LazyQuotes: true, UseCRLF: true}
+
+	// DialectExcelSemicolon is the format Excel saves in locales using a
+	// decimal comma
+	DialectExcelSemicolon = Dialect{Delimiter: ';', LazyQuotes: true, UseCRLF: true}
+
+	// DialectTSV is tab-separated with lenient quote handling, like TSV
+	DialectTSV = Dialect{Delimiter: '\t', LazyQuotes: true}
+
+	// DialectPostgres is the format of Postgres COPY with FORMAT csv
+	DialectPostgres = Dialect{Delimiter: ','}
+
+	// DialectRFC4180 follows RFC 4180 strictly: \r\n line endings, and
+	// quotes only around whole fields
+	DialectRFC4180 = Dialect{Delimiter: ',', UseCRLF: true}
+)
+
+// WithDialect sets all format options from d, replacing earlier options
+// such as WithDelimiter, WithLazyQuotes and WithQuoting
+func WithDialect(d Dialect) Option {
+	return func(o *options) {
+		o.delimiter = d.Delimiter
+		o.lazyQuotes = d.LazyQuotes
+		o.useCRLF = d.UseCRLF
+		o.quoting = d.Quoting
+		o.comment = d.Comment
+		o.trimLeading = d.TrimLeadingSpace
+	}
+}
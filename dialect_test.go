@@ -0,0 +1,90 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestDialectRoundTrip(t *testing.T) {
+	people := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob; \"Bobby\"", Email: "bob@example.com", Age: 25},
+	}
+	tests := []struct {
+		name     string
+		dialect  rowboat.Dialect
+		expected string
+	}{
+		{"excel", rowboat.DialectExcel, "Name,Email,Age\r\nAlice,alice@example.com,30\r\n\"Bob; \"\"Bobby\"\"\",bob@example.com,25\r\n"},
+		{"excel semicolon", rowboat.DialectExcelSemicolon, "Name;Email;Age\r\nAlice;alice@example.com;30\r\n\"Bob; \"\"Bobby\"\"\";bob@example.com;25\r\n"},
+		{"tsv", rowboat.DialectTSV, "Name\tEmail\tAge\nAlice\talice@example.com\t30\n\"Bob; \"\"Bobby\"\"\"\tbob@example.com\t25\n"},
+		{"postgres", rowboat.DialectPostgres, "Name,Email,Age\nAlice,alice@example.com,30\n\"Bob; \"\"Bobby\"\"\",bob@example.com,25\n"},
+		{"quote all", rowboat.Dialect{UseCRLF: true, Quoting: rowboat.QuoteAll}, "\"Name\",\"Email\",\"Age\"\r\n\"Alice\",\"alice@example.com\",\"30\"\r\n\"Bob; \"\"Bobby\"\"\",\"bob@example.com\",\"25\"\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := rowboat.NewWriter[Person](&buf, rowboat.WithDialect(tt.dialect))
+			if err != nil {
+				t.Fatalf("Failed to create Writer: %v", err)
+			}
+			if err := w.WriteHeader(); err != nil {
+				t.Fatalf("Failed to write header: %v", err)
+			}
+			if err := w.WriteSlice(people); err != nil {
+				t.Fatalf("Failed to write records: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Fatalf("Expected: %q\nGot: %q", tt.expected, buf.String())
+			}
+
+			rb, err := rowboat.NewReader[Person](&buf, rowboat.WithDialect(tt.dialect))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			got, err := rb.ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to read records: %v", err)
+			}
+			if !reflect.DeepEqual(got, people) {
+				t.Errorf("Expected: %+v\nGot: %+v", people, got)
+			}
+		})
+	}
+}
+
+func TestDialectReading(t *testing.T) {
+	csvData := "Name,Email,Age\r\nBob,\"6'2\" tall\",25\r\n"
+	if _, err := readDialect(csvData, rowboat.DialectRFC4180); err == nil {
+		t.Errorf("Expected an error for a bare quote in the RFC 4180 dialect")
+	}
+	got, err := readDialect(csvData, rowboat.DialectExcel)
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if expected := `6'2" tall`; len(got) != 1 || got[0].Email != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	csvData = "Name, Email, Age\n# exported 2024-01-01\nAlice, alice@example.com, 30\n"
+	got, err = readDialect(csvData, rowboat.Dialect{Comment: '#', TrimLeadingSpace: true})
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func readDialect(csvData string, d rowboat.Dialect) ([]Person, error) {
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithDialect(d))
+	if err != nil {
+		return nil, err
+	}
+	return rb.ReadAll()
+}
@@ -0,0 +1,119 @@
+package rowboat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// Record is a row decoded by a Dispatcher
+type Record struct {
+	Kind  string // value of the discriminator column
+	Line  int    // line in the input where the row starts
+	Value any    // decoded struct registered for Kind
+}
+
+// Dispatcher reads files in which a discriminator column determines the
+// layout of each row, such as files mixing header ("H"), detail ("D") and
+// trailer ("T") records. Rows are mapped to the struct registered for their
+// kind by position: struct fields fill columns in their CSV order, so the
+// discriminator column is usually a field of each struct as well. Files
+// read by a Dispatcher have no header row.
+type Dispatcher struct {
+	reader   *csv.Reader
+	opts     []Option
+	column   int
+	decoders map[string]func(record []string, line int) (any, error)
+}
+
+// NewDispatcher creates a Dispatcher reading r and selecting each row's
+// layout by the value in the given column
+func NewDispatcher(r io.Reader, column int, opts ...Option) (*Dispatcher, error) {
+	o := newOptions(opts)
+	r, err := o.wrapInput(r)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dispatcher{
+		reader:   csv.NewReader(r),
+		opts:     opts,
+		column:   column,
+		decoders: make(map[string]func([]string, int) (any, error)),
+	}
+	o.configureReader(d.reader)
+	d.reader.FieldsPerRecord = -1 // record kinds have different lengths
+	return d, nil
+}
+
+// Handle registers T as the layout of rows whose discriminator equals kind.
+// Options passed here apply after the Dispatcher's own.
+func Handle[T any](d *Dispatcher, kind string, opts ...Option) error {
+	rb, err := newPositionalReader[T](append(slices.Clip(d.opts), opts...))
+	if err != nil {
+		return err
+	}
+	if err := rb.checkFields(); err != nil {
+		return err
+	}
+	d.decoders[kind] = func(record []string, line int) (any, error) {
+		rb.line = line
+		return rb.decodeRecord(record)
+	}
+	return nil
+}
+
+// All returns an iterator over all rows, decoded into the struct registered
+// for their kind. Iteration stops after yielding the first error, including
+// rows of an unregistered kind.
+func (d *Dispatcher) All() iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		for {
+			record, err := d.reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(Record{}, err)
+				return
+			}
+			line, _ := d.reader.FieldPos(0)
+
+			if d.column >= len(record) {
+				yield(Record{}, &RowError{Line: line, Err: fmt.Errorf("missing discriminator column %d", d.column)})
+				return
+			}
+			kind := record[d.column]
+			decode, ok := d.decoders[kind]
+			if !ok {
+				yield(Record{}, &RowError{Line: line, Err: fmt.Errorf("no handler for record kind '%s'", kind)})
+				return
+			}
+			v, err := decode(record, line)
+			if err != nil {
+				yield(Record{}, err)
+				return
+			}
+			if !yield(Record{Kind: kind, Line: line, Value: v}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// newPositionalReader creates a Reader without input whose fields map to
+// the columns at their index, for decoding records obtained elsewhere
+func newPositionalReader[T any](opts []Option) (*Reader[T], error) {
+	rb, err := newReader[T](opts)
+	if err != nil {
+		return nil, err
+	}
+	rb.fieldMap = make(map[int]*fieldInfo, len(rb.fields))
+	for i := range rb.fields {
+		if rb.selected(rb.fields[i]) {
+			rb.fieldMap[rb.fields[i].Index] = &rb.fields[i]
+		}
+	}
+	return rb, nil
+}
@@ -0,0 +1,79 @@
+package rowboat_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+type BatchHeader struct {
+	Kind    string    `csv:"kind"`
+	BatchID string    `csv:"batch_id"`
+	Created time.Time `csv:"created"`
+}
+
+type BatchDetail struct {
+	Kind    string  `csv:"kind"`
+	Account string  `csv:"account"`
+	Amount  float64 `csv:"amount"`
+	Memo    string  `csv:"memo"`
+}
+
+type BatchTrailer struct {
+	Kind  string `csv:"kind"`
+	Count int    `csv:"count"`
+}
+
+func TestDispatcher(t *testing.T) {
+	csvData := `H,B001,2024-03-01T00:00:00Z
+D,ACC-1,100.50,rent
+D,ACC-2,20,
+T,2`
+
+	d, err := rowboat.NewDispatcher(strings.NewReader(csvData), 0)
+	if err != nil {
+		t.Fatalf("Failed to create Dispatcher: %v", err)
+	}
+	if err := rowboat.Handle[BatchHeader](d, "H"); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+	if err := rowboat.Handle[BatchDetail](d, "D"); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+	if err := rowboat.Handle[BatchTrailer](d, "T"); err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+
+	var values []any
+	for rec, err := range d.All() {
+		if err != nil {
+			t.Fatalf("Failed to read record: %v", err)
+		}
+		values = append(values, rec.Value)
+	}
+
+	expected := []any{
+		BatchHeader{Kind: "H", BatchID: "B001", Created: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		BatchDetail{Kind: "D", Account: "ACC-1", Amount: 100.5, Memo: "rent"},
+		BatchDetail{Kind: "D", Account: "ACC-2", Amount: 20},
+		BatchTrailer{Kind: "T", Count: 2},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Dispatched results do not match expected.\nExpected: %+v\nGot: %+v", expected, values)
+	}
+}
+
+func TestDispatcherUnknownKind(t *testing.T) {
+	d, err := rowboat.NewDispatcher(strings.NewReader("X,1\n"), 0)
+	if err != nil {
+		t.Fatalf("Failed to create Dispatcher: %v", err)
+	}
+	for _, err := range d.All() {
+		if err == nil {
+			t.Errorf("Expected error for unregistered record kind")
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package rowboat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnChange describes a column whose type differs from the baseline
+type ColumnChange struct {
+	Name string
+	From Column
+	To   Column
+}
+
+// DriftReport lists the differences between a baseline schema and the
+// schema of a newer file
+type DriftReport struct {
+	Added   []Column       // columns missing from the baseline
+	Removed []Column       // baseline columns missing from the file
+	Retyped []ColumnChange // columns whose type or time layout changed
+}
+
+// HasDrift reports whether the schemas differ
+func (d DriftReport) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Retyped) > 0
+}
+
+// String summarizes the report in a single line
+func (d DriftReport) String() string {
+	if !d.HasDrift() {
+		return "no schema drift"
+	}
+	var parts []string
+	for _, c := range d.Added {
+		parts = append(parts, fmt.Sprintf("added %s (%s)", c.Name, c.typeToken()))
+	}
+	for _, c := range d.Removed {
+		parts = append(parts, fmt.Sprintf("removed %s", c.Name))
+	}
+	for _, c := range d.Retyped {
+		parts = append(parts, fmt.Sprintf("retyped %s from %s to %s", c.Name, c.From.typeToken(), c.To.typeToken()))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// CompareSchema compares the columns of a file against a baseline. Columns
+// are matched by name, so reordering alone is not reported as drift.
+func CompareSchema(baseline, current []Column) DriftReport {
+	var report DriftReport
+	base := make(map[string]Column, len(baseline))
+	for _, c := range baseline {
+		base[c.Name] = c
+	}
+	seen := make(map[string]bool, len(current))
+	for _, c := range current {
+		seen[c.Name] = true
+		old, ok := base[c.Name]
+		switch {
+		case !ok:
+			report.Added = append(report.Added, c)
+		case old.typeToken() != c.typeToken():
+			report.Retyped = append(report.Retyped, ColumnChange{Name: c.Name, From: old, To: c})
+		}
+	}
+	for _, c := range baseline {
+		if !seen[c.Name] {
+			report.Removed = append(report.Removed, c)
+		}
+	}
+	return report
+}
+
+// DetectDrift reads the header of a CSV file, determines its column types
+// and compares them against a baseline. Files carrying a type row (see
+// WithTypeRow) use the declared types; otherwise the types are inferred
+// from the data rows.
+func DetectDrift(r io.Reader, baseline []Column, opts ...Option) (DriftReport, error) {
+	columns, err := ScanColumns(r, opts...)
+	if err != nil {
+		return DriftReport{}, err
+	}
+	return CompareSchema(baseline, columns), nil
+}
+
+// ScanColumns reads a CSV file and returns its columns. With WithTypeRow the
+// types are taken from the type row; otherwise each column gets the most
+// specific type (int, float, bool, time or string) that parses all of its
+// non-empty cells. WithMaxRows limits how many rows are sampled.
+func ScanColumns(r io.Reader, opts ...Option) ([]Column, error) {
+	o := newOptions(opts)
+	r, err := o.wrapInput(r)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(r)
+	o.configureReader(reader)
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]Column, len(headers))
+	for i, header := range headers {
+		columns[i].Name = strings.TrimSpace(header)
+	}
+
+	if o.typeRow {
+		types, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("error reading type row: %w", err)
+		}
+		if err := checkTypeRow(headers, types); err != nil {
+			return nil, err
+		}
+		for i := range columns {
+			if columns[i].Type, columns[i].Layout, err = parseTypeToken(types[i]); err != nil {
+				return nil, fmt.Errorf("column '%s': %w", columns[i].Name, err)
+			}
+		}
+		return columns, nil
+	}
+
+	layout := time.RFC3339
+	if o.timeLayout != "" {
+		layout = o.timeLayout
+	}
+	if err := inferColumns(reader, columns, o.maxRows, []string{layout}); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// inferColumns sets the types of columns from up to maxRows records of
+// reader, or all of them when maxRows is not positive. Time columns get the
+// first of layouts parsing all their cells. Cells beyond the columns of
+// long rows are ignored.
+func inferColumns(reader *csv.Reader, columns []Column, maxRows int, layouts []string) error {
+	inferers := make([]*typeInferer, len(columns))
+	for i := range inferers {
+		inferers[i] = newTypeInferer(layouts)
+	}
+	for rows := 0; maxRows <= 0 || rows < maxRows; rows++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for i, value := range record[:min(len(record), len(inferers))] {
+			inferers[i].observe(value)
+		}
+	}
+	for i := range columns {
+		columns[i].Type = inferers[i].result()
+		if columns[i].Type == Time {
+			columns[i].Layout = inferers[i].layouts[0]
+		}
+	}
+	return nil
+}
+
+// typeInferer narrows down the candidate types of a column as cells are
+// observed
+type typeInferer struct {
+	layouts    []string // time layouts parsing every cell so far
+	seen       bool
+	candidates map[ColumnType]bool
+}
+
+// newTypeInferer creates an inferer considering every column type and the
+// given time layouts
+func newTypeInferer(layouts []string) *typeInferer {
+	return &typeInferer{
+		layouts:    slices.Clone(layouts),
+		candidates: map[ColumnType]bool{Int: true, Float: true, Bool: true, Time: true},
+	}
+}
+
+// observe removes the candidate types that cannot parse value. Empty cells
+// carry no type information.
+func (ti *typeInferer) observe(value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	ti.seen = true
+	if ti.candidates[Int] {
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			delete(ti.candidates, Int)
+		}
+	}
+	if ti.candidates[Float] {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			delete(ti.candidates, Float)
+		}
+	}
+	if ti.candidates[Bool] {
+		if _, err := strconv.ParseBool(value); err != nil {
+			delete(ti.candidates, Bool)
+		}
+	}
+	if ti.candidates[Time] {
+		ti.layouts = slices.DeleteFunc(ti.layouts, func(layout string) bool {
+			_, err := time.Parse(layout, value)
+			return err != nil
+		})
+		if len(ti.layouts) == 0 {
+			delete(ti.candidates, Time)
+		}
+	}
+}
+
+// result returns the most specific remaining type, or String when the
+// column only held empty cells or no type parsed every cell
+func (ti *typeInferer) result() ColumnType {
+	if !ti.seen {
+		return String
+	}
+	for _, t := range []ColumnType{Int, Float, Bool, Time} {
+		if ti.candidates[t] {
+			return t
+		}
+	}
+	return String
+}
+
+// MarshalText encodes the type as its type row name, so that schemas can be
+// stored as JSON baselines
+func (t ColumnType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a type row name
+func (t *ColumnType) UnmarshalText(text []byte) error {
+	parsed, _, err := parseTypeToken(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
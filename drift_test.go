@@ -0,0 +1,102 @@
+package rowboat_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestScanColumns(t *testing.T) {
+	csvData := `id,name,score,active,created_at,note
+1,Alice,9.5,true,2023-01-02T15:04:05Z,
+2,Bob,7,false,2023-01-03T15:04:05Z,
+`
+	columns, err := rowboat.ScanColumns(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to scan columns: %v", err)
+	}
+	expected := []rowboat.Column{
+		{Name: "id", Type: rowboat.Int},
+		{Name: "name", Type: rowboat.String},
+		{Name: "score", Type: rowboat.Float},
+		{Name: "active", Type: rowboat.Bool},
+		{Name: "created_at", Type: rowboat.Time, Layout: time.RFC3339},
+		{Name: "note", Type: rowboat.String},
+	}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Columns do not match expected.\nExpected: %+v\nGot: %+v", expected, columns)
+	}
+}
+
+func TestScanColumnsVariableLength(t *testing.T) {
+	// Cells beyond the header are ignored
+	columns, err := rowboat.ScanColumns(strings.NewReader("a,b\n1,2,3\nx\n"), rowboat.WithFieldsPerRecord(-1))
+	if err != nil {
+		t.Fatalf("Failed to scan columns: %v", err)
+	}
+	expected := []rowboat.Column{{Name: "a", Type: rowboat.String}, {Name: "b", Type: rowboat.Int}}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, columns)
+	}
+
+	// A short type row is rejected
+	_, err = rowboat.ScanColumns(strings.NewReader("a,b\nint\n1,2\n"), rowboat.WithTypeRow(), rowboat.WithFieldsPerRecord(-1))
+	var countErr *rowboat.FieldCountError
+	if !errors.As(err, &countErr) || !reflect.DeepEqual(countErr.Missing, []string{"b"}) {
+		t.Errorf("Expected a FieldCountError missing b, got %v", err)
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	baseline := []rowboat.Column{
+		{Name: "id", Type: rowboat.Int},
+		{Name: "name", Type: rowboat.String},
+		{Name: "amount", Type: rowboat.Int},
+	}
+
+	// Baselines round-trip through JSON
+	data, err := json.Marshal(baseline)
+	if err != nil {
+		t.Fatalf("Failed to marshal baseline: %v", err)
+	}
+	var stored []rowboat.Column
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Failed to unmarshal baseline: %v", err)
+	}
+	if !reflect.DeepEqual(stored, baseline) {
+		t.Fatalf("Expected: %+v\nGot: %+v", baseline, stored)
+	}
+
+	csvData := "name,id,amount,currency\nAlice,1,9.99,EUR\n"
+	report, err := rowboat.DetectDrift(strings.NewReader(csvData), stored)
+	if err != nil {
+		t.Fatalf("Failed to detect drift: %v", err)
+	}
+	expected := rowboat.DriftReport{
+		Added: []rowboat.Column{{Name: "currency", Type: rowboat.String}},
+		Retyped: []rowboat.ColumnChange{{
+			Name: "amount",
+			From: rowboat.Column{Name: "amount", Type: rowboat.Int},
+			To:   rowboat.Column{Name: "amount", Type: rowboat.Float},
+		}},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Report does not match expected.\nExpected: %+v\nGot: %+v", expected, report)
+	}
+	if want := "added currency (string); retyped amount from int to float"; report.String() != want {
+		t.Errorf("Expected: %s\nGot: %s", want, report.String())
+	}
+
+	report = rowboat.CompareSchema(baseline, baseline[:2])
+	if len(report.Removed) != 1 || report.Removed[0].Name != "amount" {
+		t.Errorf("Expected amount to be removed, got %+v", report)
+	}
+	if rowboat.CompareSchema(baseline, baseline).HasDrift() {
+		t.Errorf("Expected no drift for identical schemas")
+	}
+}
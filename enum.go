@@ -0,0 +1,82 @@
+package rowboat
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// enumMapping maps the CSV text of a registered enum type to its constants
+type enumMapping struct {
+	values map[string]reflect.Value
+	names  map[any]string
+	text   []string // sorted CSV values, for error messages
+}
+
+// enumRegistry holds the mappings registered with RegisterEnum
+var enumRegistry sync.Map // map[reflect.Type]*enumMapping
+
+// RegisterEnum maps the CSV values of an enumeration to Go constants of
+// type E. Fields of type E are then decoded from and encoded to these
+// values, and any other value is an error. Registering E again replaces
+// its mapping; each constant must have a single CSV value.
+func RegisterEnum[E comparable](values map[string]E) error {
+	m := &enumMapping{
+		values: make(map[string]reflect.Value, len(values)),
+		names:  make(map[any]string, len(values)),
+	}
+	for text, v := range values {
+		if other, ok := m.names[v]; ok {
+			return fmt.Errorf("enum value %v is mapped from both '%s' and '%s'", v, other, text)
+		}
+		m.values[text] = reflect.ValueOf(v)
+		m.names[v] = text
+		m.text = append(m.text, text)
+	}
+	slices.Sort(m.text)
+	enumRegistry.Store(reflect.TypeFor[E](), m)
+	return nil
+}
+
+// lookupEnum returns the mapping registered for t, or nil
+func lookupEnum(t reflect.Type) *enumMapping {
+	if m, ok := enumRegistry.Load(t); ok {
+		return m.(*enumMapping)
+	}
+	return nil
+}
+
+// decode stores the constant for value in field
+func (m *enumMapping) decode(field reflect.Value, value string) error {
+	v, ok := m.values[value]
+	if !ok {
+		return fmt.Errorf("value '%s' is not one of %s", value, strings.Join(m.text, "|"))
+	}
+	field.Set(v)
+	return nil
+}
+
+// encode returns the CSV value of the constant in field
+func (m *enumMapping) encode(field reflect.Value) (string, error) {
+	text, ok := m.names[field.Interface()]
+	if !ok {
+		return "", fmt.Errorf("value %v has no registered enum name", field.Interface())
+	}
+	return text, nil
+}
+
+// checkEnum reports an error if a cell is not among the values allowed by
+// the field's enum tag option. Empty omitted cells and nulls are allowed.
+func (fi fieldInfo) checkEnum(value string, opts *options) error {
+	switch {
+	case len(fi.Enum) == 0 || slices.Contains(fi.Enum, value):
+		return nil
+	case value == "" && fi.omitEmpty(opts):
+		return nil
+	case opts.hasNull && value == opts.nullString:
+		return nil
+	}
+	return fmt.Errorf("value '%s' is not one of %s", value, strings.Join(fi.Enum, "|"))
+}
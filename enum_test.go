@@ -0,0 +1,190 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Subscription struct {
+	ID     int    `csv:"id"`
+	Status string `csv:"status,enum=active|inactive|pending"`
+}
+
+func TestEnumTag(t *testing.T) {
+	rb, err := rowboat.NewReader[Subscription](strings.NewReader("id,status\n1,active\n2,paused\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err == nil || !strings.Contains(err.Error(), "not one of active|inactive|pending") {
+		t.Errorf("Expected an enum error, got %v", err)
+	}
+	expected := []Subscription{{ID: 1, Status: "active"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	writer, err := rowboat.NewWriter[Subscription](&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(Subscription{ID: 1, Status: "pending"}); err != nil {
+		t.Errorf("Failed to write record: %v", err)
+	}
+	if err := writer.Write(Subscription{ID: 2, Status: "paused"}); err == nil {
+		t.Errorf("Expected an error writing a value outside the enum")
+	}
+}
+
+func TestEnumTagNil(t *testing.T) {
+	type Plan struct {
+		ID   int     `csv:"id"`
+		Tier *string `csv:"tier,enum=free|pro"`
+	}
+	got, err := mustReader[Plan](t, "id,tier\n1,\n").ReadAll()
+	if err != nil || len(got) != 1 || got[0].Tier != nil {
+		t.Fatalf("Expected a nil tier, got %+v, %v", got, err)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Plan](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(got); err != nil {
+		t.Fatalf("Failed to write a nil enum field: %v", err)
+	}
+	if expected := "1,\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
+
+type Priority int
+
+const (
+	Low Priority = iota + 1
+	High
+)
+
+type Ticket struct {
+	ID       int      `csv:"id"`
+	Priority Priority `csv:"priority"`
+}
+
+func TestRegisterEnum(t *testing.T) {
+	if err := rowboat.RegisterEnum(map[string]Priority{"low": Low, "high": High}); err != nil {
+		t.Fatalf("Failed to register enum: %v", err)
+	}
+
+	rb, err := rowboat.NewReader[Ticket](strings.NewReader("id,priority\n1,high\n2,low\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Ticket{{1, High}, {2, Low}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Ticket](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(got); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "1,high\n2,low\n"; buf.String() != want {
+		t.Errorf("Expected: %+v\nGot: %+v", want, buf.String())
+	}
+	if err := writer.Write(Ticket{ID: 3, Priority: 7}); err == nil {
+		t.Errorf("Expected an error for an unregistered constant")
+	}
+
+	rb, err = rowboat.NewReader[Ticket](strings.NewReader("id,priority\n1,urgent\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := rb.ReadAll(); err == nil {
+		t.Errorf("Expected an error for an unknown enum value")
+	}
+
+	if err := rowboat.RegisterEnum(map[string]Priority{"low": Low, "minor": Low}); err == nil {
+		t.Errorf("Expected an error for a constant with two names")
+	}
+}
+
+func TestRegisterEnumJSONLines(t *testing.T) {
+	if err := rowboat.RegisterEnum(map[string]Priority{"low": Low, "high": High}); err != nil {
+		t.Fatalf("Failed to register enum: %v", err)
+	}
+
+	records := []Ticket{{1, High}, {2, Low}}
+	var buf bytes.Buffer
+	if err := rowboat.ToJSONLines(slices.Values(records), &buf); err != nil {
+		t.Fatalf("Failed to write JSON lines: %v", err)
+	}
+	expected := `{"id":1,"priority":"high"}` + "\n" + `{"id":2,"priority":"low"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+	}
+
+	var got []Ticket
+	for record, err := range rowboat.FromJSONLines[Ticket](&buf) {
+		if err != nil {
+			t.Fatalf("Failed to read JSON lines: %v", err)
+		}
+		got = append(got, record)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("Expected: %+v\nGot: %+v", records, got)
+	}
+}
+
+func TestRegisterEnumTypeRow(t *testing.T) {
+	if err := rowboat.RegisterEnum(map[string]Priority{"low": Low, "high": High}); err != nil {
+		t.Fatalf("Failed to register enum: %v", err)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Ticket](&buf, rowboat.WithTypeRow())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(Ticket{1, High}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+	if expected := "id,priority\nint,string\n1,high\n"; buf.String() != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+	}
+
+	dr, err := rowboat.NewDynamicReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to create DynamicReader: %v", err)
+	}
+	var rows []map[string]any
+	for row, err := range dr.All() {
+		if err != nil {
+			t.Fatalf("Failed to read row: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	expected := []map[string]any{{"id": int64(1), "priority": "high"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, rows)
+	}
+}
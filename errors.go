@@ -0,0 +1,82 @@
+package rowboat
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RowError describes a failure to process a single CSV row
+type RowError struct {
+	Line   int      // line in the input where the row starts
+	Column string   // column name, empty if the error is not tied to a column
+	Raw    []string // cells of the row as read, set by readers for rows that fail to parse or decode
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("line %d, column %q: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// attachRaw stores a copy of record as the raw cells of the RowError in err
+func attachRaw(err error, record []string) {
+	var rowErr *RowError
+	if record != nil && errors.As(err, &rowErr) && rowErr.Raw == nil {
+		rowErr.Raw = slices.Clone(record)
+	}
+}
+
+// FieldCountError describes a row with a different number of fields than
+// the header, or than set by WithFieldsPerRecord. It matches
+// csv.ErrFieldCount with errors.Is.
+type FieldCountError struct {
+	Expected int      // number of fields required
+	Got      int      // number of fields in the row
+	Missing  []string // header columns absent from a short row
+}
+
+func (e *FieldCountError) Error() string {
+	if len(e.Missing) > 0 {
+		return fmt.Sprintf("expected %d fields, got %d: missing columns: %s", e.Expected, e.Got, strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("expected %d fields, got %d", e.Expected, e.Got)
+}
+
+func (e *FieldCountError) Unwrap() error {
+	return csv.ErrFieldCount
+}
+
+// skippable reports whether err fails only its row, so that reading may
+// continue under WithMaxErrors
+func skippable(err error) bool {
+	var parseErr *csv.ParseError
+	var countErr *FieldCountError
+	return errors.As(err, &parseErr) || errors.As(err, &countErr)
+}
+
+// MultiRowError aggregates the failed rows of a read that exceeded the
+// WithMaxErrors budget. It matches ErrErrorLimit with errors.Is, and each
+// row's error with errors.As.
+type MultiRowError struct {
+	Errors []error // errors of the failed rows, in input order
+}
+
+func (e *MultiRowError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("%v: no rows failed", ErrErrorLimit)
+	}
+	return fmt.Sprintf("%v: %d rows failed, last: %v", ErrErrorLimit, len(e.Errors), e.Errors[len(e.Errors)-1])
+}
+
+func (e *MultiRowError) Unwrap() []error {
+	return append([]error{ErrErrorLimit}, e.Errors...)
+}
@@ -0,0 +1,56 @@
+package rowboat
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ReaderFactory creates Readers sharing a common set of options and
+// converters. It is safe for concurrent use, which makes it suitable for
+// services that construct many short-lived readers, such as upload
+// endpoints enforcing the same limits on every request. Struct field
+// information is cached per type, so repeated construction does not
+// repeat reflection work.
+type ReaderFactory struct {
+	mu         sync.RWMutex
+	opts       []Option
+	converters map[string]func(string) (any, error)
+}
+
+// NewReaderFactory creates a factory applying opts to every Reader it creates
+func NewReaderFactory(opts ...Option) *ReaderFactory {
+	return &ReaderFactory{
+		opts:       opts,
+		converters: make(map[string]func(string) (any, error)),
+	}
+}
+
+// RegisterConverter adds a converter applied to the matching field of every
+// Reader created afterwards. Types without a matching field ignore it.
+// Registering converters under both the Go field name and the column name
+// of a field makes creating Readers of its type fail.
+func (f *ReaderFactory) RegisterConverter(fieldName string, fn func(string) (any, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.converters[fieldName] = fn
+}
+
+// options returns the factory options followed by its converters
+func (f *ReaderFactory) options() []Option {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	opts := make([]Option, 0, len(f.opts)+len(f.converters))
+	opts = append(opts, f.opts...)
+	for _, name := range slices.Sorted(maps.Keys(f.converters)) {
+		opts = append(opts, WithConverter(name, f.converters[name]))
+	}
+	return opts
+}
+
+// NewReaderFrom creates a Reader using the factory's options and converters.
+// Options passed here are applied after the factory's own.
+func NewReaderFrom[T any](f *ReaderFactory, r io.Reader, opts ...Option) (*Reader[T], error) {
+	return NewReader[T](r, append(f.options(), opts...)...)
+}
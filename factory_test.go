@@ -0,0 +1,75 @@
+package rowboat_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestReaderFactory(t *testing.T) {
+	factory := rowboat.NewReaderFactory(rowboat.WithMaxRows(2))
+	factory.RegisterConverter("Email", func(s string) (any, error) {
+		return strings.ToLower(s), nil
+	})
+
+	csvData := `Name,Email,Age
+Alice,ALICE@EXAMPLE.COM,30
+Bob,BOB@EXAMPLE.COM,25`
+
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rb, err := rowboat.NewReaderFrom[Person](factory, strings.NewReader(csvData))
+			if err != nil {
+				t.Errorf("Failed to create Reader: %v", err)
+				return
+			}
+			results, err := collect(rb)
+			if err != nil {
+				t.Errorf("Failed to read: %v", err)
+				return
+			}
+			if !reflect.DeepEqual(results, expected) {
+				t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Factory limits apply to every reader
+	rb, err := rowboat.NewReaderFrom[Person](factory, strings.NewReader(csvData+"\nCharlie,c@example.com,35"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := collect(rb); !errors.Is(err, rowboat.ErrRowLimit) {
+		t.Errorf("Expected ErrRowLimit, got %v", err)
+	}
+
+	// Converters for fields a type lacks are ignored
+	if _, err := rowboat.NewReaderFrom[Custom](factory, strings.NewReader("point\n1;2")); err != nil {
+		t.Errorf("Expected unrelated converter to be ignored, got %v", err)
+	}
+
+	// Converters registered under both names of a field are ambiguous
+	upper := func(s string) (any, error) { return strings.ToUpper(s), nil }
+	factory.RegisterConverter("Account", upper)
+	factory.RegisterConverter("account", upper)
+	if _, err := rowboat.NewReaderFrom[Settlement](factory, strings.NewReader("account,amount\na,1")); err == nil || !strings.Contains(err.Error(), "ambiguous converters") {
+		t.Errorf("Expected an ambiguous converter error, got %v", err)
+	}
+	if _, err := rowboat.NewReader[Settlement](strings.NewReader("account,amount\na,1"),
+		rowboat.WithValueMap("Account", nil), rowboat.WithValueMap("account", nil)); err == nil || !strings.Contains(err.Error(), "ambiguous value maps") {
+		t.Errorf("Expected an ambiguous value map error, got %v", err)
+	}
+}
@@ -0,0 +1,572 @@
+package rowboat
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fieldInfo contains information about a struct field and its CSV tag options
+type fieldInfo struct {
+	Index    int
+	Indexed  bool // Index is set by the index tag option
+	Name     string
+	Aliases  []string // alternative header spellings accepted on read
+	Field    reflect.StructField
+	Locale   *locale
+	Decode   func(string) (any, error) // converter registered at runtime
+	Encode   func(any) (string, error) // converter registered at runtime
+	Values   map[string]string         // replacements applied before decoding
+	Raw      bool                      // cell text is stored and written verbatim
+	Pad      int                       // width integers are zero-padded to on write
+	Format   string                    // iso8601, isoweek or a time layout
+	Omit     bool                      // zero values are written as empty cells
+	Currency bool                      // currency symbols are stripped before parsing
+	Prec     int                       // digits after the decimal point of floats and decimals, -1 for shortest
+	FloatFmt string                    // fmt verb formatting floats, e.g. %.4f
+	Quote    bool                      // cells are always quoted on write
+	Trim     bool                      // surrounding whitespace is trimmed before decoding
+	Enum     []string                  // allowed cell values, nil for any
+	Mask     func(string) string       // redacts cells on write, nil for none
+	Encrypt  bool                      // cells are encrypted with the Encrypter of WithCipher
+}
+
+// timeLayout returns the layout used for the field's time.Time values
+func (fi fieldInfo) timeLayout(opts *options) string {
+	switch {
+	case fi.Format != "":
+		return fi.Format
+	case fi.Locale != nil:
+		return fi.Locale.DateLayout
+	case opts.timeLayout != "":
+		return opts.timeLayout
+	default:
+		return time.RFC3339
+	}
+}
+
+// padNumber zero-pads the digits of an integer to the field's width,
+// keeping a leading minus sign in front of the padding
+func (fi fieldInfo) padNumber(s string) string {
+	digits, negative := strings.CutPrefix(s, "-")
+	if len(digits) >= fi.Pad {
+		return s
+	}
+	digits = strings.Repeat("0", fi.Pad-len(digits)) + digits
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// omitEmpty reports whether zero values of the field are written as empty
+// cells, and empty cells read as zero values
+func (fi fieldInfo) omitEmpty(opts *options) bool {
+	return fi.Omit || opts.omitEmpty
+}
+
+// columnNames returns the header names the field is read from, in order
+// of preference
+func (fi fieldInfo) columnNames() []string {
+	return append([]string{fi.Name}, fi.Aliases...)
+}
+
+// hasColumn reports whether header is the field's column name or an alias
+func (fi fieldInfo) hasColumn(header string) bool {
+	return fi.Name == header || slices.Contains(fi.Aliases, header)
+}
+
+// trim removes surrounding whitespace from a cell under the trim tag option
+// or WithTrimSpace. Raw cells are kept verbatim.
+func (fi fieldInfo) trim(value string, opts *options) string {
+	if (fi.Trim || opts.trimSpace) && !fi.Raw {
+		return strings.TrimSpace(value)
+	}
+	return value
+}
+
+// matches reports whether name refers to the field by its Go name or column name
+func (fi fieldInfo) matches(name string) bool {
+	return fi.Field.Name == name || fi.Name == name
+}
+
+// fieldOption returns the entry of m registered under the Go field name or
+// the column name of fi. Registering both is ambiguous and fails.
+func fieldOption[V any](m map[string]V, fi fieldInfo, kind string) (V, bool, error) {
+	byField, okField := m[fi.Field.Name]
+	byName, okName := m[fi.Name]
+	switch {
+	case okField && okName && fi.Field.Name != fi.Name:
+		return byField, false, fmt.Errorf("ambiguous %s for field %s: registered as both '%s' and '%s'", kind, fi.Field.Name, fi.Field.Name, fi.Name)
+	case okField:
+		return byField, true, nil
+	}
+	return byName, okName, nil
+}
+
+// assignValue stores a converter result in field. Results of another type
+// are only converted between types of the same kind, such as string and a
+// named string type, and between numeric types when the value is preserved.
+func assignValue(field reflect.Value, v any) error {
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		field.Set(reflect.Zero(field.Type()))
+	case rv.Type().AssignableTo(field.Type()):
+		field.Set(rv)
+	case isNumeric(rv.Type()) && isNumeric(field.Type()):
+		converted := rv.Convert(field.Type())
+		if !sameNumber(rv, converted) {
+			return fmt.Errorf("converter result %v does not fit in %s", v, field.Type())
+		}
+		field.Set(converted)
+	case rv.Kind() == field.Kind() && rv.Type().ConvertibleTo(field.Type()):
+		field.Set(rv.Convert(field.Type()))
+	default:
+		return fmt.Errorf("converter returned %s, not assignable to %s", rv.Type(), field.Type())
+	}
+	return nil
+}
+
+// isNumeric reports whether t is an integer or float type
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// sameNumber reports whether converting the number v gave converted
+// without overflow, truncation or a change of sign
+func sameNumber(v, converted reflect.Value) bool {
+	negative := func(n reflect.Value) bool {
+		switch {
+		case n.CanInt():
+			return n.Int() < 0
+		case n.CanFloat():
+			return n.Float() < 0
+		}
+		return false
+	}
+	if v.CanFloat() && converted.CanFloat() && math.IsNaN(v.Float()) {
+		return true
+	}
+	return negative(v) == negative(converted) && converted.Convert(v.Type()).Equal(v)
+}
+
+// floatVerb matches a single fmt verb formatting a float, e.g. %.4f or %g
+var floatVerb = regexp.MustCompile(`^[^%]*%[-+# 0]*[0-9]*(\.[0-9]+)?[eEfFgG][^%]*$`)
+
+// isFloat reports whether t is a float type
+func isFloat(t reflect.Type) bool {
+	return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
+
+// ErrDuplicateIndex is returned when two fields of a struct have the same
+// index tag option
+var ErrDuplicateIndex = errors.New("duplicate field index")
+
+// recordType returns the struct type of records of type T, which may be a
+// struct or a pointer to one
+func recordType[T any]() reflect.Type {
+	t := reflect.TypeFor[T]()
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		return t.Elem()
+	}
+	return t
+}
+
+// errNilRecord is returned when writing a nil pointer record
+var errNilRecord = errors.New("nil record")
+
+// structValue returns the struct of record, which may be a pointer to it
+func structValue(record reflect.Value) (reflect.Value, error) {
+	if record.Kind() != reflect.Pointer {
+		return record, nil
+	}
+	if record.IsNil() {
+		return reflect.Value{}, errNilRecord
+	}
+	return record.Elem(), nil
+}
+
+// recordOf returns the struct ptr points to as a T, which may be a pointer
+// to the struct
+func recordOf[T any](ptr reflect.Value) T {
+	if record, ok := ptr.Interface().(T); ok {
+		return record
+	}
+	return ptr.Elem().Interface().(T)
+}
+
+// fieldCache caches the result of parseFields per struct type
+var fieldCache sync.Map // map[reflect.Type][]fieldInfo
+
+// cachedFields returns a copy of the parsed fields of tType, parsing the
+// type only on first use
+func cachedFields(tType reflect.Type) ([]fieldInfo, error) {
+	if tType != nil {
+		if cached, ok := fieldCache.Load(tType); ok {
+			return slices.Clone(cached.([]fieldInfo)), nil
+		}
+	}
+	fields, err := parseFields(tType)
+	if err != nil {
+		return nil, err
+	}
+	fieldCache.Store(tType, fields)
+	return slices.Clone(fields), nil
+}
+
+// parseFields extracts information about struct fields, including indexes,
+// and returns them sorted in column order
+func parseFields(tType reflect.Type) ([]fieldInfo, error) {
+	if tType == nil || tType.Kind() != reflect.Struct {
+		return nil, errors.New("generic type T must be a struct or a pointer to one")
+	}
+
+	fields, err := collectFields(tType, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	fields, err = dominantFields(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := assignIndexes(fields); err != nil {
+		return nil, fmt.Errorf("type %s: %w", tType, err)
+	}
+	if err := checkAliases(fields); err != nil {
+		return nil, fmt.Errorf("type %s: %w", tType, err)
+	}
+
+	// Sort the fields based on the index
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Index < fields[j].Index
+	})
+
+	return fields, nil
+}
+
+// assignIndexes assigns the column of every field. Fields with an index
+// tag take that column, and the others take the lowest free columns in
+// declaration order. Columns left without a field are gaps.
+func assignIndexes(fields []fieldInfo) error {
+	taken := make(map[int]string, len(fields))
+	for _, fi := range fields {
+		if !fi.Indexed {
+			continue
+		}
+		if other, ok := taken[fi.Index]; ok {
+			return fmt.Errorf("%w: fields '%s' and '%s' both have index %d", ErrDuplicateIndex, other, fi.Field.Name, fi.Index)
+		}
+		taken[fi.Index] = fi.Field.Name
+	}
+	next := 0
+	for i := range fields {
+		if fields[i].Indexed {
+			continue
+		}
+		for taken[next] != "" {
+			next++
+		}
+		fields[i].Index = next
+		taken[next] = fields[i].Field.Name
+	}
+	return nil
+}
+
+// checkAliases fails if an alias of one field is the column name or an
+// alias of another, which would leave the column's field ambiguous
+func checkAliases(fields []fieldInfo) error {
+	owners := make(map[string]string)
+	for _, fi := range fields {
+		owners[fi.Name] = fi.Field.Name
+	}
+	for _, fi := range fields {
+		for _, alias := range fi.Aliases {
+			if other, ok := owners[alias]; ok && other != fi.Field.Name {
+				return fmt.Errorf("fields '%s' and '%s' both accept column '%s'", other, fi.Field.Name, alias)
+			}
+			owners[alias] = fi.Field.Name
+		}
+	}
+	return nil
+}
+
+// collectFields parses the fields of tType in declaration order, promoting
+// the fields of embedded structs. parent is the index path of tType within
+// the outermost struct, and enclosing the types along that path.
+func collectFields(tType reflect.Type, parent []int, enclosing []reflect.Type) ([]fieldInfo, error) {
+	fields := make([]fieldInfo, 0, tType.NumField())
+
+	for i := 0; i < tType.NumField(); i++ {
+		field := tType.Field(i)
+		csvTag := field.Tag.Get("csv")
+		if csvTag == "-" {
+			continue // skip field
+		}
+		field.Index = append(slices.Clone(parent), i)
+
+		tagParts := strings.Split(csvTag, ",")
+		if promoted(field, tagParts[0]) {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Pointer {
+				// Nil pointers to unexported structs cannot be allocated,
+				// and pointers back to an enclosing struct recurse forever
+				embeddedType = embeddedType.Elem()
+				if !field.IsExported() || embeddedType == tType || slices.Contains(enclosing, embeddedType) {
+					continue
+				}
+			}
+			embedded, err := collectFields(embeddedType, field.Index, append(enclosing, tType))
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if !field.IsExported() {
+			continue // cannot be set or read through reflection
+		}
+
+		fi, err := parseTag(field, tagParts)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, fi)
+	}
+	return fields, nil
+}
+
+// parseTag parses the csv tag options of field, split at commas
+func parseTag(field reflect.StructField, tagParts []string) (fieldInfo, error) {
+	fi := fieldInfo{
+		Index: field.Index[0], // default index is the field order
+		Name:  field.Name,
+		Field: field,
+		Prec:  -1,
+	}
+	if tagParts[0] != "" {
+		fi.Name = tagParts[0]
+	}
+	// Tag options of pointer fields apply to the value pointed to
+	baseType := field.Type
+	if baseType.Kind() == reflect.Pointer {
+		baseType = baseType.Elem()
+	}
+
+	for _, part := range tagParts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasPrefix(part, "index="):
+			idxStr := strings.TrimPrefix(part, "index=")
+			idx, err := strconv.Atoi(idxStr)
+			if err != nil || idx < 0 {
+				return fieldInfo{}, fmt.Errorf("invalid index value '%s' in field '%s'", idxStr, field.Name)
+			}
+			fi.Index = idx
+			fi.Indexed = true
+		case strings.HasPrefix(part, "alias="):
+			for _, alias := range strings.Split(strings.TrimPrefix(part, "alias="), "|") {
+				if alias = strings.TrimSpace(alias); alias != "" {
+					fi.Aliases = append(fi.Aliases, alias)
+				}
+			}
+		case strings.HasPrefix(part, "enum="):
+			fi.Enum = strings.Split(strings.TrimPrefix(part, "enum="), "|")
+		case strings.HasPrefix(part, "locale="):
+			name := strings.TrimPrefix(part, "locale=")
+			loc, err := lookupLocale(name)
+			if err != nil {
+				return fieldInfo{}, fmt.Errorf("invalid locale in field '%s': %w", field.Name, err)
+			}
+			fi.Locale = loc
+		case strings.HasPrefix(part, "pad="):
+			widthStr := strings.TrimPrefix(part, "pad=")
+			width, err := strconv.Atoi(widthStr)
+			if err != nil || width < 0 {
+				return fieldInfo{}, fmt.Errorf("invalid pad value '%s' in field '%s'", widthStr, field.Name)
+			}
+			switch baseType.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			default:
+				return fieldInfo{}, fmt.Errorf("pad field '%s' must be an integer", field.Name)
+			}
+			fi.Pad = width
+		case strings.HasPrefix(part, "format="):
+			fi.Format = strings.TrimPrefix(part, "format=")
+			isTime := baseType == reflect.TypeOf(time.Time{})
+			switch {
+			case fi.Format == tagISODuration && baseType != reflect.TypeOf(time.Duration(0)):
+				return fieldInfo{}, fmt.Errorf("format iso8601 in field '%s' requires a time.Duration", field.Name)
+			case fi.Format != tagISODuration && !isTime:
+				return fieldInfo{}, fmt.Errorf("format '%s' in field '%s' requires a time.Time", fi.Format, field.Name)
+			}
+		case part == "raw":
+			if baseType.Kind() != reflect.String && baseType != reflect.TypeOf([]byte(nil)) {
+				return fieldInfo{}, fmt.Errorf("raw field '%s' must be a string or []byte", field.Name)
+			}
+			fi.Raw = true
+		case part == "omitempty":
+			fi.Omit = true
+		case part == "quote":
+			fi.Quote = true
+		case part == "trim":
+			fi.Trim = true
+		case part == "encrypt":
+			fi.Encrypt = true
+		case strings.HasPrefix(part, "prec="):
+			precStr := strings.TrimPrefix(part, "prec=")
+			prec, err := strconv.Atoi(precStr)
+			if err != nil || prec < 0 {
+				return fieldInfo{}, fmt.Errorf("invalid prec value '%s' in field '%s'", precStr, field.Name)
+			}
+			if !isFloat(baseType) && baseType != decimalType {
+				return fieldInfo{}, fmt.Errorf("prec field '%s' must be a float or Decimal", field.Name)
+			}
+			fi.Prec = prec
+		case strings.HasPrefix(part, "fmt="):
+			fi.FloatFmt = strings.TrimPrefix(part, "fmt=")
+			if !isFloat(baseType) {
+				return fieldInfo{}, fmt.Errorf("fmt field '%s' must be a float", field.Name)
+			}
+			if !floatVerb.MatchString(fi.FloatFmt) {
+				return fieldInfo{}, fmt.Errorf("invalid fmt value '%s' in field '%s'", fi.FloatFmt, field.Name)
+			}
+		case part == "currency":
+			fi.Currency = true
+		case strings.HasPrefix(part, "mask="):
+			mask, err := parseMask(strings.TrimPrefix(part, "mask="))
+			if err != nil {
+				return fieldInfo{}, fmt.Errorf("%w in field '%s'", err, field.Name)
+			}
+			fi.Mask = mask
+		}
+	}
+
+	return fi, nil
+}
+
+// promoted reports whether the fields of an embedded field are treated as
+// columns of the outer struct. Embedded structs and pointers to structs are
+// promoted; those with a column name, or with their own CSV encoding, are
+// regular fields.
+func promoted(field reflect.StructField, name string) bool {
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return field.Anonymous && name == "" && t.Kind() == reflect.Struct &&
+		t != reflect.TypeOf(time.Time{}) &&
+		!reflect.PointerTo(t).Implements(csvMarshalerType) &&
+		!reflect.PointerTo(t).Implements(csvUnmarshalerType)
+}
+
+// value returns the field of fi in v. It reports false when the field is
+// promoted through a nil embedded pointer.
+func (fi fieldInfo) value(v reflect.Value) (reflect.Value, bool) {
+	field, err := v.FieldByIndexErr(fi.Field.Index)
+	return field, err == nil
+}
+
+// settable returns the field of fi in v, allocating nil embedded pointers
+// on its path
+func (fi fieldInfo) settable(v reflect.Value) reflect.Value {
+	for i, x := range fi.Field.Index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// ErrUnsupportedField is returned when creating a Reader or Writer for a
+// struct with exported fields of types that cannot be mapped to cells
+var ErrUnsupportedField = errors.New("unsupported field type")
+
+// checkMappable returns an error listing the fields whose types the Reader
+// (or Writer, if write is set) cannot convert, ignoring fields with a
+// converter
+func checkMappable(fields []fieldInfo, write bool) error {
+	coder := csvUnmarshalerType
+	if write {
+		coder = csvMarshalerType
+	}
+	var unsupported []string
+	for _, fi := range fields {
+		if fi.Raw || (!write && fi.Decode != nil) || (write && fi.Encode != nil) {
+			continue
+		}
+		if !mappable(fi.Field.Type, coder) {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)", fi.Field.Name, fi.Field.Type))
+		}
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("%w: %s; register a converter or skip them with the tag csv:\"-\"", ErrUnsupportedField, strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// mappable reports whether values of t convert to and from cells, given
+// CSVMarshaler or CSVUnmarshaler as coder
+func mappable(t reflect.Type, coder reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) || t.Implements(coder) || reflect.PointerTo(t).Implements(coder) || lookupEnum(t) != nil {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// dominantFields applies Go's promotion rules to columns of the same name: a
+// shallower field hides promoted ones, and promoted fields at the same depth
+// are ambiguous
+func dominantFields(fields []fieldInfo) ([]fieldInfo, error) {
+	depth := make(map[string]int, len(fields))
+	for _, fi := range fields {
+		if d, ok := depth[fi.Name]; !ok || len(fi.Field.Index) < d {
+			depth[fi.Name] = len(fi.Field.Index)
+		}
+	}
+	seen := make(map[string]bool)
+	dominant := fields[:0]
+	for _, fi := range fields {
+		d := len(fi.Field.Index)
+		if d > depth[fi.Name] {
+			continue
+		}
+		if d > 1 {
+			if seen[fi.Name] {
+				return nil, fmt.Errorf("ambiguous column '%s' promoted from embedded structs", fi.Name)
+			}
+			seen[fi.Name] = true
+		}
+		dominant = append(dominant, fi)
+	}
+	return dominant, nil
+}
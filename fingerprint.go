@@ -0,0 +1,53 @@
+package rowboat
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// schemaCommentPrefix starts the comment line carrying the schema fingerprint
+const schemaCommentPrefix = "# rowboat-schema: "
+
+// ErrSchemaMismatch is returned when a file's schema fingerprint does not
+// match the fingerprint of the struct used to read it
+var ErrSchemaMismatch = errors.New("schema fingerprint mismatch")
+
+// schemaFingerprint hashes the column names and types of fields in order
+func schemaFingerprint(fields []fieldInfo) string {
+	h := sha256.New()
+	for _, fi := range fields {
+		fmt.Fprintf(h, "%s:%s\n", fi.Name, fi.Field.Type)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// writeSchemaComment writes the fingerprint comment line to w
+func writeSchemaComment(w io.Writer, fields []fieldInfo) error {
+	_, err := io.WriteString(w, schemaCommentPrefix+schemaFingerprint(fields)+"\n")
+	return err
+}
+
+// verifySchemaComment consumes the fingerprint comment line from r and
+// compares it against the fingerprint of fields. It returns the length of
+// the line in bytes.
+func verifySchemaComment(r *bufio.Reader, fields []fieldInfo) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	n := len(line)
+	line = strings.TrimPrefix(strings.TrimRight(line, "\r\n"), utf8BOM)
+	if !strings.HasPrefix(line, schemaCommentPrefix) {
+		return n, fmt.Errorf("%w: missing schema comment", ErrSchemaMismatch)
+	}
+	got := strings.TrimPrefix(line, schemaCommentPrefix)
+	if want := schemaFingerprint(fields); got != want {
+		return n, fmt.Errorf("%w: file has %s, expected %s", ErrSchemaMismatch, got, want)
+	}
+	return n, nil
+}
@@ -0,0 +1,64 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestSchemaHash(t *testing.T) {
+	people := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithSchemaHash())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(people)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "# rowboat-schema: sha256:") {
+		t.Fatalf("Expected schema comment line, got:\n%s", buf.String())
+	}
+
+	// Same struct verifies and reads
+	rb, err := rowboat.NewReader[Person](strings.NewReader(buf.String()), rowboat.WithSchemaHash())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, people) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", people, results)
+	}
+
+	// A struct whose column types differ is rejected at open time
+	type PersonV2 struct {
+		Name  string  `csv:"Name"`
+		Email string  `csv:"Email"`
+		Age   float64 `csv:"Age"`
+	}
+	_, err = rowboat.NewReader[PersonV2](strings.NewReader(buf.String()), rowboat.WithSchemaHash())
+	if !errors.Is(err, rowboat.ErrSchemaMismatch) {
+		t.Errorf("Expected ErrSchemaMismatch, got %v", err)
+	}
+}
+
+func TestSchemaHashMissing(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\n"
+	_, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithSchemaHash())
+	if !errors.Is(err, rowboat.ErrSchemaMismatch) {
+		t.Errorf("Expected ErrSchemaMismatch, got %v", err)
+	}
+}
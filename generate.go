@@ -0,0 +1,106 @@
+package rowboat
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// initialisms are words written in upper case in Go identifiers
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "UUID": true, "IP": true,
+	"HTTP": true, "API": true, "SKU": true, "JSON": true, "SQL": true,
+}
+
+// GenerateStruct returns the source of a Go file in package pkg declaring a
+// struct named typeName with one tagged field per column of schema. Field
+// names are derived from the column names, and time columns in layouts
+// other than RFC 3339 get a format tag, so the struct reads the file the
+// schema was inferred from.
+func GenerateStruct(pkg, typeName string, schema Schema) ([]byte, error) {
+	if err := schema.validate(); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	usesTime := false
+	names := make(map[string]int)
+	for _, f := range schema.fields {
+		if f.Name == "-" || strings.ContainsAny(f.Name, ",`") {
+			return nil, fmt.Errorf("column '%s' cannot be expressed in a csv tag", f.Name)
+		}
+		name := goName(f.Name)
+		if names[name]++; names[name] > 1 {
+			name += strconv.Itoa(names[name])
+		}
+
+		var goType string
+		tag := f.Name
+		switch f.Type {
+		case Int:
+			goType = "int"
+		case Uint:
+			goType = "uint"
+		case Float:
+			goType = "float64"
+		case Bool:
+			goType = "bool"
+		case Time:
+			goType = "time.Time"
+			usesTime = true
+			if f.Layout != time.RFC3339 {
+				tag += ",format=" + f.Layout
+			}
+		default:
+			goType = "string"
+		}
+		fmt.Fprintf(&body, "\t%s %s `csv:%s`\n", name, goType, strconv.Quote(tag))
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if usesTime {
+		src.WriteString("import \"time\"\n\n")
+	}
+	fmt.Fprintf(&src, "// %s is a record of the CSV file\ntype %s struct {\n", typeName, typeName)
+	src.Write(body.Bytes())
+	src.WriteString("}\n")
+	return format.Source(src.Bytes())
+}
+
+// goName converts a column name into an exported Go identifier, e.g.
+// "order id" into OrderID
+func goName(column string) string {
+	words := strings.FieldsFunc(column, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		// Split camel case words, keeping runs of capitals together
+		runes := []rune(word)
+		start := 0
+		for i := 1; i <= len(runes); i++ {
+			if i < len(runes) && !(unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1])) {
+				continue
+			}
+			part := string(runes[start:i])
+			if upper := strings.ToUpper(part); initialisms[upper] {
+				b.WriteString(upper)
+			} else {
+				r := []rune(strings.ToLower(part))
+				r[0] = unicode.ToUpper(r[0])
+				b.WriteString(string(r))
+			}
+			start = i
+		}
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Column" + name
+	}
+	return name
+}
@@ -0,0 +1,44 @@
+package rowboat_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	csvData := `order id,Total Amount,createdAt,customerURL,1st,Paid,paid
+1,2.5,2024-01-02,https://example.com,a,true,2024-01-02T10:00:00Z`
+
+	schema, err := rowboat.InferSchema(strings.NewReader(csvData), 0)
+	if err != nil {
+		t.Fatalf("Failed to infer schema: %v", err)
+	}
+	src, err := rowboat.GenerateStruct("orders", "Order", schema)
+	if err != nil {
+		t.Fatalf("Failed to generate struct: %v", err)
+	}
+
+	expected := "package orders\n\nimport \"time\"\n\n" +
+		"// Order is a record of the CSV file\n" +
+		"type Order struct {\n" +
+		"\tOrderID     int       `csv:\"order id\"`\n" +
+		"\tTotalAmount float64   `csv:\"Total Amount\"`\n" +
+		"\tCreatedAt   time.Time `csv:\"createdAt,format=2006-01-02\"`\n" +
+		"\tCustomerURL string    `csv:\"customerURL\"`\n" +
+		"\tColumn1st   string    `csv:\"1st\"`\n" +
+		"\tPaid        bool      `csv:\"Paid\"`\n" +
+		"\tPaid2       time.Time `csv:\"paid\"`\n" +
+		"}\n"
+	if string(src) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, src)
+	}
+}
+
+func TestGenerateStructInvalidColumn(t *testing.T) {
+	schema := rowboat.NewSchema().Field("a,b", rowboat.String)
+	if _, err := rowboat.GenerateStruct("main", "Record", schema); err == nil {
+		t.Errorf("Expected error for column name containing a comma")
+	}
+}
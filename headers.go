@@ -0,0 +1,109 @@
+package rowboat
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HeaderError reports the differences between a header row and the
+// columns of a struct
+type HeaderError struct {
+	Missing    []string // struct columns absent from the header
+	Extra      []string // header columns matching no struct field
+	Duplicated []string // header columns appearing more than once
+}
+
+func (e *HeaderError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing columns: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		parts = append(parts, "unexpected columns: "+strings.Join(e.Extra, ", "))
+	}
+	if len(e.Duplicated) > 0 {
+		parts = append(parts, "duplicated columns: "+strings.Join(e.Duplicated, ", "))
+	}
+	return fmt.Sprintf("invalid header: %s", strings.Join(parts, "; "))
+}
+
+// DuplicateHeaderPolicy selects how a Reader handles header columns that
+// appear more than once
+type DuplicateHeaderPolicy int
+
+const (
+	// DuplicateLastWins maps the last column with a name to the field.
+	// This is the default.
+	DuplicateLastWins DuplicateHeaderPolicy = iota
+	// DuplicateFirstWins maps the first column with a name to the field
+	DuplicateFirstWins
+	// DuplicateError fails with a *HeaderError listing the duplicates
+	DuplicateError
+	// DuplicateSuffix renames repeated columns by appending _2, _3 and so
+	// on, so that email, email becomes email, email_2. Suffixes already
+	// used by other columns are skipped.
+	DuplicateSuffix
+)
+
+// WithDuplicateHeaders sets the policy for duplicated header columns
+func WithDuplicateHeaders(policy DuplicateHeaderPolicy) Option {
+	return func(o *options) {
+		o.duplicates = policy
+	}
+}
+
+// WithHeaderMapping binds input headers to struct fields at runtime, for
+// mappings a user confirms in an import UI. mapping is keyed by header,
+// such as "Cust E-Mail", and names the field by its Go name or column
+// name. Mapped headers take precedence over the names in struct tags.
+func WithHeaderMapping(mapping map[string]string) Option {
+	return func(o *options) {
+		o.headerMapping = mapping
+	}
+}
+
+// ValidateHeaders checks a header row against the columns of T before any
+// data is read. It returns a *HeaderError listing missing, extra and
+// duplicated columns, or nil if the header matches exactly.
+func ValidateHeaders[T any](headers []string) error {
+	fields, err := cachedFields(recordType[T]())
+	if err != nil {
+		return err
+	}
+
+	var report HeaderError
+	counts := make(map[string]int, len(headers))
+	for i, header := range headers {
+		if i == 0 {
+			header = strings.TrimPrefix(header, utf8BOM)
+		}
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue // blank columns, e.g. index gaps
+		}
+		counts[header]++
+		switch {
+		case counts[header] == 2:
+			report.Duplicated = append(report.Duplicated, header)
+		case counts[header] == 1 && !slices.ContainsFunc(fields, func(fi fieldInfo) bool { return fi.hasColumn(header) }):
+			report.Extra = append(report.Extra, header)
+		}
+	}
+	for _, fi := range fields {
+		if !slices.ContainsFunc(fi.columnNames(), func(name string) bool { return counts[name] > 0 }) {
+			report.Missing = append(report.Missing, fi.Name)
+		}
+	}
+
+	if len(report.Missing) == 0 && len(report.Extra) == 0 && len(report.Duplicated) == 0 {
+		return nil
+	}
+	return &report
+}
+
+// Headers returns the header row read from the input, or nil if it has
+// not been read yet
+func (rb *Reader[T]) Headers() []string {
+	return slices.Clone(rb.headers)
+}
@@ -0,0 +1,175 @@
+package rowboat_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestValidateHeaders(t *testing.T) {
+	if err := rowboat.ValidateHeaders[Person]([]string{"Name", " Email ", "Age"}); err != nil {
+		t.Fatalf("Expected valid header, got %v", err)
+	}
+
+	err := rowboat.ValidateHeaders[Person]([]string{"Name", "Phone", "Name", "Email"})
+	var headerErr *rowboat.HeaderError
+	if !errors.As(err, &headerErr) {
+		t.Fatalf("Expected HeaderError, got %v", err)
+	}
+	expected := &rowboat.HeaderError{
+		Missing:    []string{"Age"},
+		Extra:      []string{"Phone"},
+		Duplicated: []string{"Name"},
+	}
+	if !reflect.DeepEqual(headerErr, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, headerErr)
+	}
+	if want := "invalid header: missing columns: Age; unexpected columns: Phone; duplicated columns: Name"; err.Error() != want {
+		t.Errorf("Expected: %s\nGot: %s", want, err.Error())
+	}
+}
+
+func TestReaderHeaders(t *testing.T) {
+	rb, err := rowboat.NewReader[Person](strings.NewReader("\ufeffName,Email,Age\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if want := []string{"Name", "Email", "Age"}; !reflect.DeepEqual(rb.Headers(), want) {
+		t.Errorf("Expected: %v\nGot: %v", want, rb.Headers())
+	}
+}
+
+func TestDuplicateHeaderPolicy(t *testing.T) {
+	type Contact struct {
+		Name   string `csv:"name"`
+		Email  string `csv:"email"`
+		Email2 string `csv:"email_2"`
+	}
+	csvData := "name,email,email\nAlice,work@example.com,home@example.com\n"
+
+	tests := []struct {
+		name     string
+		policy   rowboat.DuplicateHeaderPolicy
+		expected Contact
+	}{
+		{"last", rowboat.DuplicateLastWins, Contact{Name: "Alice", Email: "home@example.com"}},
+		{"first", rowboat.DuplicateFirstWins, Contact{Name: "Alice", Email: "work@example.com"}},
+		{"suffix", rowboat.DuplicateSuffix, Contact{Name: "Alice", Email: "work@example.com", Email2: "home@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rb, err := rowboat.NewReader[Contact](strings.NewReader(csvData), rowboat.WithDuplicateHeaders(tt.policy))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			results, err := rb.ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to read records: %v", err)
+			}
+			if len(results) != 1 || results[0] != tt.expected {
+				t.Errorf("Expected: %+v\nGot: %+v", tt.expected, results)
+			}
+		})
+	}
+
+	_, err := rowboat.NewReader[Contact](strings.NewReader(csvData), rowboat.WithDuplicateHeaders(rowboat.DuplicateError))
+	var headerErr *rowboat.HeaderError
+	if !errors.As(err, &headerErr) || !reflect.DeepEqual(headerErr.Duplicated, []string{"email"}) {
+		t.Errorf("Expected HeaderError for duplicated email, got %v", err)
+	}
+
+	// Suffixes skip names of other columns
+	rb, err := rowboat.NewReader[Contact](strings.NewReader("email,email,email_2,email\nwork,home,real,other\n"),
+		rowboat.WithDuplicateHeaders(rowboat.DuplicateSuffix))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if want := []string{"email", "email_3", "email_2", "email_4"}; !reflect.DeepEqual(rb.Headers(), want) {
+		t.Errorf("Expected: %v\nGot: %v", want, rb.Headers())
+	}
+	results, err := rb.ReadAll()
+	if expected := []Contact{{Email: "work", Email2: "real"}}; err != nil || !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v, %v", expected, results, err)
+	}
+}
+
+// PartnerContact accepts several spellings of its columns
+type PartnerContact struct {
+	Name  string `csv:"name,alias=Name|Full Name"`
+	Email string `csv:"email,alias=e-mail|Email Address"`
+}
+
+func TestHeaderAliases(t *testing.T) {
+	for _, csvData := range []string{
+		"name,email\nAlice,alice@example.com\n",
+		"Full Name,e-mail\nAlice,alice@example.com\n",
+		"Email Address,Name\nalice@example.com,Alice\n",
+	} {
+		rb, err := rowboat.NewReader[PartnerContact](strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("Failed to create Reader: %v", err)
+		}
+		got, err := rb.ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read records: %v", err)
+		}
+		expected := []PartnerContact{{Name: "Alice", Email: "alice@example.com"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+		}
+	}
+
+	// The column name is preferred over an alias
+	rb, err := rowboat.NewReader[PartnerContact](strings.NewReader("Name,name,email\nalias,column,a@example.com\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if got[0].Name != "column" {
+		t.Errorf("Expected: %+v\nGot: %+v", "column", got[0].Name)
+	}
+
+	if err := rowboat.ValidateHeaders[PartnerContact]([]string{"Full Name", "e-mail"}); err != nil {
+		t.Errorf("Expected aliases to validate, got %v", err)
+	}
+
+	// An alias shared by two fields is rejected
+	type SharedAlias struct {
+		Home string `csv:"home,alias=phone"`
+		Work string `csv:"work,alias=phone"`
+	}
+	if _, err := rowboat.NewReader[SharedAlias](strings.NewReader("phone\n123\n")); err == nil || !strings.Contains(err.Error(), "both accept column 'phone'") {
+		t.Errorf("Expected an error for the shared alias, got %v", err)
+	}
+}
+
+func TestHeaderMapping(t *testing.T) {
+	csvData := "Customer,Cust E-Mail,Email,Years\nAlice,alice@example.com,ignored,30\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithHeaderMapping(map[string]string{
+		"Customer":    "Name",
+		"Cust E-Mail": "Email",
+		"Years":       "Age",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	_, err = rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithHeaderMapping(map[string]string{"Customer": "Phone"}))
+	if err == nil || !strings.Contains(err.Error(), "Phone") {
+		t.Errorf("Expected an error for the unknown field Phone, got %v", err)
+	}
+}
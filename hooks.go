@@ -0,0 +1,33 @@
+package rowboat
+
+import "reflect"
+
+// AfterCSVDecoder is implemented by records that post-process themselves
+// after decoding, e.g. to normalize values, compute derived fields or
+// validate. An error fails the row like a decoding error.
+type AfterCSVDecoder interface {
+	AfterCSVDecode() error
+}
+
+// BeforeCSVEncoder is implemented by records that prepare themselves for
+// encoding. The Writer calls it on a copy of the record, so changes do not
+// affect the caller's value. An error fails the write.
+type BeforeCSVEncoder interface {
+	BeforeCSVEncode() error
+}
+
+// afterDecode runs the AfterCSVDecode hook of the record ptr points to
+func afterDecode(ptr reflect.Value) error {
+	if h, ok := ptr.Interface().(AfterCSVDecoder); ok {
+		return h.AfterCSVDecode()
+	}
+	return nil
+}
+
+// beforeEncode runs the BeforeCSVEncode hook of the record ptr points to
+func beforeEncode(ptr reflect.Value) error {
+	if h, ok := ptr.Interface().(BeforeCSVEncoder); ok {
+		return h.BeforeCSVEncode()
+	}
+	return nil
+}
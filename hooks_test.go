@@ -0,0 +1,70 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Invoice struct {
+	Number string  `csv:"number"`
+	Net    float64 `csv:"net"`
+	Tax    float64 `csv:"tax"`
+	Gross  float64 `csv:"-"`
+}
+
+func (i *Invoice) AfterCSVDecode() error {
+	if i.Net < 0 {
+		return errors.New("negative net amount")
+	}
+	i.Number = strings.ToUpper(i.Number)
+	i.Gross = i.Net + i.Tax
+	return nil
+}
+
+func (i *Invoice) BeforeCSVEncode() error {
+	if i.Number == "" {
+		return errors.New("missing invoice number")
+	}
+	i.Number = strings.ToLower(i.Number)
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	rb, err := rowboat.NewReader[Invoice](strings.NewReader("number,net,tax\ninv-1,100,20\ninv-2,-5,0\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	expected := []Invoice{{Number: "INV-1", Net: 100, Tax: 20, Gross: 120}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 {
+		t.Errorf("Expected a RowError on line 3, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[*Invoice](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(&got[0]); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Write(&Invoice{Net: 1}); err == nil {
+		t.Error("Expected an error for a missing invoice number")
+	}
+	writer.Flush()
+	if want := "inv-1,100,20\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+	if got[0].Number != "INV-1" {
+		t.Errorf("Expected the hook to leave the caller's record unchanged, got %q", got[0].Number)
+	}
+}
@@ -0,0 +1,200 @@
+package rowboat
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values of the format tag option selecting ISO 8601 representations
+const (
+	tagISODuration = "iso8601" // time.Duration as e.g. P3DT4H
+	tagISOWeek     = "isoweek" // time.Time as a week date, e.g. 2023-W07-3
+)
+
+// isoDurationUnits lists the designators of an ISO 8601 duration in order,
+// with the date part before the T separator
+var isoDurationUnits = []struct {
+	designator byte
+	unit       time.Duration
+	timePart   bool
+}{
+	{'W', 7 * 24 * time.Hour, false},
+	{'D', 24 * time.Hour, false},
+	{'H', time.Hour, true},
+	{'M', time.Minute, true},
+	{'S', time.Second, true},
+}
+
+// parseISODuration parses an ISO 8601 duration such as P3DT4H or PT1.5S.
+// Days are 24 hours long; years and months are rejected because they have
+// no fixed length.
+func parseISODuration(s string) (time.Duration, error) {
+	orig := s
+	negative := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		s, negative = rest, true
+	}
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok || s == "" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid ISO 8601 duration '%s'", orig)
+	}
+
+	// The magnitude is unsigned, so that math.MinInt64 can be parsed
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++
+	}
+	var n uint64
+	inTime := false
+	next := 0
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("invalid ISO 8601 duration '%s'", orig)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+		end := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if end <= 0 {
+			return 0, fmt.Errorf("invalid ISO 8601 duration '%s'", orig)
+		}
+		number, designator := s[:end], s[end]
+		s = s[end+1:]
+		if !inTime && (designator == 'Y' || designator == 'M') {
+			return 0, errors.New("ISO 8601 years and months have no fixed duration")
+		}
+
+		found := false
+		for next < len(isoDurationUnits) {
+			u := isoDurationUnits[next]
+			next++
+			if u.designator == designator && u.timePart == inTime {
+				v, err := scaleDecimal(strings.Replace(number, ",", ".", 1), u.unit)
+				if err == nil && uint64(v) > limit-n {
+					err = errors.New("duration out of range")
+				}
+				if err != nil {
+					return 0, fmt.Errorf("invalid ISO 8601 duration '%s': %w", orig, err)
+				}
+				n += uint64(v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("invalid ISO 8601 duration '%s'", orig)
+		}
+	}
+	d := time.Duration(n)
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// scaleDecimal multiplies a non-negative decimal number by unit without
+// losing precision to floating point
+func scaleDecimal(number string, unit time.Duration) (time.Duration, error) {
+	intPart, fracPart, _ := strings.Cut(number, ".")
+	whole, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	var frac time.Duration
+	scale := time.Duration(1)
+	for _, c := range fracPart {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid number '%s'", number)
+		}
+		scale *= 10
+		frac += time.Duration(c-'0') * unit / scale
+		if scale >= unit {
+			break
+		}
+	}
+	if whole > int64(math.MaxInt64-frac)/int64(unit) {
+		return 0, fmt.Errorf("number '%s' out of range", number)
+	}
+	return time.Duration(whole)*unit + frac, nil
+}
+
+// formatISODuration formats d as an ISO 8601 duration using days, hours,
+// minutes and seconds, e.g. P1DT2H30M or PT0.5S
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	// The magnitude is unsigned, so that math.MinInt64 can be negated
+	var b strings.Builder
+	n := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		n = -n
+	}
+	b.WriteByte('P')
+	if days := n / uint64(24*time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		n %= uint64(24 * time.Hour)
+	}
+	if n == 0 {
+		return b.String()
+	}
+	b.WriteByte('T')
+	if h := n / uint64(time.Hour); h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		n %= uint64(time.Hour)
+	}
+	if m := n / uint64(time.Minute); m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		n %= uint64(time.Minute)
+	}
+	if n > 0 {
+		secs := strconv.FormatUint(n/uint64(time.Second), 10)
+		if frac := n % uint64(time.Second); frac > 0 {
+			secs += strings.TrimRight(fmt.Sprintf(".%09d", frac), "0")
+		}
+		b.WriteString(secs + "S")
+	}
+	return b.String()
+}
+
+// parseISOWeek parses an ISO 8601 week date such as 2023-W07-3, or
+// 2023-W07 for the Monday of the week, into a UTC time
+func parseISOWeek(s string) (time.Time, error) {
+	var year, week, day int
+	var err error
+	switch len(s) {
+	case 8:
+		day = 1
+		_, err = fmt.Sscanf(s, "%4d-W%2d", &year, &week)
+	case 10:
+		_, err = fmt.Sscanf(s, "%4d-W%2d-%1d", &year, &week, &day)
+	default:
+		err = errors.New("wrong length")
+	}
+	if err != nil || day < 1 || day > 7 || week < 1 {
+		return time.Time{}, fmt.Errorf("invalid ISO 8601 week date '%s'", s)
+	}
+
+	// Week 1 is the week containing January 4th
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7 // days since Monday
+	t := jan4.AddDate(0, 0, (week-1)*7+(day-1)-offset)
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid ISO 8601 week date '%s': year %d has no week %d", s, year, week)
+	}
+	return t, nil
+}
+
+// formatISOWeek formats t as an ISO 8601 week date, e.g. 2023-W07-3
+func formatISOWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	day := (int(t.Weekday())+6)%7 + 1
+	return fmt.Sprintf("%04d-W%02d-%d", year, week, day)
+}
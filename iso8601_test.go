@@ -0,0 +1,128 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+type Shipment struct {
+	ID      string        `csv:"id"`
+	Transit time.Duration `csv:"transit,format=iso8601"`
+	Week    time.Time     `csv:"week,format=isoweek"`
+	Shipped time.Time     `csv:"shipped,format=02.01.2006"`
+}
+
+func TestISO8601Formats(t *testing.T) {
+	csvData := `id,transit,week,shipped
+a,P3DT4H,2023-W07-3,14.02.2023
+b,PT1.5S,2020-W53,31.12.2020
+c,-P1W,2019-W01-1,31.12.2018
+`
+	rb, err := rowboat.NewReader[Shipment](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Shipment{
+		{ID: "a", Transit: 76 * time.Hour, Week: time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC), Shipped: time.Date(2023, 2, 14, 0, 0, 0, 0, time.UTC)},
+		{ID: "b", Transit: 1500 * time.Millisecond, Week: time.Date(2020, 12, 28, 0, 0, 0, 0, time.UTC), Shipped: time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{ID: "c", Transit: -7 * 24 * time.Hour, Week: time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC), Shipped: time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Shipment](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(results)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	expectedCSV := `id,transit,week,shipped
+a,P3DT4H,2023-W07-3,14.02.2023
+b,PT1.5S,2020-W53-1,31.12.2020
+c,-P7D,2019-W01-1,31.12.2018
+`
+	if buf.String() != expectedCSV {
+		t.Errorf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+}
+
+func TestISO8601Invalid(t *testing.T) {
+	for _, row := range []string{
+		"a,P1M,2023-W07-3,14.02.2023",                           // months have no fixed duration
+		"a,P1H,2023-W07-3,14.02.2023",                           // hours require the T separator
+		"a,PT1H2D,2023-W07-3,14.02.2023",                        // designators out of order
+		"a,P1D,2021-W53-1,14.02.2023",                           // 2021 has 52 weeks
+		"a,P1D,2023-W07-8,14.02.2023",                           // days run from 1 to 7
+		"a,P200000D,2023-W07-3,14.02.2023",                      // overflows time.Duration
+		"a,PT9223372036.9S,2023-W07-3,14.02.2023",               // fraction overflows time.Duration
+		"a,P106751DT23H59M,2023-W07-3,14.02.2023",               // sum overflows time.Duration
+		"a,-P106751DT23H47M16.854775809S,2023-W07-3,14.02.2023", // below math.MinInt64
+	} {
+		rb, err := rowboat.NewReader[Shipment](strings.NewReader("id,transit,week,shipped\n" + row))
+		if err != nil {
+			t.Fatalf("Failed to create Reader: %v", err)
+		}
+		if _, err := rb.ReadAll(); err == nil {
+			t.Errorf("Expected error for row %q", row)
+		}
+	}
+
+	type Invalid struct {
+		Transit int64 `csv:"transit,format=iso8601"`
+	}
+	if _, err := rowboat.NewReader[Invalid](strings.NewReader("transit\n")); err == nil {
+		t.Errorf("Expected error for iso8601 format on an int64 field")
+	}
+}
+
+func TestISO8601DurationBounds(t *testing.T) {
+	for _, tc := range []struct {
+		transit time.Duration
+		cell    string
+	}{
+		{0, "PT0S"},
+		{-90 * time.Second, "-PT1M30S"},
+		{math.MaxInt64, "P106751DT23H47M16.854775807S"},
+		{math.MinInt64, "-P106751DT23H47M16.854775808S"},
+	} {
+		var buf bytes.Buffer
+		writer, err := rowboat.NewWriter[Shipment](&buf)
+		if err != nil {
+			t.Fatalf("Failed to create Writer: %v", err)
+		}
+		if err := writer.Write(Shipment{ID: "a", Transit: tc.transit}); err != nil {
+			t.Fatalf("Failed to write record: %v", err)
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatalf("Failed to flush Writer: %v", err)
+		}
+		if cell := strings.Split(buf.String(), ",")[1]; cell != tc.cell {
+			t.Errorf("Expected: %+v\nGot: %+v", tc.cell, cell)
+		}
+
+		got, err := mustReader[Shipment](t, "id,transit,week,shipped\n"+buf.String()).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", tc.cell, err)
+		}
+		if got[0].Transit != tc.transit {
+			t.Errorf("Expected: %+v\nGot: %+v", tc.transit, got[0].Transit)
+		}
+	}
+}
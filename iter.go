@@ -0,0 +1,129 @@
+package rowboat
+
+import "iter"
+
+// Filter returns a sequence that contains the elements
+// of s for which f returns true.
+func Filter[V any](f func(V) bool, s iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range s {
+			if f(v) {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Map returns a sequence that contains the results of applying f to the
+// elements of s
+func Map[V, U any](f func(V) U, s iter.Seq[V]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range s {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns a sequence that contains the first n elements of s
+func Take[V any](n int, s iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range s {
+			if !yield(v) {
+				return
+			}
+			if i++; i == n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns a sequence that contains the elements of s after the
+// first n
+func Skip[V any](n int, s iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		i := 0
+		for v := range s {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns a sequence of consecutive slices of up to n elements of s.
+// Each slice is newly allocated and may be retained.
+func Chunk[V any](n int, s iter.Seq[V]) iter.Seq[[]V] {
+	n = max(n, 1)
+	return func(yield func([]V) bool) {
+		chunk := make([]V, 0, n)
+		for v := range s {
+			chunk = append(chunk, v)
+			if len(chunk) < n {
+				continue
+			}
+			if !yield(chunk) {
+				return
+			}
+			chunk = make([]V, 0, n)
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Tap returns a sequence that contains the elements of s, calling f on
+// each element before it is yielded, e.g. for logging or metrics
+func Tap[V any](f func(V), s iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for v := range s {
+			f(v)
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Reduce combines the elements of s into a single value by applying f to
+// the accumulator, starting at init, and each element in turn
+func Reduce[V, A any](f func(A, V) A, init A, s iter.Seq[V]) A {
+	acc := init
+	for v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// GroupBy collects the elements of s into slices keyed by the result of
+// key, keeping their order within each group
+func GroupBy[V any, K comparable](key func(V) K, s iter.Seq[V]) map[K][]V {
+	groups := make(map[K][]V)
+	for v := range s {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// CountBy counts the elements of s per result of key
+func CountBy[V any, K comparable](key func(V) K, s iter.Seq[V]) map[K]int {
+	counts := make(map[K]int)
+	for v := range s {
+		counts[key(v)]++
+	}
+	return counts
+}
@@ -0,0 +1,66 @@
+package rowboat_test
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestIteratorHelpers(t *testing.T) {
+	numbers := slices.Values([]int{1, 2, 3, 4, 5, 6, 7})
+
+	strs := slices.Collect(rowboat.Map(strconv.Itoa, numbers))
+	if want := []string{"1", "2", "3", "4", "5", "6", "7"}; !reflect.DeepEqual(strs, want) {
+		t.Errorf("Map: expected %v, got %v", want, strs)
+	}
+
+	if got := slices.Collect(rowboat.Take(3, numbers)); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Take: expected [1 2 3], got %v", got)
+	}
+	if got := slices.Collect(rowboat.Take(0, numbers)); len(got) != 0 {
+		t.Errorf("Take: expected no elements, got %v", got)
+	}
+
+	if got := slices.Collect(rowboat.Skip(5, numbers)); !reflect.DeepEqual(got, []int{6, 7}) {
+		t.Errorf("Skip: expected [6 7], got %v", got)
+	}
+
+	chunks := slices.Collect(rowboat.Chunk(3, numbers))
+	if want := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}; !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Chunk: expected %v, got %v", want, chunks)
+	}
+
+	var seen []int
+	got := slices.Collect(rowboat.Take(2, rowboat.Tap(func(n int) { seen = append(seen, n) }, numbers)))
+	if !reflect.DeepEqual(got, []int{1, 2}) || !reflect.DeepEqual(seen, []int{1, 2}) {
+		t.Errorf("Tap: expected [1 2] yielded and seen, got %v and %v", got, seen)
+	}
+}
+
+func TestAggregationHelpers(t *testing.T) {
+	people := slices.Values([]Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.org", Age: 25},
+		{Name: "Charlie", Email: "charlie@example.com", Age: 35},
+	})
+	domain := func(p Person) string { return p.Email[strings.Index(p.Email, "@")+1:] }
+
+	total := rowboat.Reduce(func(sum int, p Person) int { return sum + p.Age }, 0, people)
+	if total != 90 {
+		t.Errorf("Reduce: expected 90, got %d", total)
+	}
+
+	groups := rowboat.GroupBy(domain, people)
+	if len(groups) != 2 || len(groups["example.com"]) != 2 || groups["example.com"][1].Name != "Charlie" {
+		t.Errorf("GroupBy: unexpected groups %+v", groups)
+	}
+
+	counts := rowboat.CountBy(domain, people)
+	if want := map[string]int{"example.com": 2, "example.org": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("CountBy: expected %v, got %v", want, counts)
+	}
+}
@@ -0,0 +1,50 @@
+package rowboat
+
+import "iter"
+
+// JoinKind selects which left elements a Join yields
+type JoinKind int
+
+const (
+	// InnerJoin yields only left elements with a matching right element
+	InnerJoin JoinKind = iota
+	// LeftJoin also yields left elements without a match, with a zero
+	// Right and Matched set to false
+	LeftJoin
+)
+
+// JoinPair is an element of a joined sequence
+type JoinPair[L, R any] struct {
+	Left    L
+	Right   R
+	Matched bool // whether Right holds a matching element
+}
+
+// Join joins two sequences on keys extracted from their elements. The right
+// sequence is read into memory first, so it should be the smaller one, such
+// as a customers file joined to a transactions stream. The left sequence is
+// streamed, yielding one pair per matching right element in right order.
+func Join[L, R any, K comparable](left iter.Seq[L], right iter.Seq[R], leftKey func(L) K, rightKey func(R) K, kind JoinKind) iter.Seq[JoinPair[L, R]] {
+	return func(yield func(JoinPair[L, R]) bool) {
+		index := make(map[K][]R)
+		for r := range right {
+			k := rightKey(r)
+			index[k] = append(index[k], r)
+		}
+
+		for l := range left {
+			matches := index[leftKey(l)]
+			if len(matches) == 0 {
+				if kind == LeftJoin && !yield(JoinPair[L, R]{Left: l}) {
+					return
+				}
+				continue
+			}
+			for _, r := range matches {
+				if !yield(JoinPair[L, R]{Left: l, Right: r, Matched: true}) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package rowboat_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Customer struct {
+	ID   string `csv:"id"`
+	Name string `csv:"name"`
+}
+
+type Transaction struct {
+	CustomerID string  `csv:"customer_id"`
+	Amount     float64 `csv:"amount"`
+}
+
+func TestJoin(t *testing.T) {
+	customers, err := rowboat.NewReader[Customer](strings.NewReader("id,name\nc1,Alice\nc2,Bob\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	customerList := slices.Collect(customers.All())
+	transactions := []Transaction{
+		{CustomerID: "c2", Amount: 10},
+		{CustomerID: "c9", Amount: 20},
+		{CustomerID: "c1", Amount: 30},
+	}
+	txKey := func(tx Transaction) string { return tx.CustomerID }
+	customerKey := func(c Customer) string { return c.ID }
+
+	inner := slices.Collect(rowboat.Join(slices.Values(transactions), slices.Values(customerList), txKey, customerKey, rowboat.InnerJoin))
+	expected := []rowboat.JoinPair[Transaction, Customer]{
+		{Left: transactions[0], Right: Customer{ID: "c2", Name: "Bob"}, Matched: true},
+		{Left: transactions[2], Right: Customer{ID: "c1", Name: "Alice"}, Matched: true},
+	}
+	if !reflect.DeepEqual(inner, expected) {
+		t.Errorf("Inner join does not match expected.\nExpected: %+v\nGot: %+v", expected, inner)
+	}
+
+	left := slices.Collect(rowboat.Join(slices.Values(transactions), slices.Values(customerList), txKey, customerKey, rowboat.LeftJoin))
+	expected = slices.Insert(expected, 1, rowboat.JoinPair[Transaction, Customer]{Left: transactions[1]})
+	if !reflect.DeepEqual(left, expected) {
+		t.Errorf("Left join does not match expected.\nExpected: %+v\nGot: %+v", expected, left)
+	}
+}
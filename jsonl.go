@@ -0,0 +1,204 @@
+package rowboat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ToJSONLines writes each record of seq to w as a JSON object on its own
+// line (NDJSON). Keys are the CSV column names. Numbers and booleans are
+// written as JSON numbers and booleans; custom marshalers, times and
+// everything else are written as the same strings a Writer would produce.
+func ToJSONLines[T any](seq iter.Seq[T], w io.Writer, opts ...Option) error {
+	fields, err := cachedFields(recordType[T]())
+	if err != nil {
+		return err
+	}
+	o := newTypeOptions[T](opts)
+
+	bw := bufio.NewWriterSize(w, max(o.bufferSize, minBufferSize))
+	var line bytes.Buffer
+	for record := range seq {
+		line.Reset()
+		line.WriteByte('{')
+		v, err := structValue(reflect.ValueOf(record))
+		if err != nil {
+			return err
+		}
+		for i, fi := range fields {
+			if i > 0 {
+				line.WriteByte(',')
+			}
+			key, _ := json.Marshal(fi.Name)
+			line.Write(key)
+			line.WriteByte(':')
+			value, err := []byte("null"), error(nil)
+			if field, ok := fi.value(v); ok {
+				value, err = encodeJSONValue(field, fi, o)
+			}
+			if err != nil {
+				return fmt.Errorf("error marshaling field %s: %w", fi.Field.Name, err)
+			}
+			line.Write(value)
+		}
+		line.WriteString("}\n")
+		if _, err := bw.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// encodeJSONValue converts a struct field to its JSON representation
+func encodeJSONValue(field reflect.Value, fi fieldInfo, opts *options) ([]byte, error) {
+	if fi.omitEmpty(opts) && field.IsZero() {
+		return []byte("null"), nil
+	}
+	if field.Kind() == reflect.Pointer && fi.Encode == nil {
+		if field.IsNil() {
+			return []byte("null"), nil
+		}
+		return encodeJSONValue(field.Elem(), fi, opts)
+	}
+	s, err := getFieldStringValue(field, fi, opts)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Encode != nil || fi.Locale != nil || fi.Pad > 0 || fi.Format != "" || field.Type() == reflect.TypeOf(time.Time{}) ||
+		lookupEnum(field.Type()) != nil || field.Type().Implements(csvMarshalerType) || reflect.PointerTo(field.Type()).Implements(csvMarshalerType) {
+		return json.Marshal(s)
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Bool:
+		return []byte(s), nil
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return []byte(s), nil
+		}
+		if s == "" {
+			return []byte("null"), nil
+		}
+	}
+	return json.Marshal(s)
+}
+
+// FromJSONLines reads newline-delimited JSON objects from r and decodes
+// them into T using the CSV column names as keys. JSON strings are decoded
+// exactly like CSV cells, so custom unmarshalers and time layouts apply;
+// numbers and booleans are decoded from their literal text, and null or
+// missing keys leave the field at its zero value. Value maps and trimming
+// apply to both. Errors report the line where the object starts, and
+// iteration stops after yielding the first error.
+func FromJSONLines[T any](r io.Reader, opts ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		rb, err := newReader[T](opts)
+		if err == nil {
+			err = rb.checkFields()
+		}
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		lines := &lineTracker{r: r, line: 1}
+		dec := json.NewDecoder(lines)
+		dec.UseNumber()
+		for {
+			var obj map[string]json.RawMessage
+			err := dec.Decode(&obj)
+			line := lines.start()
+			lines.advance(dec.InputOffset())
+			if err == io.EOF {
+				return
+			} else if err != nil {
+				yield(zero, &RowError{Line: line, Err: err})
+				return
+			}
+
+			ptr := reflect.New(recordType[T]())
+			tValue := ptr.Elem()
+			for _, fi := range rb.fields {
+				var raw json.RawMessage
+				var ok bool
+				for _, name := range fi.columnNames() {
+					if raw, ok = obj[name]; ok {
+						break
+					}
+				}
+				if !ok || string(raw) == "null" {
+					continue
+				}
+				value := string(raw)
+				if raw[0] == '"' {
+					if err := json.Unmarshal(raw, &value); err != nil {
+						yield(zero, &RowError{Line: line, Column: fi.Name, Err: err})
+						return
+					}
+				} else if raw[0] == '{' || raw[0] == '[' {
+					yield(zero, &RowError{Line: line, Column: fi.Name, Err: fmt.Errorf("unsupported JSON value %s", raw)})
+					return
+				}
+				value = fi.trim(value, rb.opts)
+				if mapped, ok := fi.Values[value]; ok && !fi.Raw {
+					value = mapped
+				}
+				if err := setFieldValue(fi.settable(tValue), value, fi, rb.opts); err != nil {
+					yield(zero, &RowError{Line: line, Column: fi.Name, Err: fmt.Errorf("error setting field %s: %w", fi.Field.Name, err)})
+					return
+				}
+			}
+			if !yield(recordOf[T](ptr), nil) {
+				return
+			}
+		}
+	}
+}
+
+// lineTracker counts the lines of the input a json.Decoder consumes, so
+// that errors can name the line where an object starts
+type lineTracker struct {
+	r      io.Reader
+	buf    []byte // bytes read by the decoder and not yet advanced past
+	offset int64  // input offset of buf
+	line   int    // line at the start of buf
+}
+
+func (l *lineTracker) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.buf = append(l.buf, p[:n]...)
+	return n, err
+}
+
+// start returns the line of the first non-whitespace byte not yet advanced
+// past, where the decoder's next value starts
+func (l *lineTracker) start() int {
+	line := l.line
+	for _, b := range l.buf {
+		switch b {
+		case '\n':
+			line++
+		case ' ', '\t', '\r':
+		default:
+			return line
+		}
+	}
+	return line
+}
+
+// advance moves past the input before offset, counting its lines
+func (l *lineTracker) advance(offset int64) {
+	n := min(int(offset-l.offset), len(l.buf))
+	l.line += bytes.Count(l.buf[:n], []byte{'\n'})
+	l.buf = l.buf[n:]
+	l.offset += int64(n)
+}
@@ -0,0 +1,114 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestJSONLinesRoundTrip(t *testing.T) {
+	records := []ComplexRecord{
+		{
+			Name:      "John \"JJ\"",
+			CreatedAt: time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+			Active:    true,
+			Score:     98.6,
+			Count:     42,
+			Rate:      1.5,
+			Tags:      "test;debug",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := rowboat.ToJSONLines(slices.Values(records), &buf); err != nil {
+		t.Fatalf("Failed to write JSON lines: %v", err)
+	}
+
+	expected := `{"name":"John \"JJ\"","created_at":"2023-01-02T15:04:05Z","active":true,"score":98.6,"count":42,"rate":1.5,"tags":"test;debug"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Written JSON does not match expected.\nExpected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+
+	var results []ComplexRecord
+	for record, err := range rowboat.FromJSONLines[ComplexRecord](&buf) {
+		if err != nil {
+			t.Fatalf("Failed to read JSON lines: %v", err)
+		}
+		results = append(results, record)
+	}
+	if !reflect.DeepEqual(results, records) {
+		t.Errorf("Read results do not match expected.\nExpected: %+v\nGot: %+v", records, results)
+	}
+}
+
+func TestJSONLinesCustomTypes(t *testing.T) {
+	jsonData := `{"point":"1;2"}
+{"point":null}
+{}
+`
+	var results []Custom
+	for record, err := range rowboat.FromJSONLines[Custom](strings.NewReader(jsonData)) {
+		if err != nil {
+			t.Fatalf("Failed to read JSON lines: %v", err)
+		}
+		results = append(results, record)
+	}
+	expected := []Custom{{Point: Point{X: 1, Y: 2}}, {}, {}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Read results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestFromJSONLinesError(t *testing.T) {
+	jsonData := `{"Name":"Alice","Age":30}
+{"Name":"Bob","Age":"old"}
+`
+	count := 0
+	var lastErr error
+	for _, err := range rowboat.FromJSONLines[Person](strings.NewReader(jsonData)) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		count++
+	}
+	var rowErr *rowboat.RowError
+	if count != 1 || !errors.As(lastErr, &rowErr) || rowErr.Line != 2 || rowErr.Column != "Age" {
+		t.Errorf("Expected one record and a RowError on line 2, got %d records and %v", count, lastErr)
+	}
+}
+
+func TestFromJSONLinesLinesAndValueMaps(t *testing.T) {
+	jsonData := "{\"Name\":\"Alice\",\"Age\":\"thirty\"}\n\n{\n  \"Name\": \"Bob\",\n  \"Age\": 25\n}\n\n{\"Name\":\"Carol\",\"Age\":\"old\"}\n"
+	var people []Person
+	var lastErr error
+	for person, err := range rowboat.FromJSONLines[Person](strings.NewReader(jsonData),
+		rowboat.WithValueMap("Age", map[string]string{"thirty": "30"})) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		people = append(people, person)
+	}
+	expected := []Person{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 25}}
+	if !reflect.DeepEqual(people, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, people)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(lastErr, &rowErr) || rowErr.Line != 8 {
+		t.Errorf("Expected a RowError on line 8, got %v", lastErr)
+	}
+
+	for _, err := range rowboat.FromJSONLines[Person](strings.NewReader("{\"Name\":\"Alice\"}\n\n{\"Name\":")) {
+		lastErr = err
+	}
+	if !errors.As(lastErr, &rowErr) || rowErr.Line != 3 {
+		t.Errorf("Expected a RowError on line 3, got %v", lastErr)
+	}
+}
@@ -0,0 +1,241 @@
+package rowboat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+var (
+	// ErrRowLimit is returned when the input has more rows than WithMaxRows allows
+	ErrRowLimit = errors.New("row limit exceeded")
+	// ErrByteLimit is returned when the input is larger than WithMaxBytes allows
+	ErrByteLimit = errors.New("byte limit exceeded")
+	// ErrErrorLimit is returned when more rows fail than WithMaxErrors allows
+	ErrErrorLimit = errors.New("error limit exceeded")
+	// ErrRowSize is returned when a row is longer than WithMaxRowBytes allows
+	ErrRowSize = errors.New("row size limit exceeded")
+	// ErrCellSize is returned when a cell is longer than WithMaxCellBytes allows
+	ErrCellSize = errors.New("cell size limit exceeded")
+	// ErrColumnLimit is returned when a row has more columns than
+	// WithMaxColumns allows
+	ErrColumnLimit = errors.New("column limit exceeded")
+)
+
+// WithMaxRows limits the number of data rows a Reader accepts. Reading
+// stops with ErrRowLimit when the input contains more rows.
+func WithMaxRows(n int) Option {
+	return func(o *options) {
+		o.maxRows = n
+	}
+}
+
+// WithMaxBytes limits the number of bytes a Reader consumes from its input.
+// Reading stops with ErrByteLimit when the input is larger.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithMaxErrors lets a Reader skip up to n rows that fail to parse or
+// decode. The skipped errors are available from Reader.Errors, and reading
+// stops with a *MultiRowError aggregating them, which matches
+// ErrErrorLimit, once more than n rows have failed.
+func WithMaxErrors(n int) Option {
+	return func(o *options) {
+		o.maxErrors = n
+	}
+}
+
+// WithMaxRowBytes limits the length of a row, counting the bytes of its
+// cells but not delimiters, enclosing quotes or line breaks between rows.
+// Reading stops with ErrRowSize at a longer row before it is buffered in
+// full, so a missing line break or quote cannot exhaust memory.
+func WithMaxRowBytes(n int) Option {
+	return func(o *options) {
+		o.maxRowBytes = n
+	}
+}
+
+// WithMaxCellBytes limits the length of a cell. Reading stops with
+// ErrCellSize at a row holding a longer cell.
+func WithMaxCellBytes(n int) Option {
+	return func(o *options) {
+		o.maxCellBytes = n
+	}
+}
+
+// WithMaxColumns limits the number of columns of the header and each row.
+// Reading stops with ErrColumnLimit at a wider row.
+func WithMaxColumns(n int) Option {
+	return func(o *options) {
+		o.maxColumns = n
+	}
+}
+
+// checkRecord checks a record read from the input against the size limits
+func (o *options) checkRecord(record []string) error {
+	if o.maxColumns > 0 && len(record) > o.maxColumns {
+		return fmt.Errorf("%w: %d columns, more than %d", ErrColumnLimit, len(record), o.maxColumns)
+	}
+	if o.maxRowBytes <= 0 && o.maxCellBytes <= 0 {
+		return nil
+	}
+	size := 0
+	for i, cell := range record {
+		if o.maxCellBytes > 0 && len(cell) > o.maxCellBytes {
+			return fmt.Errorf("%w: cell %d is %d bytes, more than %d", ErrCellSize, i+1, len(cell), o.maxCellBytes)
+		}
+		size += len(cell)
+	}
+	if o.maxRowBytes > 0 && size > o.maxRowBytes {
+		return fmt.Errorf("%w: %d bytes, more than %d", ErrRowSize, size, o.maxRowBytes)
+	}
+	return nil
+}
+
+// rowLimiter fails with ErrRowSize once the cells of a CSV row of r hold
+// more than max bytes, so that a missing line break or quote cannot make a
+// reader buffer all of r. It follows quoting to count cell bytes like
+// checkRecord does.
+type rowLimiter struct {
+	r       io.Reader
+	max     int
+	comma   []byte // encoded delimiter
+	comment byte   // ASCII comment character, or zero
+	trim    bool   // leading spaces of fields are dropped
+
+	n       int  // cell bytes of the current row
+	line    int  // line breaks read so far
+	start   int  // line breaks before the current row
+	pending int  // bytes of a partially read delimiter
+	quoted  bool // inside a quoted field
+	closed  bool // a quote ended the quoted field, unless doubled
+	field   bool // at the start of a field
+	row     bool // at the start of a row
+	skip    bool // inside a comment line
+	cr      bool // the last byte was a carriage return
+	err     error
+}
+
+// newRowLimiter returns a rowLimiter for r with the format of o
+func (o *options) newRowLimiter(r io.Reader) *rowLimiter {
+	comma := o.delimiter
+	if comma == 0 {
+		comma = ','
+	}
+	l := &rowLimiter{r: r, max: o.maxRowBytes, comma: utf8.AppendRune(nil, comma), trim: o.trimLeading, field: true, row: true}
+	if o.comment > 0 && o.comment < utf8.RuneSelf {
+		l.comment = byte(o.comment)
+	}
+	return l
+}
+
+func (l *rowLimiter) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	n, err := l.r.Read(p)
+	for i, b := range p[:n] {
+		if !l.scan(b) {
+			// Pass on the rows before the long one, failing on the next Read
+			l.err = &RowError{Line: l.start + 1, Err: fmt.Errorf("%w: more than %d bytes", ErrRowSize, l.max)}
+			if i == 0 {
+				return 0, l.err
+			}
+			return i, nil
+		}
+	}
+	return n, err
+}
+
+// scan advances the parse state by b and reports whether the row is still
+// within the limit
+func (l *rowLimiter) scan(b byte) bool {
+	cells := 0
+	if b == '\n' {
+		l.line++
+	}
+	switch {
+	case l.skip:
+		l.skip = b != '\n'
+	case l.quoted:
+		switch {
+		case b == '"':
+			l.quoted, l.closed = false, true
+		case b != '\n' || !l.cr:
+			// A quoted \r\n is read as \n
+			cells = 1
+		}
+		l.cr = b == '\r'
+	case l.closed && b == '"':
+		// A doubled quote is read as one quote
+		l.quoted, l.closed = true, false
+		cells = 1
+	default:
+		l.closed = false
+		cr := l.cr
+		l.cr = b == '\r'
+		switch {
+		case b == '\n':
+			// Line breaks between rows, including a \r before them, are not
+			// part of a cell
+			l.n, l.pending, l.start = 0, 0, l.line
+			l.field, l.row = true, true
+			return true
+		case cr:
+			cells = 1
+		}
+		if l.row && l.comment != 0 && b == l.comment {
+			l.skip, l.row = true, false
+			break
+		}
+		l.row = false
+		if b == l.comma[l.pending] {
+			if l.pending++; l.pending == len(l.comma) {
+				l.pending, l.field = 0, true
+			}
+			break
+		}
+		cells += l.pending
+		l.pending = 0
+		switch {
+		case l.cr:
+			// Counted with the next byte unless it ends the row
+		case l.field && l.trim && (b == ' ' || b == '\t'):
+		case l.field && b == '"':
+			l.quoted, l.field = true, false
+		default:
+			l.field = false
+			cells++
+		}
+	}
+	l.n += cells
+	return l.n <= l.max
+}
+
+// limitedReader reads at most n bytes from r and fails with ErrByteLimit
+// if r holds more data
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrByteLimit
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
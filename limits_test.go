@@ -0,0 +1,277 @@
+package rowboat_test
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+// collect reads all records, recovering the panic raised by All on error
+func collect[T any](rb *rowboat.Reader[T]) (results []T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(error)
+		}
+	}()
+	for v := range rb.All() {
+		results = append(results, v)
+	}
+	return results, nil
+}
+
+func TestMaxRows(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25
+Charlie,charlie@example.com,35`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxRows(3))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if results, err := collect(rb); err != nil || len(results) != 3 {
+		t.Errorf("Expected 3 rows within the limit, got %d rows and error %v", len(results), err)
+	}
+
+	rb, err = rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxRows(2))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := collect(rb); !errors.Is(err, rowboat.ErrRowLimit) {
+		t.Errorf("Expected ErrRowLimit, got %v", err)
+	}
+
+	// Filtered and failed rows do not count towards the limit
+	withBad := strings.Replace(csvData, "Charlie", "Dan,dan@example.com,old\nCharlie", 1)
+	rb, err = rowboat.NewReader[Person](strings.NewReader(withBad), rowboat.WithMaxRows(2), rowboat.WithMaxErrors(1),
+		rowboat.WithRowFilter(func(raw []string) bool { return raw[0] != "Bob" }))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if results, err := collect(rb); err != nil || len(results) != 2 {
+		t.Errorf("Expected 2 rows within the limit, got %d rows and error %v", len(results), err)
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	csvData := "Name,Email,Age\n" + strings.Repeat("Alice,alice@example.com,30\n", 100)
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxBytes(int64(len(csvData))))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if results, err := collect(rb); err != nil || len(results) != 100 {
+		t.Errorf("Expected 100 rows within the limit, got %d rows and error %v", len(results), err)
+	}
+
+	rb, err = rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxBytes(1000))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := collect(rb); !errors.Is(err, rowboat.ErrByteLimit) {
+		t.Errorf("Expected ErrByteLimit, got %v", err)
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,thirty
+Bob,bob@example.com,25
+Charlie,charlie@example.com,old
+Dave,dave@example.com,40`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxErrors(2))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := collect(rb)
+	if err != nil {
+		t.Fatalf("Expected errors within budget to be skipped, got %v", err)
+	}
+	expected := []Person{
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+		{Name: "Dave", Email: "dave@example.com", Age: 40},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+	if len(rb.Errors()) != 2 {
+		t.Errorf("Expected 2 skipped errors, got %v", rb.Errors())
+	}
+
+	rb, err = rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxErrors(1))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	_, err = collect(rb)
+	if !errors.Is(err, rowboat.ErrErrorLimit) {
+		t.Errorf("Expected ErrErrorLimit, got %v", err)
+	}
+	var multiErr *rowboat.MultiRowError
+	if !errors.As(err, &multiErr) || len(multiErr.Errors) != 2 {
+		t.Fatalf("Expected a MultiRowError with 2 errors, got %v", err)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(multiErr.Errors[1], &rowErr) || rowErr.Line != 4 || rowErr.Column != "Age" {
+		t.Errorf("Expected a RowError for column Age on line 4, got %v", multiErr.Errors[1])
+	}
+	if !errors.As(err, &rowErr) || rowErr.Line != 2 {
+		t.Errorf("Expected the first RowError on line 2, got %v", rowErr)
+	}
+
+	// The zero value describes itself without failing
+	if got, want := (&rowboat.MultiRowError{}).Error(), "error limit exceeded: no rows failed"; got != want {
+		t.Errorf("Expected: %q\nGot: %q", want, got)
+	}
+}
+
+func TestSizeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		csvData  string
+		opt      rowboat.Option
+		expected error
+		line     int
+	}{
+		{"columns", "Name,Email,Age\nAlice,alice@example.com,30,x,y\n", rowboat.WithMaxColumns(4), rowboat.ErrColumnLimit, 2},
+		{"header columns", "Name,Email,Age,a,b\nAlice,alice@example.com,30\n", rowboat.WithMaxColumns(4), rowboat.ErrColumnLimit, 1},
+		{"cell", "Name,Email,Age\nAlice,alice@example.com,30\nBob,\"" + strings.Repeat("b", 100) + "\",25\n", rowboat.WithMaxCellBytes(64), rowboat.ErrCellSize, 3},
+		{"row", "Name,Email,Age\nAlice,\"alice\n@example.com\",30\n", rowboat.WithMaxRowBytes(20), rowboat.ErrRowSize, 2},
+		{"line", "Name,Email,Age\n" + strings.Repeat("x", 1<<20), rowboat.WithMaxRowBytes(1024), rowboat.ErrRowSize, 2},
+		{"quoted lines", "Name,Email,Age\nAlice,\"" + strings.Repeat("x\n", 1<<19), rowboat.WithMaxRowBytes(1024), rowboat.ErrRowSize, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rb, err := rowboat.NewReader[Person](strings.NewReader(tt.csvData), tt.opt)
+			if err == nil {
+				_, err = collect(rb)
+			}
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected: %v\nGot: %v", tt.expected, err)
+			}
+			var rowErr *rowboat.RowError
+			if tt.line > 0 && (!errors.As(err, &rowErr) || rowErr.Line != tt.line) {
+				t.Errorf("Expected the error on line %d, got %v", tt.line, err)
+			}
+		})
+	}
+
+	// Rows within the limits are read
+	rb, err := rowboat.NewReader[Person](strings.NewReader("Name,Email,Age\nAlice,alice@example.com,30\n"),
+		rowboat.WithMaxColumns(3), rowboat.WithMaxCellBytes(17), rowboat.WithMaxRowBytes(32))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if got, err := collect(rb); err != nil || len(got) != 1 {
+		t.Errorf("Expected one record, got %v, %v", got, err)
+	}
+
+	// Delimiters, quotes and line breaks between rows do not count, so a row
+	// of exactly 24 cell bytes is read with any quoting and delimiter
+	for _, tt := range []struct {
+		csvData   string
+		delimiter rune
+	}{
+		{"Name,Email,Age\r\nAlice,alice@example.com,30\r\n", ','},
+		{"\"Name\",\"Email\",\"Age\"\n\"Alice\",\"alice@\r\nexample.co\",\"30\"\n", ','},
+		{"Name§Email§Age\nAlice§\"alice\"\"example.com\"§30\n", '§'},
+	} {
+		rb, err := rowboat.NewReader[Person](strings.NewReader(tt.csvData), rowboat.WithDelimiter(tt.delimiter), rowboat.WithMaxRowBytes(24))
+		if err != nil {
+			t.Fatalf("Failed to create Reader: %v", err)
+		}
+		if got, err := collect(rb); err != nil || len(got) != 1 {
+			t.Errorf("Expected one record from %q, got %v, %v", tt.csvData, got, err)
+		}
+		rb, err = rowboat.NewReader[Person](strings.NewReader(tt.csvData), rowboat.WithDelimiter(tt.delimiter), rowboat.WithMaxRowBytes(23))
+		if err == nil {
+			_, err = collect(rb)
+		}
+		if !errors.Is(err, rowboat.ErrRowSize) {
+			t.Errorf("Expected: %v\nGot: %v", rowboat.ErrRowSize, err)
+		}
+	}
+}
+
+func TestFieldsPerRecord(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob\nCarol,carol@example.com,40,extra\n"
+
+	_, err := collect(mustReader[Person](t, csvData))
+	var countErr *rowboat.FieldCountError
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &countErr) || !errors.As(err, &rowErr) || !errors.Is(err, csv.ErrFieldCount) {
+		t.Fatalf("Expected a FieldCountError in a RowError, got %v", err)
+	}
+	expected := rowboat.FieldCountError{Expected: 3, Got: 1, Missing: []string{"Email", "Age"}}
+	if !reflect.DeepEqual(*countErr, expected) || rowErr.Line != 3 {
+		t.Errorf("Expected: %+v on line 3\nGot: %+v on line %d", expected, *countErr, rowErr.Line)
+	}
+	if want := "line 3: expected 3 fields, got 1: missing columns: Email, Age"; err.Error() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, err.Error())
+	}
+
+	// Short and long rows are skipped under WithMaxErrors
+	got, err := collect(mustReader[Person](t, csvData, rowboat.WithMaxErrors(2)))
+	if err != nil || len(got) != 1 {
+		t.Errorf("Expected one record and no error, got %d records and %v", len(got), err)
+	}
+
+	// Variable rows leave missing fields at their zero values
+	got, err = collect(mustReader[Person](t, "Name,Email,Age\nBob\n", rowboat.WithFieldsPerRecord(-1)))
+	if want := []Person{{Name: "Bob"}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected: %+v\nGot: %+v, %v", want, got, err)
+	}
+}
+
+func TestPadAndTruncateRows(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30,,\nBob\nCarol,carol@example.com\n"
+	got, err := collect(mustReader[Person](t, csvData, rowboat.WithPadShortRows(), rowboat.WithTruncateLongRows()))
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob"},
+		{Name: "Carol", Email: "carol@example.com"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	// Padding alone still rejects long rows
+	_, err = collect(mustReader[Person](t, csvData, rowboat.WithPadShortRows()))
+	var countErr *rowboat.FieldCountError
+	if !errors.As(err, &countErr) || countErr.Got != 5 {
+		t.Errorf("Expected a FieldCountError for 5 fields, got %v", err)
+	}
+}
+
+func TestPadShortRowsStatsAndRaw(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob\n"
+	rb := mustReader[Person](t, csvData, rowboat.WithPadShortRows(), rowboat.WithStats())
+	var raws [][]string
+	for _, raw := range rb.AllWithRaw() {
+		raws = append(raws, raw)
+	}
+	expected := [][]string{{"Alice", "alice@example.com", "30"}, {"Bob", "", ""}}
+	if !reflect.DeepEqual(raws, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, raws)
+	}
+	stats := rb.Stats()
+	if stats.Records != 2 || stats.Columns["Age"].Nulls != 1 || stats.Columns["Email"].Nulls != 1 {
+		t.Errorf("Expected 2 records with a null Age and Email\nGot: %+v, %+v", stats.Records, stats.Columns)
+	}
+}
+
+func mustReader[T any](t *testing.T, csvData string, opts ...rowboat.Option) *rowboat.Reader[T] {
+	t.Helper()
+	rb, err := rowboat.NewReader[T](strings.NewReader(csvData), opts...)
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	return rb
+}
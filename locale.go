@@ -0,0 +1,140 @@
+package rowboat
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// locale describes the number and date conventions of a region
+type locale struct {
+	Name       string // key in locales
+	Decimal    rune   // decimal separator
+	Group      string // thousands separators accepted when parsing
+	DateLayout string // time layout used for time.Time fields
+}
+
+// locales holds the supported locales keyed by lower-case name
+var locales = map[string]*locale{
+	"en":    {Decimal: '.', Group: ",", DateLayout: "01/02/2006"},
+	"en-us": {Decimal: '.', Group: ",", DateLayout: "01/02/2006"},
+	"en-gb": {Decimal: '.', Group: ",", DateLayout: "02/01/2006"},
+	"de":    {Decimal: ',', Group: ".", DateLayout: "02.01.2006"},
+	"de-ch": {Decimal: '.', Group: "'", DateLayout: "02.01.2006"},
+	"fr":    {Decimal: ',', Group: " \u00a0\u202f", DateLayout: "02/01/2006"},
+	"es":    {Decimal: ',', Group: ".", DateLayout: "02/01/2006"},
+	"it":    {Decimal: ',', Group: ".", DateLayout: "02/01/2006"},
+	"nl":    {Decimal: ',', Group: ".", DateLayout: "02-01-2006"},
+	"pt":    {Decimal: ',', Group: ".", DateLayout: "02/01/2006"},
+	"pt-br": {Decimal: ',', Group: ".", DateLayout: "02/01/2006"},
+	"sv":    {Decimal: ',', Group: " \u00a0", DateLayout: "2006-01-02"},
+	"ja":    {Decimal: '.', Group: ",", DateLayout: "2006/01/02"},
+}
+
+func init() {
+	for name, loc := range locales {
+		loc.Name = name
+	}
+}
+
+// lookupLocale returns the locale registered under name
+func lookupLocale(name string) (*locale, error) {
+	key := strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+	loc, ok := locales[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown locale '%s'", name)
+	}
+	return loc, nil
+}
+
+// normalizeNumber converts a locale formatted number into the form
+// accepted by strconv by dropping group separators and replacing the
+// decimal separator with a period
+func (l *locale) normalizeNumber(value string) string {
+	var sb strings.Builder
+	sb.Grow(len(value))
+	for _, r := range value {
+		switch {
+		case r == l.Decimal:
+			sb.WriteByte('.')
+		case strings.ContainsRune(l.Group, r):
+			// drop thousands separator
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// formatNumber converts a number formatted by strconv into the locale's
+// representation
+func (l *locale) formatNumber(value string) string {
+	if l.Decimal == '.' {
+		return value
+	}
+	return strings.Replace(value, ".", string(l.Decimal), 1)
+}
+
+// normalizeNumber prepares a numeric cell for strconv, stripping currency
+// symbols and applying the field's locale
+func (fi fieldInfo) normalizeNumber(value string, opts *options) string {
+	if fi.Currency || opts.stripCurrency {
+		value = strings.TrimSpace(strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Sc, r) {
+				return -1
+			}
+			return r
+		}, value))
+	}
+	if fi.Locale != nil {
+		value = fi.Locale.normalizeNumber(value)
+	}
+	return value
+}
+
+// applyLocale assigns the WithLocale locale to numeric fields without a
+// locale tag
+func (o *options) applyLocale(fields []fieldInfo) error {
+	if o.locale == "" {
+		return nil
+	}
+	loc, err := lookupLocale(o.locale)
+	if err != nil {
+		return err
+	}
+	for i := range fields {
+		if fields[i].Locale != nil {
+			continue
+		}
+		if fields[i].Field.Type == decimalType {
+			fields[i].Locale = loc
+			continue
+		}
+		switch fields[i].Field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			fields[i].Locale = loc
+		}
+	}
+	return nil
+}
+
+// WithLocale parses and formats the numbers of all numeric fields without
+// a locale tag using the conventions of a locale, e.g. "de" for "1.234,56".
+// Dates are not affected. An unknown locale makes the Reader or Writer
+// constructor fail.
+func WithLocale(name string) Option {
+	return func(o *options) {
+		o.locale = name
+	}
+}
+
+// WithStripCurrency removes currency symbols such as €, $ and £ from
+// numeric cells before parsing them, like the currency tag option
+func WithStripCurrency() Option {
+	return func(o *options) {
+		o.stripCurrency = true
+	}
+}
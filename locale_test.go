@@ -0,0 +1,195 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+type MixedLocaleRecord struct {
+	Subsidiary string    `csv:"subsidiary"`
+	USAmount   float64   `csv:"us_amount,locale=en-US"`
+	EUAmount   float64   `csv:"eu_amount,locale=de"`
+	EUUnits    int       `csv:"eu_units,locale=de"`
+	EUDate     time.Time `csv:"eu_date,locale=de"`
+}
+
+func TestLocaleTag(t *testing.T) {
+	csvData := `subsidiary,us_amount,eu_amount,eu_units,eu_date
+Berlin,"1,234.56","1.234,56",1.000,24.12.2023
+Munich,0.5,"0,5",7,01.02.2024`
+
+	rb, err := rowboat.NewReader[MixedLocaleRecord](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+
+	expected := []MixedLocaleRecord{
+		{
+			Subsidiary: "Berlin",
+			USAmount:   1234.56,
+			EUAmount:   1234.56,
+			EUUnits:    1000,
+			EUDate:     time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Subsidiary: "Munich",
+			USAmount:   0.5,
+			EUAmount:   0.5,
+			EUUnits:    7,
+			EUDate:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[MixedLocaleRecord](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(expected)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	expectedCSV := `subsidiary,us_amount,eu_amount,eu_units,eu_date
+Berlin,1234.56,"1234,56",1000,24.12.2023
+Munich,0.5,"0,5",7,01.02.2024
+`
+	if buf.String() != expectedCSV {
+		t.Errorf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+}
+
+func TestUnknownLocale(t *testing.T) {
+	type BadLocale struct {
+		Amount float64 `csv:"amount,locale=xx"`
+	}
+	if _, err := rowboat.NewReader[BadLocale](strings.NewReader("amount\n1")); err == nil {
+		t.Errorf("Expected error for unknown locale")
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	type Invoice struct {
+		Number string  `csv:"number"`
+		Total  float64 `csv:"total,currency"`
+		Units  uint    `csv:"units"`
+		Fee    float64 `csv:"fee,locale=en"`
+	}
+	csvData := `number,total,units,fee
+INV-1,"1.234,56 €",1.000,2.50
+INV-2,"€ 7,5",3,1.25`
+
+	rb, err := rowboat.NewReader[Invoice](strings.NewReader(csvData), rowboat.WithLocale("de"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Invoice{
+		{Number: "INV-1", Total: 1234.56, Units: 1000, Fee: 2.5},
+		{Number: "INV-2", Total: 7.5, Units: 3, Fee: 1.25},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Invoice](&buf, rowboat.WithLocale("de"))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(expected[:1]); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "INV-1,\"1234,56\",1000,2.5\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+
+	if _, err := rowboat.NewReader[Invoice](strings.NewReader(csvData), rowboat.WithLocale("xx")); err == nil {
+		t.Errorf("Expected error for unknown locale")
+	}
+}
+
+func TestWithStripCurrency(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,$30\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithStripCurrency())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(results) != 1 || results[0].Age != 30 {
+		t.Errorf("Expected age 30, got %+v", results)
+	}
+}
+
+func TestLocaleTypeRow(t *testing.T) {
+	records := []MixedLocaleRecord{
+		{Subsidiary: "Munich", USAmount: 0.5, EUAmount: 1.5, EUUnits: 7, EUDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[MixedLocaleRecord](&buf, rowboat.WithTypeRow())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(records); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if types := strings.Split(buf.String(), "\n")[1]; types != "string,float:en-us,float:de,int,time:02.01.2006" {
+		t.Errorf("Unexpected type row: %s", types)
+	}
+
+	dr, err := rowboat.NewDynamicReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to create DynamicReader: %v", err)
+	}
+	var rows []map[string]any
+	for row, err := range dr.All() {
+		if err != nil {
+			t.Fatalf("Failed to read row: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	expected := []map[string]any{{
+		"subsidiary": "Munich",
+		"us_amount":  0.5,
+		"eu_amount":  1.5,
+		"eu_units":   int64(7),
+		"eu_date":    time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, rows)
+	}
+
+	rb, err := rowboat.NewReader[MixedLocaleRecord](strings.NewReader(buf.String()), rowboat.WithTypeRow())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("Expected: %+v\nGot: %+v", records, got)
+	}
+}
@@ -0,0 +1,61 @@
+package rowboat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithFieldMasker makes the Writer pass the cells of the field identified
+// by its Go field name or CSV column name through mask, to redact personal
+// data on export without changing the records. It replaces the mask tag
+// option of the field.
+func WithFieldMasker(fieldName string, mask func(string) string) Option {
+	return func(o *options) {
+		if o.maskers == nil {
+			o.maskers = make(map[string]func(string) string)
+		}
+		o.maskers[fieldName] = mask
+	}
+}
+
+// parseMask returns the masking function of a mask tag option: all hides
+// the whole cell, lastN keeps its last N characters and firstN its first N
+func parseMask(spec string) (func(string) string, error) {
+	if spec == "all" {
+		return func(s string) string { return maskRunes(s, 0, 0) }, nil
+	}
+	for _, prefix := range []string{"last", "first"} {
+		digits, ok := strings.CutPrefix(spec, prefix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 0 {
+			break
+		}
+		if prefix == "last" {
+			return func(s string) string { return maskRunes(s, 0, n) }, nil
+		}
+		return func(s string) string { return maskRunes(s, n, 0) }, nil
+	}
+	return nil, fmt.Errorf("invalid mask '%s'", spec)
+}
+
+// maskRunes replaces the characters of s with asterisks, except for the
+// first head and last tail characters. Empty cells stay empty.
+func maskRunes(s string, head, tail int) string {
+	n := utf8.RuneCountInString(s)
+	var b strings.Builder
+	i := 0
+	for _, r := range s {
+		if i < head || i >= n-tail {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('*')
+		}
+		i++
+	}
+	return b.String()
+}
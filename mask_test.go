@@ -0,0 +1,85 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Patient struct {
+	Name  string `csv:"name,mask=first1"`
+	SSN   string `csv:"ssn,mask=last4"`
+	Email string `csv:"email"`
+	Notes string `csv:"notes,mask=all"`
+}
+
+func TestMask(t *testing.T) {
+	patients := []Patient{
+		{Name: "Zoë", SSN: "123-45-6789", Email: "zoe@example.com", Notes: "allergic"},
+		{Name: "", SSN: "12", Email: "", Notes: ""},
+	}
+	maskEmail := func(email string) string {
+		if user, domain, ok := strings.Cut(email, "@"); ok {
+			return user[:1] + "***@" + domain
+		}
+		return email
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Patient](&buf, rowboat.WithFieldMasker("Email", maskEmail))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(patients); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	expected := "Z**,*******6789,z***@example.com,********\n,12,,\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+	if patients[0].SSN != "123-45-6789" {
+		t.Errorf("Expected the record to be left unchanged, got %q", patients[0].SSN)
+	}
+
+	if _, err := rowboat.NewWriter[Patient](&buf, rowboat.WithFieldMasker("Phone", maskEmail)); err == nil {
+		t.Errorf("Expected an error for the unknown field Phone")
+	}
+	type badMask struct {
+		SSN string `csv:"ssn,mask=middle"`
+	}
+	if _, err := rowboat.NewWriter[badMask](&buf); err == nil || !strings.Contains(err.Error(), "invalid mask") {
+		t.Errorf("Expected an invalid mask error, got %v", err)
+	}
+}
+
+func TestMaskNull(t *testing.T) {
+	type Lead struct {
+		ID    int     `csv:"id"`
+		Phone *string `csv:"phone,mask=all"`
+	}
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Lead](&buf, rowboat.WithNullString("NULL"))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice([]Lead{{ID: 1}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if expected := "1,NULL\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	copyWriter, err := rowboat.NewCopyTextWriter[Lead](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := copyWriter.WriteSlice([]Lead{{ID: 1}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if expected := "1\t\\N\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,186 @@
+package rowboat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"slices"
+)
+
+// MultiReader reads several CSV inputs as one stream, such as daily
+// partitioned files. Every input starts with its own header. The headers
+// must have the columns of the first input, in any order; an input whose
+// columns differ stops reading with a HeaderError.
+type MultiReader[T any] struct {
+	inputs  []multiInput
+	opts    []Option
+	headers []string
+	errs    []error
+}
+
+// multiInput is an input of a MultiReader, opened when it is reached
+type multiInput struct {
+	name string // prefixes the errors of the input
+	open func() (io.ReadCloser, error)
+}
+
+// NewMultiReader creates a MultiReader concatenating readers. Errors name
+// the input they occurred in, counting from 1.
+func NewMultiReader[T any](readers []io.Reader, opts ...Option) (*MultiReader[T], error) {
+	inputs := make([]multiInput, len(readers))
+	for i, r := range readers {
+		inputs[i] = multiInput{
+			name: fmt.Sprintf("input %d", i+1),
+			open: func() (io.ReadCloser, error) { return io.NopCloser(r), nil },
+		}
+	}
+	return newMultiReader[T](inputs, opts)
+}
+
+// NewDirReader creates a MultiReader over the files in fsys matching the
+// fs.Glob pattern, in lexical order. Compressed files such as .csv.gz are
+// decompressed, and errors are prefixed with the name of their file.
+func NewDirReader[T any](fsys fs.FS, pattern string, opts ...Option) (*MultiReader[T], error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]multiInput, len(names))
+	for i, name := range names {
+		inputs[i] = multiInput{
+			name: name,
+			open: func() (io.ReadCloser, error) { return fsys.Open(name) },
+		}
+	}
+	return newMultiReader[T](inputs, append([]Option{WithDecompression()}, opts...))
+}
+
+// ReadDir returns an iterator over the records of the files in fsys
+// matching the fs.Glob pattern, read as one stream like NewDirReader.
+// Errors are prefixed with the name of their file; use NewDirReader to
+// inspect the rows skipped under WithMaxErrors.
+func ReadDir[T any](fsys fs.FS, pattern string, opts ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		mr, err := NewDirReader[T](fsys, pattern, opts...)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		mr.AllContext(context.Background())(yield)
+	}
+}
+
+func newMultiReader[T any](inputs []multiInput, opts []Option) (*MultiReader[T], error) {
+	// Fail on invalid structs before any input is opened
+	if _, err := newReader[T](opts); err != nil {
+		return nil, err
+	}
+	return &MultiReader[T]{inputs: inputs, opts: opts}, nil
+}
+
+// Headers returns the header of the first input, once it has been read
+func (mr *MultiReader[T]) Headers() []string {
+	return slices.Clone(mr.headers)
+}
+
+// Errors returns the row errors skipped so far under WithMaxErrors,
+// prefixed with the name of their input. The error budget applies to each
+// input separately.
+func (mr *MultiReader[T]) Errors() []error {
+	return mr.errs
+}
+
+// AllContext returns an iterator over the records of all inputs in order
+// that stops once ctx is done. An error stopping iteration is yielded as
+// the final element, prefixed with the name of its input.
+func (mr *MultiReader[T]) AllContext(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for _, in := range mr.inputs {
+			r, err := in.open()
+			if err != nil {
+				yield(zero, fmt.Errorf("%s: %w", in.name, err))
+				return
+			}
+			ok := mr.readInput(ctx, in.name, r, yield)
+			r.Close()
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// ReadAll reads the records of all inputs and returns them with the first
+// error that stopped reading
+func (mr *MultiReader[T]) ReadAll() ([]T, error) {
+	var records []T
+	for record, err := range mr.AllContext(context.Background()) {
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readInput yields the records of one input and reports whether iteration
+// continues with the next input
+func (mr *MultiReader[T]) readInput(ctx context.Context, name string, r io.Reader, yield func(T, error) bool) bool {
+	var zero T
+	rb, err := NewReader[T](r, mr.opts...)
+	if err == nil {
+		err = mr.checkHeaders(rb.headers)
+	}
+	if err != nil {
+		yield(zero, fmt.Errorf("%s: %w", name, err))
+		return false
+	}
+	// Keep the errors skipped since the last record, before yielding more
+	skipped := 0
+	collect := func() {
+		for _, err := range rb.Errors()[skipped:] {
+			mr.errs = append(mr.errs, fmt.Errorf("%s: %w", name, err))
+		}
+		skipped = len(rb.Errors())
+	}
+	for record, err := range rb.AllContext(ctx) {
+		collect()
+		if err != nil {
+			yield(zero, fmt.Errorf("%s: %w", name, err))
+			return false
+		}
+		if !yield(record, nil) {
+			return false
+		}
+	}
+	collect()
+	return true
+}
+
+// checkHeaders records the header of the first input and compares the
+// headers of later inputs against it
+func (mr *MultiReader[T]) checkHeaders(headers []string) error {
+	if mr.headers == nil {
+		mr.headers = slices.Clone(headers)
+		return nil
+	}
+	var herr HeaderError
+	for _, h := range mr.headers {
+		if !slices.Contains(headers, h) {
+			herr.Missing = append(herr.Missing, h)
+		}
+	}
+	for _, h := range headers {
+		if !slices.Contains(mr.headers, h) {
+			herr.Extra = append(herr.Extra, h)
+		}
+	}
+	if len(herr.Missing) > 0 || len(herr.Extra) > 0 {
+		return &herr
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestMultiReader(t *testing.T) {
+	inputs := []io.Reader{
+		strings.NewReader("Name,Email,Age\nAlice,alice@example.com,30\n"),
+		strings.NewReader("Age,Name,Email\n25,Bob,bob@example.com\n40,Carol,carol@example.com\n"),
+		strings.NewReader("Name,Email,Age\n"),
+	}
+	mr, err := rowboat.NewMultiReader[Person](inputs)
+	if err != nil {
+		t.Fatalf("Failed to create MultiReader: %v", err)
+	}
+	got, err := mr.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+		{Name: "Carol", Email: "carol@example.com", Age: 40},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if headers := mr.Headers(); !reflect.DeepEqual(headers, []string{"Name", "Email", "Age"}) {
+		t.Errorf("Expected: %+v\nGot: %+v", []string{"Name", "Email", "Age"}, headers)
+	}
+}
+
+func TestMultiReaderErrors(t *testing.T) {
+	inputs := []io.Reader{
+		strings.NewReader("Name,Email,Age\nAlice,alice@example.com,30\n"),
+		strings.NewReader("Name,Email,Age,Phone\nBob,bob@example.com,25,555\n"),
+	}
+	mr, err := rowboat.NewMultiReader[Person](inputs)
+	if err != nil {
+		t.Fatalf("Failed to create MultiReader: %v", err)
+	}
+	got, err := mr.ReadAll()
+	var headerErr *rowboat.HeaderError
+	if !errors.As(err, &headerErr) || !reflect.DeepEqual(headerErr.Extra, []string{"Phone"}) {
+		t.Fatalf("Expected a HeaderError for the Phone column, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "input 2: ") {
+		t.Errorf("Expected the error to name input 2, got %q", err.Error())
+	}
+	if len(got) != 1 {
+		t.Errorf("Expected: %+v\nGot: %+v", 1, len(got))
+	}
+
+	inputs = []io.Reader{
+		strings.NewReader("Name,Email,Age\nAlice,alice@example.com,30\n"),
+		strings.NewReader("Name,Email,Age\nBob,bob@example.com,old\n"),
+	}
+	mr, err = rowboat.NewMultiReader[Person](inputs)
+	if err != nil {
+		t.Fatalf("Failed to create MultiReader: %v", err)
+	}
+	_, err = mr.ReadAll()
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 2 || !strings.HasPrefix(err.Error(), "input 2: ") {
+		t.Errorf("Expected a RowError on line 2 of input 2, got %v", err)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte("Name,Email,Age\nBob,bob@example.com,25\n"))
+	zw.Close()
+	fsys := fstest.MapFS{
+		"exports/2024-01-01.csv":    {Data: []byte("Name,Email,Age\nAlice,alice@example.com,30\n")},
+		"exports/2024-01-02.csv.gz": {Data: gz.Bytes()},
+		"exports/2024-01-03.csv":    {Data: []byte("Name,Email,Age\nCarol,carol@example.com,forty\n")},
+		"exports/notes.txt":         {Data: []byte("not a CSV file")},
+	}
+
+	var got []Person
+	var readErr error
+	for p, err := range rowboat.ReadDir[Person](fsys, "exports/*.csv*") {
+		if err != nil {
+			readErr = err
+			break
+		}
+		got = append(got, p)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(readErr, &rowErr) || !strings.HasPrefix(readErr.Error(), "exports/2024-01-03.csv: ") {
+		t.Errorf("Expected a RowError naming exports/2024-01-03.csv, got %v", readErr)
+	}
+}
+
+func TestDirReaderSkippedErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.csv": {Data: []byte("Name,Email,Age\nAlice,alice@example.com,thirty\nBob,bob@example.com,25\n")},
+		"b.csv": {Data: []byte("Name,Email,Age\nCarol,carol@example.com,forty\n")},
+	}
+	mr, err := rowboat.NewDirReader[Person](fsys, "*.csv", rowboat.WithMaxErrors(1))
+	if err != nil {
+		t.Fatalf("Failed to create MultiReader: %v", err)
+	}
+	got, err := mr.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if expected := []Person{{Name: "Bob", Email: "bob@example.com", Age: 25}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	var names []string
+	for _, err := range mr.Errors() {
+		var rowErr *rowboat.RowError
+		if !errors.As(err, &rowErr) {
+			t.Errorf("Expected a RowError, got %v", err)
+		}
+		name, _, _ := strings.Cut(err.Error(), ":")
+		names = append(names, name)
+	}
+	if expected := []string{"a.csv", "b.csv"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, names)
+	}
+}
@@ -0,0 +1,74 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type NullableRow struct {
+	ID    int      `csv:"id"`
+	Name  *string  `csv:"name"`
+	Score *float64 `csv:"score,prec=1"`
+	Count int      `csv:"count"`
+}
+
+func ptr[V any](v V) *V {
+	return &v
+}
+
+func TestNullString(t *testing.T) {
+	rows := []NullableRow{
+		{ID: 1, Name: ptr("Alice"), Score: ptr(9.5), Count: 3},
+		{ID: 2, Name: ptr(""), Score: nil, Count: 0},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[NullableRow](&buf, rowboat.WithNullString(`\N`))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(rows); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	expected := "id,name,score,count\n1,Alice,9.5,3\n2,,\\N,0\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+	}
+
+	// The sentinel also decodes as the zero value of other fields
+	input := buf.String() + "3,\\N,1.0,\\N\n"
+	rb, err := rowboat.NewReader[NullableRow](strings.NewReader(input), rowboat.WithNullString(`\N`))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	want := append(rows, NullableRow{ID: 3, Score: ptr(1.0)})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, got)
+	}
+}
+
+func TestPointerFields(t *testing.T) {
+	rb, err := rowboat.NewReader[NullableRow](strings.NewReader("id,name,score,count\n1,,2.5,4\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []NullableRow{{ID: 1, Score: ptr(2.5), Count: 4}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
@@ -0,0 +1,375 @@
+package rowboat
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Option configures a Reader or Writer. Options that only affect one of
+// them are ignored by the other.
+type Option func(*options)
+
+// options holds the settings shared by readers and writers
+type options struct {
+	schemaHash    bool
+	typeRow       bool
+	maxRows       int
+	maxBytes      int64
+	maxErrors     int
+	converters    map[string]func(string) (any, error)
+	nonFinite     NonFinitePolicy
+	delimiter     rune
+	lazyQuotes    bool
+	useCRLF       bool
+	comment       rune
+	trimLeading   bool
+	valueMaps     map[string]map[string]string
+	rowFilter     func(raw []string) bool
+	decompress    bool
+	gzip          bool
+	charset       Charset
+	decoder       func(io.Reader) io.Reader
+	timeLayout    string
+	footer        func(stats Stats) []string
+	columns       []string
+	omitEmpty     bool
+	locale        string
+	stripCurrency bool
+	duplicates    DuplicateHeaderPolicy
+	validators    []any // func(T) error for the record type T
+	progress      func(ProgressInfo)
+
+	sectionSeparator func(line string) bool
+	skipRows         int
+	skipUntil        func(row []string) bool
+	trailer          func(row []string) bool
+	checksum         Checksum
+	quoting          QuotePolicy
+	nullString       string
+	hasNull          bool // nullString is set
+	trimSpace        bool
+	positional       bool
+	sniff            bool
+	stats            bool
+	headerMapping    map[string]string
+	maxRowBytes      int
+	maxCellBytes     int
+	maxColumns       int
+	formulaPrefix    string
+	maskers          map[string]func(string) string
+	cipher           Encrypter
+	concurrent       bool
+	flushRows        int
+	flushBytes       int
+	shardRows        int
+	shardBytes       int64
+	chunkSize        int64
+	readAhead        int
+	bufferSize       int
+	fieldsPerRecord  int
+	padShort         bool
+	truncateLong     bool
+}
+
+var (
+	defaultsMu sync.RWMutex
+	defaults   []Option
+)
+
+// SetDefaults replaces the package-level default options applied to every
+// Reader and Writer before their own options, so that an application can
+// establish common settings once. It is safe for concurrent use.
+func SetDefaults(opts ...Option) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaults = slices.Clone(opts)
+}
+
+// newOptions applies opts over the default settings
+func newOptions(opts []Option) *options {
+	o := &options{}
+	defaultsMu.RLock()
+	for _, opt := range defaults {
+		opt(o)
+	}
+	defaultsMu.RUnlock()
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// CSVOptioner is implemented by record types declaring their own options,
+// such as a delimiter, time layout or null string. Readers and Writers of
+// the type apply them after the package defaults and before the options
+// passed at the call site.
+type CSVOptioner interface {
+	CSVOptions() []Option
+}
+
+// newTypeOptions applies opts over the default settings and the options
+// declared by T
+func newTypeOptions[T any](opts []Option) *options {
+	t := reflect.New(recordType[T]())
+	if p, ok := t.Elem().Interface().(CSVOptioner); ok {
+		opts = slices.Concat(p.CSVOptions(), opts)
+	} else if p, ok := t.Interface().(CSVOptioner); ok {
+		opts = slices.Concat(p.CSVOptions(), opts)
+	}
+	return newOptions(opts)
+}
+
+// wrapInput applies decompression, character decoding and size limits to
+// the raw input of a reader
+func (o *options) wrapInput(r io.Reader) (io.Reader, error) {
+	r, _, err := o.openInput(r)
+	return r, err
+}
+
+// openInput wraps r like wrapInput and also returns the number of bytes of
+// the byte order mark it skipped
+func (o *options) openInput(r io.Reader) (io.Reader, int64, error) {
+	if o.decompress {
+		var err error
+		if r, err = decompress(r); err != nil {
+			return nil, 0, err
+		}
+	}
+	r = decodeCharset(r, o.charset)
+	if o.decoder != nil {
+		r = o.decoder(r)
+	}
+	if o.maxBytes > 0 {
+		r = &limitedReader{r: r, n: o.maxBytes}
+	}
+	if o.bufferSize > 0 {
+		r = bufio.NewReaderSize(r, max(o.bufferSize, minBufferSize))
+	}
+	r, bom, err := skipBOM(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if o.maxRowBytes > 0 {
+		r = o.newRowLimiter(r)
+	}
+	return r, bom, nil
+}
+
+// minBufferSize is the buffer size of encoding/csv. The csv package reuses
+// bufio Readers and Writers at least this large instead of wrapping them.
+const minBufferSize = 4096
+
+// WithBufferSize sets the size of the buffers between the Reader or Writer
+// and the underlying input or output. Larger buffers suit very long lines,
+// such as base64 blobs in cells, and high-latency storage. Sizes below the
+// 4096 byte default are raised to it.
+func WithBufferSize(n int) Option {
+	return func(o *options) {
+		o.bufferSize = n
+	}
+}
+
+// wrapOutput buffers w as set by WithBufferSize
+func (o *options) wrapOutput(w io.Writer) io.Writer {
+	if o.bufferSize > 0 {
+		return bufio.NewWriterSize(w, max(o.bufferSize, minBufferSize))
+	}
+	return w
+}
+
+// configureReader applies the format options to a csv.Reader
+func (o *options) configureReader(r *csv.Reader) {
+	if o.delimiter != 0 {
+		r.Comma = o.delimiter
+	}
+	r.LazyQuotes = o.lazyQuotes
+	r.Comment = o.comment
+	r.TrimLeadingSpace = o.trimLeading
+	r.FieldsPerRecord = o.fieldsPerRecord
+}
+
+// configureWriter applies the format options to a csv.Writer
+func (o *options) configureWriter(w *csv.Writer) {
+	if o.delimiter != 0 {
+		w.Comma = o.delimiter
+	}
+	w.UseCRLF = o.useCRLF
+}
+
+// WithDelimiter sets the field delimiter. The default is a comma.
+func WithDelimiter(delimiter rune) Option {
+	return func(o *options) {
+		o.delimiter = delimiter
+	}
+}
+
+// WithLazyQuotes lets the Reader accept quotes appearing in unquoted fields
+// and non-doubled quotes in quoted fields
+func WithLazyQuotes() Option {
+	return func(o *options) {
+		o.lazyQuotes = true
+	}
+}
+
+// WithFieldsPerRecord sets the number of fields rows must have, like
+// csv.Reader.FieldsPerRecord. Zero, the default, requires the number of
+// header fields, and a negative n allows any number, leaving the fields of
+// missing columns at their zero values. Other rows fail with a
+// FieldCountError.
+func WithFieldsPerRecord(n int) Option {
+	return func(o *options) {
+		o.fieldsPerRecord = n
+	}
+}
+
+// WithPadShortRows makes the Reader accept rows with fewer fields than
+// required, as produced by spreadsheet tools that drop trailing empty
+// columns. Short rows are padded with empty cells, which WithStats and
+// AllWithRaw see, and the fields of missing columns keep their zero values.
+func WithPadShortRows() Option {
+	return func(o *options) {
+		o.padShort = true
+	}
+}
+
+// WithTruncateLongRows makes the Reader drop the cells of rows beyond the
+// number of fields required instead of failing
+func WithTruncateLongRows() Option {
+	return func(o *options) {
+		o.truncateLong = true
+	}
+}
+
+// WithSchemaHash makes the Writer emit a fingerprint of the struct schema
+// as a comment line before the header, and makes the Reader verify that
+// fingerprint against its own struct before reading any rows.
+func WithSchemaHash() Option {
+	return func(o *options) {
+		o.schemaHash = true
+	}
+}
+
+// WithConverter attaches a conversion function to the field identified by
+// its Go field name or CSV column name, like Reader.RegisterConverter.
+// Types without a matching field ignore it, and registering both names of a
+// field is an error.
+func WithConverter(fieldName string, fn func(string) (any, error)) Option {
+	return func(o *options) {
+		if o.converters == nil {
+			o.converters = make(map[string]func(string) (any, error))
+		}
+		o.converters[fieldName] = fn
+	}
+}
+
+// NonFinitePolicy controls how NaN, +Inf and -Inf float values are handled
+type NonFinitePolicy int
+
+const (
+	// NonFiniteString writes non-finite values as NaN, +Inf and -Inf and
+	// accepts them on read. This is the default.
+	NonFiniteString NonFinitePolicy = iota
+	// NonFiniteEmpty writes non-finite values as empty cells and reads
+	// empty float cells as NaN
+	NonFiniteEmpty
+	// NonFiniteError rejects non-finite values on both write and read
+	NonFiniteError
+)
+
+// WithNonFinite sets the policy for NaN and infinite float values
+func WithNonFinite(policy NonFinitePolicy) Option {
+	return func(o *options) {
+		o.nonFinite = policy
+	}
+}
+
+// WithValueMap replaces cell values of the named column before they are
+// decoded. The column is identified by its CSV column name or Go field name,
+// but not both. Values missing from the map are decoded unchanged.
+func WithValueMap(column string, values map[string]string) Option {
+	return func(o *options) {
+		if o.valueMaps == nil {
+			o.valueMaps = make(map[string]map[string]string)
+		}
+		o.valueMaps[column] = values
+	}
+}
+
+// WithRowFilter skips rows for which keep returns false. The filter sees the
+// raw record before any conversion, so rejected rows cost no decoding work.
+// The record slice is reused for the next row; copy it to retain it.
+func WithRowFilter(keep func(raw []string) bool) Option {
+	return func(o *options) {
+		o.rowFilter = keep
+	}
+}
+
+// WithTimeLayout sets the layout used to read and write time.Time fields.
+// The default is time.RFC3339. Fields with a locale tag use the locale's
+// date layout instead.
+func WithTimeLayout(layout string) Option {
+	return func(o *options) {
+		o.timeLayout = layout
+	}
+}
+
+// WithColumns makes the Reader decode only the named columns, identified by
+// CSV column name or Go field name. The fields of other columns keep their
+// zero values and their cells are not converted.
+func WithColumns(columns ...string) Option {
+	return func(o *options) {
+		o.columns = columns
+	}
+}
+
+// WithNullString sets the sentinel, such as NULL or \N, that represents
+// missing values. The Reader decodes it as a nil pointer, or as the zero
+// value of fields that are not pointers, and the Writer writes nil pointers
+// as the sentinel. Without it, empty cells decode as nil pointers and nil
+// pointers are written as empty cells.
+func WithNullString(null string) Option {
+	return func(o *options) {
+		o.nullString = null
+		o.hasNull = true
+	}
+}
+
+// isNull reports whether a cell holds a missing value for field type t
+func (o *options) isNull(value string, t reflect.Type) bool {
+	if o.hasNull {
+		return value == o.nullString
+	}
+	return value == "" && t.Kind() == reflect.Pointer
+}
+
+// WithTrimSpace makes the Reader trim leading and trailing whitespace from
+// every cell before decoding it, like the trim tag option. Raw fields are
+// not trimmed.
+func WithTrimSpace() Option {
+	return func(o *options) {
+		o.trimSpace = true
+	}
+}
+
+// WithPositional makes the Reader map each field to the column at its
+// index, the index tag option or its position in CSV order, instead of
+// matching header names. The header row is still read and available from
+// Headers.
+func WithPositional() Option {
+	return func(o *options) {
+		o.positional = true
+	}
+}
+
+// WithOmitEmpty makes the Writer write zero values of every field as empty
+// cells, like the omitempty tag option, and makes the Reader decode empty
+// cells as zero values.
+func WithOmitEmpty() Option {
+	return func(o *options) {
+		o.omitEmpty = true
+	}
+}
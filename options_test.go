@@ -0,0 +1,297 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+type Measurement struct {
+	Sensor string  `csv:"sensor"`
+	Value  float64 `csv:"value"`
+}
+
+func TestNonFinitePolicy(t *testing.T) {
+	records := []Measurement{
+		{Sensor: "a", Value: 1.5},
+		{Sensor: "b", Value: math.NaN()},
+		{Sensor: "c", Value: math.Inf(1)},
+		{Sensor: "d", Value: math.Inf(-1)},
+	}
+
+	tests := []struct {
+		name     string
+		policy   rowboat.NonFinitePolicy
+		expected string
+		wantErr  bool
+	}{
+		{"string", rowboat.NonFiniteString, "sensor,value\na,1.5\nb,NaN\nc,+Inf\nd,-Inf\n", false},
+		{"empty", rowboat.NonFiniteEmpty, "sensor,value\na,1.5\nb,\nc,\nd,\n", false},
+		{"error", rowboat.NonFiniteError, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := rowboat.NewWriter[Measurement](&buf, rowboat.WithNonFinite(tt.policy))
+			if err != nil {
+				t.Fatalf("Failed to create Writer: %v", err)
+			}
+			if err := writer.WriteHeader(); err != nil {
+				t.Fatalf("Failed to write header: %v", err)
+			}
+			var writeErr error
+			for _, record := range records {
+				if writeErr = writer.Write(record); writeErr != nil {
+					break
+				}
+			}
+			if tt.wantErr {
+				if writeErr == nil {
+					t.Errorf("Expected error writing non-finite value")
+				}
+				return
+			}
+			if writeErr != nil {
+				t.Fatalf("Failed to write record: %v", writeErr)
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatalf("Failed to flush Writer: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", tt.expected, buf.String())
+			}
+
+			rb, err := rowboat.NewReader[Measurement](strings.NewReader(buf.String()), rowboat.WithNonFinite(tt.policy))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			results, err := collect(rb)
+			if err != nil {
+				t.Fatalf("Failed to read records: %v", err)
+			}
+			if len(results) != 4 || results[0].Value != 1.5 || !math.IsNaN(results[1].Value) {
+				t.Errorf("Unexpected results: %+v", results)
+			}
+		})
+	}
+}
+
+func TestNonFiniteErrorOnRead(t *testing.T) {
+	csvData := "sensor,value\na,NaN\n"
+	rb, err := rowboat.NewReader[Measurement](strings.NewReader(csvData), rowboat.WithNonFinite(rowboat.NonFiniteError))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := collect(rb); err == nil {
+		t.Errorf("Expected error reading NaN")
+	}
+}
+
+func TestValueMap(t *testing.T) {
+	type Customer struct {
+		Name    string `csv:"name"`
+		Country string `csv:"country"`
+		Active  bool   `csv:"active"`
+	}
+
+	csvData := `name,country,active
+Alice,USA,yes
+Bob,U.S.,no
+Carol,DE,true`
+
+	rb, err := rowboat.NewReader[Customer](strings.NewReader(csvData),
+		rowboat.WithValueMap("country", map[string]string{"USA": "US", "U.S.": "US"}),
+		rowboat.WithValueMap("Active", map[string]string{"yes": "true", "no": "false"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+
+	expected := []Customer{
+		{Name: "Alice", Country: "US", Active: true},
+		{Name: "Bob", Country: "US", Active: false},
+		{Name: "Carol", Country: "DE", Active: true},
+	}
+	results, err := collect(rb)
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	rowboat.SetDefaults(rowboat.WithDelimiter(';'), rowboat.WithTimeLayout("2006-01-02"))
+	defer rowboat.SetDefaults()
+
+	type Event struct {
+		Name string    `csv:"name"`
+		Date time.Time `csv:"date"`
+	}
+
+	rb, err := rowboat.NewReader[Event](strings.NewReader("name;date\nlaunch;2024-05-01\n"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	expected := []Event{{Name: "launch", Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}}
+	results, err := collect(rb)
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	// Per-instance options override the defaults
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Event](&buf, rowboat.WithDelimiter(','))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(expected[0]); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush Writer: %v", err)
+	}
+	if want := "name,date\nlaunch,2024-05-01\n"; buf.String() != want {
+		t.Errorf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", want, buf.String())
+	}
+
+	// Defaults can be replaced while readers are being created
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			rowboat.SetDefaults(rowboat.WithDelimiter(';'), rowboat.WithTimeLayout("2006-01-02"))
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := rowboat.NewReader[Event](strings.NewReader("name;date\n")); err != nil {
+				t.Errorf("Failed to create Reader: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithColumns(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,not an email,not a number`
+
+	// The Age column is never converted, so its invalid value is ignored
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithColumns("Name", "Email"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com"},
+		{Name: "Bob", Email: "not an email"},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	if _, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithColumns("Phone")); err == nil {
+		t.Errorf("Expected error for unknown column")
+	}
+}
+
+// EuropeanInvoice declares its own format next to the type
+type EuropeanInvoice struct {
+	Number string    `csv:"number"`
+	Date   time.Time `csv:"date"`
+	Total  *float64  `csv:"total"`
+}
+
+func (EuropeanInvoice) CSVOptions() []rowboat.Option {
+	return []rowboat.Option{
+		rowboat.WithDelimiter(';'),
+		rowboat.WithTimeLayout("02.01.2006"),
+		rowboat.WithNullString("NULL"),
+	}
+}
+
+func TestCSVOptions(t *testing.T) {
+	csvData := "number;date;total\nR-1;24.12.2023;12.5\nR-2;01.02.2024;NULL\n"
+	rb, err := rowboat.NewReader[EuropeanInvoice](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	total := 12.5
+	expected := []EuropeanInvoice{
+		{Number: "R-1", Date: time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC), Total: &total},
+		{Number: "R-2", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	// Call site options apply after the type's own
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[EuropeanInvoice](&buf, rowboat.WithDelimiter('|'))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(got); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "R-1|24.12.2023|12.5\nR-2|01.02.2024|NULL\n"; buf.String() != want {
+		t.Errorf("Expected: %+v\nGot: %+v", want, buf.String())
+	}
+}
+
+func TestBufferSize(t *testing.T) {
+	blob := strings.Repeat("QUJD", 50000)
+	people := []Person{
+		{Name: "Alice", Email: blob, Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var out writeCounter
+	writer, err := rowboat.NewWriter[Person](&out, rowboat.WithBufferSize(1<<20))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(people); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if out.writes != 1 {
+		t.Errorf("Expected: %+v\nGot: %+v", 1, out.writes)
+	}
+
+	for _, size := range []int{1, 1 << 20} {
+		rb, err := rowboat.NewReader[Person](strings.NewReader("Name,Email,Age\n"+out.String()), rowboat.WithBufferSize(size))
+		if err != nil {
+			t.Fatalf("Failed to create Reader: %v", err)
+		}
+		got, err := rb.ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read records: %v", err)
+		}
+		if !reflect.DeepEqual(got, people) {
+			t.Errorf("Records differ after reading with a %d byte buffer", size)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol type identifiers
+const (
+	typeBoolTrue  = 1
+	typeBoolFalse = 2
+	typeI32       = 5
+	typeI64       = 6
+	typeBinary    = 8
+	typeList      = 9
+	typeStruct    = 12
+)
+
+// compactWriter encodes the Thrift compact protocol structures used by the
+// Parquet file and page metadata
+type compactWriter struct {
+	buf    []byte
+	lastID int16
+	stack  []int16
+}
+
+// field writes a field header, using the short delta form when possible
+func (c *compactWriter) field(id int16, typ byte) {
+	if delta := id - c.lastID; delta > 0 && delta <= 15 {
+		c.buf = append(c.buf, byte(delta)<<4|typ)
+	} else {
+		c.buf = append(c.buf, typ)
+		c.varint(uint64(zigzag(int64(id))))
+	}
+	c.lastID = id
+}
+
+// varint appends an unsigned LEB128 integer
+func (c *compactWriter) varint(v uint64) {
+	c.buf = binary.AppendUvarint(c.buf, v)
+}
+
+// zigzag maps signed integers to unsigned ones so small magnitudes stay short
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// beginStruct starts a struct, either a top-level message or a value whose
+// field header or list header was already written
+func (c *compactWriter) beginStruct() {
+	c.stack = append(c.stack, c.lastID)
+	c.lastID = 0
+}
+
+// endStruct writes the stop byte and restores the enclosing field ID
+func (c *compactWriter) endStruct() {
+	c.buf = append(c.buf, 0)
+	c.lastID = c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+}
+
+// structField starts a struct-valued field
+func (c *compactWriter) structField(id int16) {
+	c.field(id, typeStruct)
+	c.beginStruct()
+}
+
+// i32 writes a 32-bit integer field
+func (c *compactWriter) i32(id int16, v int32) {
+	c.field(id, typeI32)
+	c.varint(zigzag(int64(v)))
+}
+
+// i64 writes a 64-bit integer field
+func (c *compactWriter) i64(id int16, v int64) {
+	c.field(id, typeI64)
+	c.varint(zigzag(v))
+}
+
+// bool writes a boolean field, whose value is carried by the field type
+func (c *compactWriter) bool(id int16, v bool) {
+	if v {
+		c.field(id, typeBoolTrue)
+	} else {
+		c.field(id, typeBoolFalse)
+	}
+}
+
+// string writes a binary field
+func (c *compactWriter) string(id int16, s string) {
+	c.field(id, typeBinary)
+	c.rawString(s)
+}
+
+// rawString writes a length-prefixed string without a field header
+func (c *compactWriter) rawString(s string) {
+	c.varint(uint64(len(s)))
+	c.buf = append(c.buf, s...)
+}
+
+// rawI32 writes a list element of type i32
+func (c *compactWriter) rawI32(v int32) {
+	c.varint(zigzag(int64(v)))
+}
+
+// list writes the header of a list field holding n elements of elemType
+func (c *compactWriter) list(id int16, elemType byte, n int) {
+	c.field(id, typeList)
+	if n < 15 {
+		c.buf = append(c.buf, byte(n)<<4|elemType)
+	} else {
+		c.buf = append(c.buf, 0xf0|elemType)
+		c.varint(uint64(n))
+	}
+}
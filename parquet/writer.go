@@ -0,0 +1,412 @@
+// Package parquet writes rowboat structs as Parquet files.
+//
+// Columns are named after the csv tags of the struct fields and typed from
+// the field types, so the same struct definitions serve CSV and Parquet
+// outputs. The package only depends on the standard library: files are
+// written uncompressed with PLAIN encoding.
+package parquet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+// RowGroupSize is the number of rows buffered in memory before they are
+// written out as a row group
+const RowGroupSize = 64 * 1024
+
+// magic starts and ends every Parquet file
+const magic = "PAR1"
+
+// Writer writes structs as rows of a Parquet file. Call WriteHeader before
+// writing rows, as with rowboat.Writer, and Close to complete the file; it
+// does not close the underlying io.Writer.
+type Writer[T any] struct {
+	*rowboat.Writer[T]
+	file *fileWriter
+}
+
+// NewWriter creates a Writer producing a Parquet file. String fields become
+// UTF8 byte arrays, integers INT64, floats DOUBLE, booleans BOOLEAN and
+// time.Time fields microsecond timestamps. Fields with custom marshalers or
+// converters are written as strings. Empty non-string cells, such as NaN
+// values under rowboat.NonFiniteEmpty, are written as nulls. Numbers
+// formatted for a locale are stored as plain numbers.
+func NewWriter[T any](w io.Writer, opts ...rowboat.Option) (*Writer[T], error) {
+	fw := &fileWriter{w: w}
+	rw, err := rowboat.NewRecordWriter[T](fw, slices.Concat(opts, []rowboat.Option{rowboat.WithTypeRow()})...)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer[T]{Writer: rw, file: fw}, nil
+}
+
+// Close flushes the buffered rows and writes the file metadata
+func (w *Writer[T]) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		return err
+	}
+	return w.file.close()
+}
+
+// Parquet physical types
+const (
+	physicalBoolean   = 0
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+)
+
+// Parquet converted types annotating the physical types
+const (
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+	convertedUint64          = 14
+)
+
+// Parquet metadata values
+const (
+	encodingPlain      = 0
+	encodingRLE        = 3
+	repetitionOptional = 1
+	pageTypeData       = 0
+	codecUncompressed  = 0
+	formatVersion      = 1
+	createdBy          = "rowboat"
+)
+
+// column buffers the values of a column for the current row group
+type column struct {
+	rowboat.Column
+	values  []byte  // PLAIN encoded non-null values
+	bools   []bool  // values of boolean columns, bit-packed when flushed
+	defined []bool  // definition level of each row
+	chunks  []chunk // column chunks written so far, one per row group
+}
+
+// chunk records where a column chunk was written
+type chunk struct {
+	offset int64
+	size   int64
+	values int
+}
+
+// physicalType returns the Parquet physical type storing the column
+func (c *column) physicalType() int32 {
+	switch c.Type {
+	case rowboat.Int, rowboat.Uint, rowboat.Time:
+		return physicalInt64
+	case rowboat.Float:
+		return physicalDouble
+	case rowboat.Bool:
+		return physicalBoolean
+	default:
+		return physicalByteArray
+	}
+}
+
+// convertedType returns the annotation of the column's physical type, or -1
+func (c *column) convertedType() int32 {
+	switch c.Type {
+	case rowboat.String:
+		return convertedUTF8
+	case rowboat.Uint:
+		return convertedUint64
+	case rowboat.Time:
+		return convertedTimestampMicros
+	default:
+		return -1
+	}
+}
+
+// append encodes a cell and adds it to the column
+func (c *column) append(value string) error {
+	if value == "" && c.Type != rowboat.String {
+		c.defined = append(c.defined, false)
+		return nil
+	}
+	switch c.Type {
+	case rowboat.Int:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.values = binary.LittleEndian.AppendUint64(c.values, uint64(v))
+	case rowboat.Uint:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.values = binary.LittleEndian.AppendUint64(c.values, v)
+	case rowboat.Float:
+		// Under a locale with a decimal comma, the Writer formats floats
+		// like 1234,5; it never adds group separators
+		v, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil {
+			return err
+		}
+		c.values = binary.LittleEndian.AppendUint64(c.values, math.Float64bits(v))
+	case rowboat.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		c.bools = append(c.bools, v)
+	case rowboat.Time:
+		t, err := time.Parse(c.Layout, value)
+		if err != nil {
+			return err
+		}
+		c.values = binary.LittleEndian.AppendUint64(c.values, uint64(t.UnixMicro()))
+	default:
+		c.values = binary.LittleEndian.AppendUint32(c.values, uint32(len(value)))
+		c.values = append(c.values, value...)
+	}
+	c.defined = append(c.defined, true)
+	return nil
+}
+
+// page returns the data page holding the buffered values: the length
+// prefixed definition levels followed by the values
+func (c *column) page() []byte {
+	levels := bitPack(c.defined)
+	page := binary.LittleEndian.AppendUint32(nil, uint32(len(levels)))
+	page = append(page, levels...)
+	if c.Type == rowboat.Bool {
+		return append(page, packBits(c.bools)...)
+	}
+	return append(page, c.values...)
+}
+
+// reset clears the buffered values after a row group was written
+func (c *column) reset() {
+	c.values = c.values[:0]
+	c.bools = c.bools[:0]
+	c.defined = c.defined[:0]
+}
+
+// bitPack encodes definition levels of bit width 1 as a single bit-packed
+// run of the RLE/bit-packing hybrid encoding
+func bitPack(levels []bool) []byte {
+	groups := (len(levels) + 7) / 8
+	out := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	return append(out, packBits(levels)...)
+}
+
+// packBits packs booleans into bytes, least significant bit first
+func packBits(bits []bool) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+	return out
+}
+
+// fileWriter receives the header, type row and records produced by
+// rowboat.Writer and lays them out as a Parquet file
+type fileWriter struct {
+	w       io.Writer
+	offset  int64
+	names   []string
+	columns []*column
+	rows    int
+	groups  []int
+	err     error
+}
+
+// Write consumes the header row, then the type row, then data rows
+func (fw *fileWriter) Write(record []string) error {
+	if fw.err != nil {
+		return fw.err
+	}
+	switch {
+	case fw.names == nil:
+		fw.names = record
+	case fw.columns == nil:
+		fw.err = fw.start(record)
+	default:
+		fw.err = fw.append(record)
+	}
+	return fw.err
+}
+
+// start creates the columns from the type row and writes the leading magic
+func (fw *fileWriter) start(types []string) error {
+	if len(types) != len(fw.names) {
+		return errors.New("type row does not match header")
+	}
+	fw.columns = make([]*column, len(types))
+	for i, token := range types {
+		col := &column{Column: rowboat.Column{Name: fw.names[i]}}
+		if err := col.Type.UnmarshalText([]byte(token)); err != nil {
+			return fmt.Errorf("column '%s': %w", col.Name, err)
+		}
+		if layout, ok := strings.CutPrefix(token, "time:"); ok {
+			col.Layout = layout
+		} else if col.Type == rowboat.Time {
+			col.Layout = time.RFC3339
+		}
+		fw.columns[i] = col
+	}
+	return fw.write([]byte(magic))
+}
+
+// append adds a data row, writing a row group once enough rows are buffered
+func (fw *fileWriter) append(record []string) error {
+	if len(record) != len(fw.columns) {
+		return fmt.Errorf("record has %d fields, expected %d", len(record), len(fw.columns))
+	}
+	for i, value := range record {
+		if err := fw.columns[i].append(value); err != nil {
+			return fmt.Errorf("column '%s': %w", fw.columns[i].Name, err)
+		}
+	}
+	fw.rows++
+	if fw.rows == RowGroupSize {
+		return fw.flushRowGroup()
+	}
+	return nil
+}
+
+// flushRowGroup writes one data page per column for the buffered rows
+func (fw *fileWriter) flushRowGroup() error {
+	for _, col := range fw.columns {
+		data := col.page()
+
+		var header compactWriter
+		header.beginStruct()
+		header.i32(1, pageTypeData)
+		header.i32(2, int32(len(data)))
+		header.i32(3, int32(len(data)))
+		header.structField(5)
+		header.i32(1, int32(fw.rows))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.endStruct()
+		header.endStruct()
+
+		ch := chunk{offset: fw.offset, size: int64(len(header.buf) + len(data)), values: fw.rows}
+		if err := fw.write(header.buf); err != nil {
+			return err
+		}
+		if err := fw.write(data); err != nil {
+			return err
+		}
+		col.chunks = append(col.chunks, ch)
+		col.reset()
+	}
+	fw.groups = append(fw.groups, fw.rows)
+	fw.rows = 0
+	return nil
+}
+
+// write writes p to the output, tracking the file offset
+func (fw *fileWriter) write(p []byte) error {
+	n, err := fw.w.Write(p)
+	fw.offset += int64(n)
+	return err
+}
+
+// Flush is a no-op: rows are buffered until a row group is complete
+func (fw *fileWriter) Flush() {}
+
+// Error returns the first error encountered while writing rows
+func (fw *fileWriter) Error() error {
+	return fw.err
+}
+
+// close writes the last row group and the file metadata
+func (fw *fileWriter) close() error {
+	if fw.err != nil {
+		return fw.err
+	}
+	if fw.columns == nil {
+		return errors.New("parquet: WriteHeader was not called")
+	}
+	if fw.rows > 0 {
+		if err := fw.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	var meta compactWriter
+	meta.beginStruct()
+	meta.i32(1, formatVersion)
+
+	// The schema is a root group followed by one optional leaf per column
+	meta.list(2, typeStruct, len(fw.columns)+1)
+	meta.beginStruct()
+	meta.string(4, "schema")
+	meta.i32(5, int32(len(fw.columns)))
+	meta.endStruct()
+	for _, col := range fw.columns {
+		meta.beginStruct()
+		meta.i32(1, col.physicalType())
+		meta.i32(3, repetitionOptional)
+		meta.string(4, col.Name)
+		if ct := col.convertedType(); ct >= 0 {
+			meta.i32(6, ct)
+		}
+		meta.endStruct()
+	}
+
+	var total int64
+	for _, rows := range fw.groups {
+		total += int64(rows)
+	}
+	meta.i64(3, total)
+
+	meta.list(4, typeStruct, len(fw.groups))
+	for g, rows := range fw.groups {
+		var size int64
+		for _, col := range fw.columns {
+			size += col.chunks[g].size
+		}
+		meta.beginStruct()
+		meta.list(1, typeStruct, len(fw.columns))
+		for _, col := range fw.columns {
+			ch := col.chunks[g]
+			meta.beginStruct()
+			meta.i64(2, ch.offset)
+			meta.structField(3)
+			meta.i32(1, col.physicalType())
+			meta.list(2, typeI32, 2)
+			meta.rawI32(encodingPlain)
+			meta.rawI32(encodingRLE)
+			meta.list(3, typeBinary, 1)
+			meta.rawString(col.Name)
+			meta.i32(4, codecUncompressed)
+			meta.i64(5, int64(ch.values))
+			meta.i64(6, ch.size)
+			meta.i64(7, ch.size)
+			meta.i64(9, ch.offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.i64(2, size)
+		meta.i64(3, int64(rows))
+		meta.endStruct()
+	}
+	meta.string(6, createdBy)
+	meta.endStruct()
+
+	if err := fw.write(meta.buf); err != nil {
+		return err
+	}
+	if err := fw.write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta.buf)))); err != nil {
+		return err
+	}
+	return fw.write([]byte(magic))
+}
@@ -0,0 +1,233 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+	"github.com/notnil/rowboat/parquet"
+)
+
+type Trade struct {
+	Symbol string    `csv:"symbol"`
+	Qty    int       `csv:"qty"`
+	ID     uint64    `csv:"id"`
+	Price  float64   `csv:"price"`
+	Open   bool      `csv:"open"`
+	At     time.Time `csv:"at"`
+}
+
+func TestWriter(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	trades := []Trade{
+		{Symbol: "ACME", Qty: -10, ID: 1, Price: 12.5, Open: true, At: at},
+		{Symbol: "", Qty: 3, ID: 2, Price: math.NaN(), Open: false, At: at.Add(time.Second)},
+	}
+
+	var buf bytes.Buffer
+	writer, err := parquet.NewWriter[Trade](&buf, rowboat.WithNonFinite(rowboat.NonFiniteEmpty))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(trades)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	data := buf.Bytes()
+	if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+		t.Fatalf("Missing Parquet magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := (&compactReader{buf: data[len(data)-8-footerLen:]}).readStruct()
+
+	if rows := meta[3]; rows != int64(2) {
+		t.Fatalf("Expected 2 rows, got %v", rows)
+	}
+	schema := meta[2].([]any)
+	var names []string
+	for _, el := range schema[1:] {
+		names = append(names, el.(map[int16]any)[4].(string))
+	}
+	if want := []string{"symbol", "qty", "id", "price", "open", "at"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected: %v\nGot: %v", want, names)
+	}
+
+	group := meta[4].([]any)[0].(map[int16]any)
+	var got [][]any
+	for _, c := range group[1].([]any) {
+		md := c.(map[int16]any)[3].(map[int16]any)
+		got = append(got, readColumn(data, md))
+	}
+	expected := [][]any{
+		{"ACME", ""},
+		{int64(-10), int64(3)},
+		{int64(1), int64(2)},
+		{12.5, nil},
+		{true, false},
+		{at.UnixMicro(), at.Add(time.Second).UnixMicro()},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Column values do not match expected.\nExpected: %v\nGot: %v", expected, got)
+	}
+}
+
+func TestWriterWithoutHeader(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := parquet.NewWriter[Trade](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Close(); err == nil {
+		t.Errorf("Expected error when closing without a header")
+	}
+}
+
+func TestWriterLocale(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	var buf bytes.Buffer
+	// Spare capacity must not be written to
+	opts := make([]rowboat.Option, 1, 2)
+	opts[0] = rowboat.WithLocale("de")
+	writer, err := parquet.NewWriter[Trade](&buf, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if opts[:2][1] != nil {
+		t.Errorf("Expected the caller's options to be left alone")
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(Trade{Symbol: "ACME", Qty: 1234, Price: 1234.5, At: at}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	data := buf.Bytes()
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	meta := (&compactReader{buf: data[len(data)-8-footerLen:]}).readStruct()
+	columns := meta[4].([]any)[0].(map[int16]any)[1].([]any)
+	got := readColumn(data, columns[3].(map[int16]any)[3].(map[int16]any))
+	if expected := []any{1234.5}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, got)
+	}
+}
+
+// readColumn decodes the single data page of a column chunk
+func readColumn(data []byte, md map[int16]any) []any {
+	r := &compactReader{buf: data, pos: int(md[9].(int64))}
+	header := r.readStruct()
+	page := data[r.pos : r.pos+int(header[3].(int64))]
+	n := int(header[5].(map[int16]any)[1].(int64))
+
+	levelsLen := int(binary.LittleEndian.Uint32(page))
+	levels := page[4 : 4+levelsLen]
+	values := page[4+levelsLen:]
+	_, k := binary.Uvarint(levels)
+	levels = levels[k:]
+
+	var out []any
+	var bit int
+	for i := 0; i < n; i++ {
+		if levels[i/8]&(1<<(i%8)) == 0 {
+			out = append(out, nil)
+			continue
+		}
+		switch md[1].(int64) {
+		case 0:
+			out = append(out, values[bit/8]&(1<<(bit%8)) != 0)
+			bit++
+		case 2:
+			out = append(out, int64(binary.LittleEndian.Uint64(values)))
+			values = values[8:]
+		case 5:
+			out = append(out, math.Float64frombits(binary.LittleEndian.Uint64(values)))
+			values = values[8:]
+		case 6:
+			l := binary.LittleEndian.Uint32(values)
+			out = append(out, string(values[4:4+l]))
+			values = values[4+l:]
+		}
+	}
+	return out
+}
+
+// compactReader decodes Thrift compact protocol structs into maps keyed by
+// field ID
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) varint() int64 {
+	u := r.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (r *compactReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+	var id int16
+	for {
+		b := r.buf[r.pos]
+		r.pos++
+		if b == 0 {
+			return fields
+		}
+		if delta := int16(b >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(b & 0x0f)
+	}
+}
+
+func (r *compactReader) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 5, 6:
+		return r.varint()
+	case 8:
+		n := int(r.uvarint())
+		s := string(r.buf[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case 9:
+		h := r.buf[r.pos]
+		r.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(h & 0x0f)
+		}
+		return list
+	case 12:
+		return r.readStruct()
+	}
+	panic("unsupported thrift type")
+}
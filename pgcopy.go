@@ -0,0 +1,251 @@
+package rowboat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// copyNull is the cell the COPY text reader and writer use for \N. Text
+// values in Postgres cannot contain NUL bytes, so it never collides with
+// data.
+const copyNull = "\x00"
+
+// NewCopyTextReader creates a Reader for the text format of Postgres COPY,
+// as in the data blocks of pg_dump output. Columns are tab-separated,
+// special characters are backslash-escaped and \N marks NULL, which decodes
+// as a nil pointer or a zero value. The format has no header, so fields map
+// to columns by their index like WithPositional; skip a HEADER line with
+// WithSkipRows(1). A \. line ends the data.
+func NewCopyTextReader[T any](r io.Reader, opts ...Option) (*Reader[T], error) {
+	rb, err := newReader[T](append(slices.Clip(opts), WithNullString(copyNull), WithPositional()))
+	if err != nil {
+		return nil, err
+	}
+	input, bom, err := rb.opts.openInput(rb.trackProgress(r, 0))
+	if err != nil {
+		return nil, err
+	}
+	input, rb.offset, rb.lines, err = rb.opts.skipPreamble(input)
+	if err != nil {
+		return nil, err
+	}
+	rb.offset += bom
+	rb.reader = newCopyTextReader(input, rb.opts.delimiter)
+
+	// Name the columns after the fields at their index
+	for _, fi := range rb.fields {
+		if fi.Index >= len(rb.headers) {
+			rb.headers = append(rb.headers, make([]string, fi.Index+1-len(rb.headers))...)
+		}
+		rb.headers[fi.Index] = fi.Name
+	}
+	if err := rb.createFieldMap(); err != nil {
+		return nil, err
+	}
+	return rb, nil
+}
+
+// NewCopyTextWriter creates a Writer producing the text format of Postgres
+// COPY, ready for COPY ... FROM STDIN. Nil pointers are written as \N.
+// Call WriteHeader only for COPY with the HEADER option.
+func NewCopyTextWriter[T any](w io.Writer, opts ...Option) (*Writer[T], error) {
+	o := newTypeOptions[T](opts)
+	return NewRecordWriter[T](newCopyTextWriter(o.wrapOutput(w), o.delimiter), append(slices.Clip(opts), WithNullString(copyNull))...)
+}
+
+// copyTextReader is a RecordReader decoding the COPY text format
+type copyTextReader struct {
+	br     *bufio.Reader
+	delim  byte
+	line   int   // line of the last record read
+	offset int64 // bytes consumed
+	done   bool  // the end of data marker was read
+	record []string
+}
+
+// newCopyTextReader creates a copyTextReader reading r. The delimiter
+// defaults to a tab.
+func newCopyTextReader(r io.Reader, delimiter rune) *copyTextReader {
+	c := &copyTextReader{br: bufio.NewReader(r), delim: '\t'}
+	if delimiter != 0 {
+		c.delim = byte(delimiter)
+	}
+	return c
+}
+
+func (c *copyTextReader) Read() ([]string, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	line, err := c.br.ReadString('\n')
+	if line == "" && err != nil {
+		return nil, err
+	}
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	c.offset += int64(len(line))
+	c.line++
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	if line == `\.` {
+		c.done = true
+		return nil, io.EOF
+	}
+
+	c.record = append(c.record[:0], strings.Split(line, string(c.delim))...)
+	// A backslash escapes the delimiter, so rejoin fields split after one
+	for i := 0; i < len(c.record)-1; i++ {
+		if trailingBackslashes(c.record[i])%2 == 1 {
+			c.record[i] += string(c.delim) + c.record[i+1]
+			c.record = slices.Delete(c.record, i+1, i+2)
+			i--
+		}
+	}
+	for i, field := range c.record {
+		if field == `\N` {
+			c.record[i] = copyNull
+			continue
+		}
+		if c.record[i], err = unescapeCopy(field); err != nil {
+			return nil, fmt.Errorf("line %d, field %d: %w", c.line, i+1, err)
+		}
+	}
+	return c.record, nil
+}
+
+// FieldPos returns the line of the last record read; each record is one
+// line in the COPY text format
+func (c *copyTextReader) FieldPos(field int) (line, column int) {
+	return c.line, 1
+}
+
+// InputOffset returns the number of bytes consumed
+func (c *copyTextReader) InputOffset() int64 {
+	return c.offset
+}
+
+// trailingBackslashes counts the backslashes ending s
+func trailingBackslashes(s string) int {
+	return len(s) - len(strings.TrimRight(s, `\`))
+}
+
+// unescapeCopy decodes the backslash escapes of a COPY text field
+func unescapeCopy(field string) (string, error) {
+	if !strings.Contains(field, `\`) {
+		return field, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' {
+			b.WriteByte(field[i])
+			continue
+		}
+		i++
+		if i == len(field) {
+			return "", errors.New("trailing backslash")
+		}
+		switch c := field[i]; c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			j := i + 1
+			for j < len(field) && j < i+3 && field[j] >= '0' && field[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(field[i:j], 8, 8)
+			b.WriteByte(byte(n))
+			i = j - 1
+		case 'x':
+			j := i + 1
+			for j < len(field) && j < i+3 && strings.IndexByte("0123456789abcdefABCDEF", field[j]) >= 0 {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte('x') // \x without hex digits is a plain x
+				continue
+			}
+			n, _ := strconv.ParseUint(field[i+1:j], 16, 8)
+			b.WriteByte(byte(n))
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// copyTextWriter is a RecordWriter encoding the COPY text format
+type copyTextWriter struct {
+	w     *bufio.Writer
+	delim byte
+}
+
+// newCopyTextWriter creates a copyTextWriter writing to w. The delimiter
+// defaults to a tab.
+func newCopyTextWriter(w io.Writer, delimiter rune) *copyTextWriter {
+	c := &copyTextWriter{w: bufio.NewWriter(w), delim: '\t'}
+	if delimiter != 0 {
+		c.delim = byte(delimiter)
+	}
+	return c
+}
+
+func (c *copyTextWriter) Write(record []string) error {
+	for i, field := range record {
+		if i > 0 {
+			c.w.WriteByte(c.delim)
+		}
+		if field == copyNull {
+			c.w.WriteString(`\N`)
+			continue
+		}
+		for j := 0; j < len(field); j++ {
+			switch ch := field[j]; ch {
+			case '\\':
+				c.w.WriteString(`\\`)
+			case '\b':
+				c.w.WriteString(`\b`)
+			case '\f':
+				c.w.WriteString(`\f`)
+			case '\n':
+				c.w.WriteString(`\n`)
+			case '\r':
+				c.w.WriteString(`\r`)
+			case '\t':
+				c.w.WriteString(`\t`)
+			case '\v':
+				c.w.WriteString(`\v`)
+			default:
+				if ch == c.delim {
+					c.w.WriteByte('\\')
+				}
+				c.w.WriteByte(ch)
+			}
+		}
+	}
+	return c.w.WriteByte('\n')
+}
+
+func (c *copyTextWriter) Flush() {
+	c.w.Flush()
+}
+
+func (c *copyTextWriter) Error() error {
+	_, err := c.w.Write(nil)
+	return err
+}
@@ -0,0 +1,95 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestCopyText(t *testing.T) {
+	rows := []NullableRow{
+		{ID: 1, Name: ptr("Alice\tSmith"), Score: ptr(9.5), Count: 3},
+		{ID: 2, Name: ptr(`C:\temp` + "\nline"), Score: nil, Count: 0},
+		{ID: 3, Name: nil, Score: ptr(1.0), Count: 7},
+		{ID: 4, Name: ptr(""), Score: nil, Count: 1},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewCopyTextWriter[NullableRow](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(rows); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	expected := "1\tAlice\\tSmith\t9.5\t3\n" +
+		"2\tC:\\\\temp\\nline\t\\N\t0\n" +
+		"3\t\\N\t1.0\t7\n" +
+		"4\t\t\\N\t1\n"
+	if buf.String() != expected {
+		t.Fatalf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+
+	reader, err := rowboat.NewCopyTextReader[NullableRow](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(got, rows) {
+		t.Errorf("Expected: %+v\nGot: %+v", rows, got)
+	}
+}
+
+func TestCopyTextReader(t *testing.T) {
+	// A pg_dump data block with a header line, octal and hex escapes, an
+	// escaped delimiter, NULL in a non-pointer column and the end marker
+	input := "id|name|score|count\n" +
+		"1|caf\\303\\251|\\N|\\N\n" +
+		"2|a\\|b\\x21|2.5|4\r\n" +
+		"\\.\n" +
+		"ignored\n"
+	reader, err := rowboat.NewCopyTextReader[NullableRow](strings.NewReader(input), rowboat.WithDelimiter('|'), rowboat.WithSkipRows(1))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []NullableRow{
+		{ID: 1, Name: ptr("café"), Score: nil, Count: 0},
+		{ID: 2, Name: ptr("a|b!"), Score: ptr(2.5), Count: 4},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if headers := reader.Headers(); !reflect.DeepEqual(headers, []string{"id", "name", "score", "count"}) {
+		t.Errorf("Expected: %+v\nGot: %+v", []string{"id", "name", "score", "count"}, headers)
+	}
+}
+
+func TestCopyTextWriterCSVOptions(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewCopyTextWriter[EuropeanInvoice](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	total := 12.5
+	invoices := []EuropeanInvoice{
+		{Number: "R-1", Date: time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC), Total: &total},
+		{Number: "R-2", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if err := writer.WriteSlice(invoices); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if expected := "R-1;24.12.2023;12.5\nR-2;01.02.2024;\\N\n"; buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,124 @@
+package rowboat
+
+import (
+	"context"
+	"errors"
+)
+
+// PipelineBuilder assembles a read, transform and write pipeline over
+// records of type T. Create one with Pipeline.
+type PipelineBuilder[T any] struct {
+	src      *Reader[T]
+	dst      *Writer[T]
+	steps    []func(T) (T, bool)
+	progress func(ProgressInfo, PipelineMetrics)
+	metrics  PipelineMetrics
+}
+
+// PipelineMetrics counts the records that went through a pipeline
+type PipelineMetrics struct {
+	Read     int // records decoded from the source
+	Filtered int // records dropped by filters
+	Written  int // records written to the destination
+}
+
+// Pipeline starts building a pipeline, e.g.
+//
+//	rowboat.Pipeline[T]().From(reader).Filter(f).Map(g).To(writer).Run(ctx)
+//
+// Filters and maps run in the order they were added. Converting records to
+// another type is done with ReadAs.
+func Pipeline[T any]() *PipelineBuilder[T] {
+	return &PipelineBuilder[T]{}
+}
+
+// From sets the Reader supplying the records. Its options, such as
+// WithMaxErrors, define how malformed rows are handled.
+func (p *PipelineBuilder[T]) From(r *Reader[T]) *PipelineBuilder[T] {
+	p.src = r
+	return p
+}
+
+// Filter drops the records for which keep returns false
+func (p *PipelineBuilder[T]) Filter(keep func(T) bool) *PipelineBuilder[T] {
+	p.steps = append(p.steps, func(record T) (T, bool) {
+		return record, keep(record)
+	})
+	return p
+}
+
+// Map replaces each record with the result of fn
+func (p *PipelineBuilder[T]) Map(fn func(T) T) *PipelineBuilder[T] {
+	p.steps = append(p.steps, func(record T) (T, bool) {
+		return fn(record), true
+	})
+	return p
+}
+
+// To sets the Writer receiving the records. Run writes the header first
+// and flushes the Writer when done; closing it is left to the caller.
+func (p *PipelineBuilder[T]) To(w *Writer[T]) *PipelineBuilder[T] {
+	p.dst = w
+	return p
+}
+
+// Progress calls report after every record the pipeline reads and once
+// more when Run returns, with the progress of the source and the record
+// counts so far. Bytes and the input size are tracked only if the source
+// was created with WithProgress.
+func (p *PipelineBuilder[T]) Progress(report func(ProgressInfo, PipelineMetrics)) *PipelineBuilder[T] {
+	p.progress = report
+	return p
+}
+
+// Run reads all records, applies the filters and maps and writes the
+// results. It stops with the context's error once ctx is done, or with the
+// first read or write error.
+func (p *PipelineBuilder[T]) Run(ctx context.Context) error {
+	if p.src == nil || p.dst == nil {
+		return errors.New("pipeline requires a source and a destination")
+	}
+	p.metrics = PipelineMetrics{}
+	if err := p.dst.WriteHeader(); err != nil {
+		return err
+	}
+	if p.progress != nil {
+		defer p.reportProgress()
+	}
+
+	for record, err := range p.src.AllContext(ctx) {
+		if err != nil {
+			p.dst.Flush()
+			return err
+		}
+		p.metrics.Read++
+		keep := true
+		for _, step := range p.steps {
+			if record, keep = step(record); !keep {
+				break
+			}
+		}
+		if keep {
+			if err := p.dst.Write(record); err != nil {
+				return err
+			}
+			p.metrics.Written++
+		} else {
+			p.metrics.Filtered++
+		}
+		if p.progress != nil {
+			p.reportProgress()
+		}
+	}
+	return p.dst.Flush()
+}
+
+// reportProgress passes the current progress to the Progress callback
+func (p *PipelineBuilder[T]) reportProgress() {
+	p.progress(p.src.progressInfo(), p.metrics)
+}
+
+// Metrics returns the record counts of the last run
+func (p *PipelineBuilder[T]) Metrics() PipelineMetrics {
+	return p.metrics
+}
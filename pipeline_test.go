@@ -0,0 +1,111 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestPipeline(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,17
+Charlie,charlie@example.com,35`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+
+	p := rowboat.Pipeline[Person]().
+		From(rb).
+		Filter(func(p Person) bool { return p.Age >= 18 }).
+		Map(func(p Person) Person {
+			p.Email = strings.ToUpper(p.Email)
+			return p
+		}).
+		To(writer)
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Failed to run pipeline: %v", err)
+	}
+
+	expectedCSV := `Name,Email,Age
+Alice,ALICE@EXAMPLE.COM,30
+Charlie,CHARLIE@EXAMPLE.COM,35
+`
+	if buf.String() != expectedCSV {
+		t.Errorf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+	if want := (rowboat.PipelineMetrics{Read: 3, Filtered: 1, Written: 2}); p.Metrics() != want {
+		t.Errorf("Expected: %+v\nGot: %+v", want, p.Metrics())
+	}
+}
+
+func TestPipelineErrors(t *testing.T) {
+	if err := rowboat.Pipeline[Person]().Run(context.Background()); err == nil {
+		t.Errorf("Expected error for pipeline without source and destination")
+	}
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader("Name,Email,Age\nAlice,a@example.com,old"))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	err = rowboat.Pipeline[Person]().From(rb).To(writer).Run(context.Background())
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) {
+		t.Errorf("Expected RowError, got %v", err)
+	}
+}
+
+func TestPipelineProgress(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,17\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithProgress(func(rowboat.ProgressInfo) {}))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	writer, err := rowboat.NewWriter[Person](&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+
+	var reports []rowboat.PipelineMetrics
+	var last rowboat.ProgressInfo
+	err = rowboat.Pipeline[Person]().
+		From(rb).
+		Filter(func(p Person) bool { return p.Age >= 18 }).
+		To(writer).
+		Progress(func(info rowboat.ProgressInfo, m rowboat.PipelineMetrics) {
+			reports = append(reports, m)
+			last = info
+		}).
+		Run(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to run pipeline: %v", err)
+	}
+	expected := []rowboat.PipelineMetrics{
+		{Read: 1, Written: 1},
+		{Read: 2, Filtered: 1, Written: 1},
+		{Read: 2, Filtered: 1, Written: 1},
+	}
+	if !reflect.DeepEqual(reports, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, reports)
+	}
+	if want := (rowboat.ProgressInfo{Rows: 2, Bytes: int64(len(csvData)), Size: int64(len(csvData))}); last != want {
+		t.Errorf("Expected: %+v\nGot: %+v", want, last)
+	}
+}
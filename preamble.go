@@ -0,0 +1,69 @@
+package rowboat
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// WithSkipRows makes the Reader skip the first n lines of the input, such
+// as report titles and generation timestamps, before reading the header
+func WithSkipRows(n int) Option {
+	return func(o *options) {
+		o.skipRows = n
+	}
+}
+
+// WithSkipUntil makes the Reader skip lines until match reports true for
+// the parsed row, which is then read as the header. It applies after
+// WithSkipRows. Blank and malformed lines are skipped without calling
+// match.
+func WithSkipUntil(match func(row []string) bool) Option {
+	return func(o *options) {
+		o.skipUntil = match
+	}
+}
+
+// skipPreamble consumes the lines preceding the header. It returns the
+// remaining input with the number of bytes and lines skipped.
+func (o *options) skipPreamble(r io.Reader) (io.Reader, int64, int, error) {
+	if o.skipRows <= 0 && o.skipUntil == nil {
+		return r, 0, 0, nil
+	}
+	br := bufio.NewReader(r)
+	var n int64
+	lines := 0
+	for ; lines < o.skipRows; lines++ {
+		line, err := br.ReadString('\n')
+		n += int64(len(line))
+		if err == io.EOF {
+			return br, n, lines, nil
+		}
+		if err != nil {
+			return nil, 0, 0, err
+		}
+	}
+	if o.skipUntil == nil {
+		return br, n, lines, nil
+	}
+
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, 0, 0, err
+		}
+		cr := csv.NewReader(strings.NewReader(line))
+		o.configureReader(cr)
+		if row, perr := cr.Read(); perr == nil {
+			if o.skipUntil(row) {
+				return io.MultiReader(strings.NewReader(line), br), n, lines, nil
+			}
+		}
+		n += int64(len(line))
+		lines++
+		if err == io.EOF {
+			return br, n, lines, nil
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package rowboat_test
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestSkipRows(t *testing.T) {
+	csvData := "Monthly Report\nGenerated 2024-05-06\nName,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,x\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithSkipRows(2))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) {
+		t.Fatalf("Expected RowError, got %v", err)
+	}
+	if rowErr.Line != 5 {
+		t.Errorf("Expected: %+v\nGot: %+v", 5, rowErr.Line)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func TestSkipUntil(t *testing.T) {
+	csvData := "\ufeffSales export\n\n\"Period: May, 2024\"\nName,Email,Age\nAlice,alice@example.com,30\n"
+	isHeader := func(row []string) bool { return slices.Contains(row, "Email") }
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithSkipUntil(isHeader))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if want := 5; rb.Line() != want {
+		t.Errorf("Expected: %+v\nGot: %+v", want, rb.Line())
+	}
+	if want := int64(len(csvData)); rb.Offset() != want {
+		t.Errorf("Expected: %+v\nGot: %+v", want, rb.Offset())
+	}
+
+	if _, err := rowboat.NewReader[Person](strings.NewReader("no header here\n"), rowboat.WithSkipUntil(isHeader)); err == nil {
+		t.Error("Expected an error when no header is found")
+	}
+}
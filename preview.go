@@ -0,0 +1,107 @@
+package rowboat
+
+import (
+	"io"
+	"slices"
+)
+
+// bufferedRecord is a record read ahead of time from a Reader's source
+type bufferedRecord struct {
+	record []string
+	err    error
+	line   int   // line where the record starts
+	offset int64 // byte offset just past the record
+	padded int   // empty cells appended by WithPadShortRows
+}
+
+// Preview returns up to n of the next rows without consuming them, for
+// import wizards showing users what a file holds before committing to it:
+// the raw cells of each row, the record decoded from it and the error
+// decoding or validating it, which is nil for valid rows. A record failing
+// to decode holds the cells decoded before the bad one. Rows rejected by
+// WithRowFilter are left out, as when reading. The rows are read
+// ahead and buffered, so later reads and previews return them again. The
+// returned error reports failures to read the input rather than bad rows.
+func (rb *Reader[T]) Preview(n int) (rows [][]string, records []T, errs []error, err error) {
+	if err := rb.Init(); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := rb.checkFields(); err != nil {
+		return nil, nil, nil, err
+	}
+	if rb.replayed == nil {
+		// Keep the position of the last record read until the next read
+		rb.replayed = &bufferedRecord{line: rb.line, offset: rb.sourceOffset()}
+	}
+
+	// Read ahead until n rows passing the row filter are buffered
+	kept := 0
+	for _, b := range rb.buffered {
+		if rb.previewed(b) {
+			kept++
+		}
+	}
+	for kept < n && rb.trailer == nil && !rb.bufferedTrailer() {
+		record, err := rb.reader.Read()
+		if err == io.EOF {
+			break
+		}
+		buffered := bufferedRecord{}
+		buffered.line = rb.sourceLine(rb.records + len(rb.buffered) + 1)
+		buffered.offset = rb.sourceOffset()
+		record, buffered.err = rb.checkRead(record, err, buffered.line)
+		buffered.padded = rb.padded
+		buffered.record = slices.Clone(record)
+		rb.buffered = append(rb.buffered, buffered)
+		if rb.previewed(buffered) {
+			kept++
+		}
+	}
+
+	line, padded := rb.line, rb.padded
+	defer func() { rb.line, rb.padded = line, padded }()
+	for _, b := range rb.buffered {
+		if len(rows) >= n {
+			break
+		}
+		if b.err != nil && !skippable(b.err) {
+			return rows, records, errs, b.err
+		}
+		if rb.isTrailerRecord(b.record, b.err) {
+			break
+		}
+		if !rb.previewed(b) {
+			continue
+		}
+		var record T
+		err := b.err
+		if err == nil {
+			rb.line, rb.padded = b.line, b.padded
+			if record, err = rb.decodeRecord(b.record); err == nil {
+				if err = validateRecord(rb.validate, record); err != nil {
+					err = &RowError{Line: b.line, Err: err}
+				}
+			}
+		}
+		rows = append(rows, slices.Clone(b.record))
+		records = append(records, record)
+		errs = append(errs, err)
+	}
+	return rows, records, errs, nil
+}
+
+// bufferedTrailer reports whether the last buffered record ends reading,
+// being the trailer or an error other than a malformed row
+func (rb *Reader[T]) bufferedTrailer() bool {
+	if len(rb.buffered) == 0 {
+		return false
+	}
+	b := rb.buffered[len(rb.buffered)-1]
+	return (b.err != nil && !skippable(b.err)) || rb.isTrailerRecord(b.record, b.err)
+}
+
+// previewed reports whether a buffered record is shown by Preview: rows
+// failing to read are, while rows rejected by the row filter are not
+func (rb *Reader[T]) previewed(b bufferedRecord) bool {
+	return b.err != nil || rb.opts.rowFilter == nil || rb.opts.rowFilter(b.record)
+}
@@ -0,0 +1,124 @@
+package rowboat_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestPreview(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,old\nCarol,carol@example.com,41\nDave,dave@example.com,52\n"
+	reader, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+
+	for range 2 {
+		rows, records, errs, err := reader.Preview(3)
+		if err != nil {
+			t.Fatalf("Failed to preview: %v", err)
+		}
+		expectedRows := [][]string{
+			{"Alice", "alice@example.com", "30"},
+			{"Bob", "bob@example.com", "old"},
+			{"Carol", "carol@example.com", "41"},
+		}
+		if !reflect.DeepEqual(rows, expectedRows) {
+			t.Errorf("Expected: %+v\nGot: %+v", expectedRows, rows)
+		}
+		expectedRecords := []Person{
+			{Name: "Alice", Email: "alice@example.com", Age: 30},
+			{Name: "Bob", Email: "bob@example.com"},
+			{Name: "Carol", Email: "carol@example.com", Age: 41},
+		}
+		if !reflect.DeepEqual(records, expectedRecords) {
+			t.Errorf("Expected: %+v\nGot: %+v", expectedRecords, records)
+		}
+		var rowErr *rowboat.RowError
+		if len(errs) != 3 || errs[0] != nil || errs[2] != nil || !errors.As(errs[1], &rowErr) || rowErr.Line != 3 || rowErr.Column != "Age" {
+			t.Errorf("Expected a RowError for column Age on line 3, got %v", errs)
+		}
+	}
+
+	// Previewed rows are read again
+	var names []string
+	var readErr error
+	for p, err := range reader.AllContext(context.Background()) {
+		if err != nil {
+			readErr = err
+			break
+		}
+		names = append(names, p.Name)
+	}
+	var rowErr *rowboat.RowError
+	if !errors.As(readErr, &rowErr) || rowErr.Line != 3 {
+		t.Errorf("Expected a RowError on line 3, got %v", readErr)
+	}
+	if expected := []string{"Alice"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, names)
+	}
+}
+
+func TestPreviewThenReadAll(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,25\nTOTAL,2\n"
+	reader, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithTrailer(func(row []string) bool {
+		return row[0] == "TOTAL"
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	rows, _, _, err := reader.Preview(10)
+	if err != nil {
+		t.Fatalf("Failed to preview: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("Expected the preview to stop at the trailer, got %v", rows)
+	}
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if expected := []string{"TOTAL", "2"}; !reflect.DeepEqual(reader.Trailer(), expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, reader.Trailer())
+	}
+}
+
+func TestPreviewRowFilter(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,25\nCarol,carol@example.com,41\nDave,dave@example.com,52\n"
+	reader, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithRowFilter(func(raw []string) bool {
+		return raw[0] != "Bob"
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	rows, _, _, err := reader.Preview(2)
+	if err != nil {
+		t.Fatalf("Failed to preview: %v", err)
+	}
+	expectedRows := [][]string{
+		{"Alice", "alice@example.com", "30"},
+		{"Carol", "carol@example.com", "41"},
+	}
+	if !reflect.DeepEqual(rows, expectedRows) {
+		t.Errorf("Expected: %+v\nGot: %+v", expectedRows, rows)
+	}
+
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(got) != 3 || got[1].Name != "Carol" {
+		t.Errorf("Expected Alice, Carol and Dave, got %+v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package rowboat
+
+import (
+	"io"
+	"io/fs"
+)
+
+// ProgressInfo describes how far a Reader has progressed through its input
+type ProgressInfo struct {
+	Rows  int   // data rows read, including skipped ones
+	Bytes int64 // bytes consumed from the input
+	Size  int64 // total size of the input, or -1 if unknown
+}
+
+// Percent returns the share of the input consumed, from 0 to 100, and
+// whether the input size is known
+func (p ProgressInfo) Percent() (float64, bool) {
+	if p.Size < 0 {
+		return 0, false
+	}
+	if p.Size == 0 {
+		return 100, true
+	}
+	return min(float64(p.Bytes)/float64(p.Size)*100, 100), true
+}
+
+// WithProgress calls report after every record a Reader yields and once
+// more when reading ends. Bytes counts the raw input, before decompression,
+// and includes data buffered ahead of the current record. The size is known
+// for inputs like *os.File, *bytes.Reader and *strings.Reader.
+func WithProgress(report func(ProgressInfo)) Option {
+	return func(o *options) {
+		o.progress = report
+	}
+}
+
+// progressReader counts the bytes read from an input of known or unknown size
+type progressReader struct {
+	r    io.Reader
+	n    int64
+	size int64
+}
+
+// newProgressReader wraps r, which starts at offset of the whole input
+func newProgressReader(r io.Reader, offset int64) *progressReader {
+	return &progressReader{r: r, n: offset, size: inputSize(r, offset)}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n += int64(n)
+	return n, err
+}
+
+// inputSize returns the total size of the input r, whose remaining data
+// starts at offset, or -1 if it cannot be determined
+func inputSize(r io.Reader, offset int64) int64 {
+	switch v := r.(type) {
+	case interface{ Size() int64 }:
+		return v.Size()
+	case interface{ Len() int }:
+		return offset + int64(v.Len())
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := v.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
+// trackProgress wraps the input in a progressReader when progress is
+// reported
+func (rb *Reader[T]) trackProgress(r io.Reader, offset int64) io.Reader {
+	if rb.opts.progress == nil {
+		return r
+	}
+	rb.input = newProgressReader(r, offset)
+	return rb.input
+}
+
+// reportProgress passes the current progress to the WithProgress callback
+func (rb *Reader[T]) reportProgress() {
+	if rb.opts.progress == nil {
+		return
+	}
+	rb.opts.progress(rb.progressInfo())
+}
+
+// progressInfo returns the rows and bytes read so far
+func (rb *Reader[T]) progressInfo() ProgressInfo {
+	info := ProgressInfo{Rows: rb.seen, Size: -1}
+	if rb.input != nil {
+		info.Bytes = rb.input.n
+		info.Size = rb.input.size
+	}
+	return info
+}
@@ -0,0 +1,76 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestWithProgress(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25
+`
+	var reports []rowboat.ProgressInfo
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithProgress(func(p rowboat.ProgressInfo) {
+		reports = append(reports, p)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	if _, err := rb.ReadAll(); err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 progress reports, got %d", len(reports))
+	}
+	for i, p := range reports {
+		if want := min(i+1, 2); p.Rows != want {
+			t.Errorf("Report %d: expected %d rows, got %d", i, want, p.Rows)
+		}
+		if p.Size != int64(len(csvData)) {
+			t.Errorf("Report %d: expected size %d, got %d", i, len(csvData), p.Size)
+		}
+	}
+	last := reports[len(reports)-1]
+	if pct, ok := last.Percent(); !ok || pct != 100 {
+		t.Errorf("Expected 100%% complete, got %v (known: %v)", pct, ok)
+	}
+}
+
+func TestWithProgressCompressed(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("Name,Email,Age\nAlice,alice@example.com,30\n"))
+	zw.Close()
+	size := int64(buf.Len())
+
+	var last rowboat.ProgressInfo
+	rb, err := rowboat.NewReader[Person](bytes.NewReader(buf.Bytes()),
+		rowboat.WithDecompression(),
+		rowboat.WithProgress(func(p rowboat.ProgressInfo) { last = p }))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	if _, err := rb.ReadAll(); err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if last.Rows != 1 || last.Bytes != size || last.Size != size {
+		t.Errorf("Expected 1 row and %d of %d bytes, got %+v", size, size, last)
+	}
+}
+
+func TestProgressUnknownSize(t *testing.T) {
+	info := rowboat.ProgressInfo{Rows: 1, Bytes: 10, Size: -1}
+	if _, ok := info.Percent(); ok {
+		t.Errorf("Expected unknown percentage")
+	}
+	info.Size = 40
+	if pct, _ := info.Percent(); pct != 25 {
+		t.Errorf("Expected 25%%, got %v", pct)
+	}
+}
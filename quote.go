@@ -0,0 +1,156 @@
+package rowboat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QuotePolicy selects which fields the Writer encloses in quotes
+type QuotePolicy int
+
+const (
+	QuoteMinimal QuotePolicy = iota // quote fields only when required, like encoding/csv
+	QuoteAll                        // quote every field, including empty ones
+	QuoteStrings                    // quote string fields and the header
+	QuoteNever                      // never quote; fields requiring quotes are an error
+)
+
+// WithQuoting sets which fields the Writer quotes. Fields with the quote
+// tag option are quoted regardless of the policy, except under QuoteNever.
+// Quoting control requires a Writer created by NewWriter.
+func WithQuoting(policy QuotePolicy) Option {
+	return func(o *options) {
+		o.quoting = policy
+	}
+}
+
+// WithEscapeFormulas defends exports opened in Excel or Sheets against CSV
+// injection. The Writer prefixes cells starting with =, +, -, @, a tab or a
+// carriage return with prefix, usually "'" or "\t", so that spreadsheets
+// show them as text instead of evaluating them as formulas. Cells holding
+// numbers, such as -5, and ciphertexts of encrypted fields are written
+// unchanged.
+func WithEscapeFormulas(prefix string) Option {
+	return func(o *options) {
+		o.formulaPrefix = prefix
+	}
+}
+
+// escapeFormula returns cell prefixed with prefix if a spreadsheet would
+// evaluate it as a formula
+func escapeFormula(cell, prefix string) string {
+	if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return cell
+	}
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+	return prefix + cell
+}
+
+// quoteWriter is a RecordWriter writing CSV with quoting control. It
+// otherwise produces the same output as csv.Writer.
+type quoteWriter struct {
+	w      *bufio.Writer
+	comma  rune
+	policy QuotePolicy
+	crlf   bool   // lines end with \r\n
+	quote  []bool // columns quoted in data records
+	header bool   // the next record is the header
+}
+
+// newQuoteWriter creates a quoteWriter writing to w
+func newQuoteWriter(w io.Writer, comma rune, policy QuotePolicy, crlf bool) *quoteWriter {
+	if comma == 0 {
+		comma = ','
+	}
+	return &quoteWriter{w: bufio.NewWriter(w), comma: comma, policy: policy, crlf: crlf}
+}
+
+// quoteColumns returns the output columns quoted in data records, given
+// the column of each field
+func quoteColumns(fields []fieldInfo, columns []int, width int, policy QuotePolicy) []bool {
+	quote := make([]bool, width)
+	for i, fi := range fields {
+		quote[columns[i]] = fi.Quote || (policy == QuoteStrings && fi.Field.Type.Kind() == reflect.String)
+	}
+	return quote
+}
+
+func (q *quoteWriter) Write(record []string) error {
+	header := q.header
+	q.header = false
+	if q.policy == QuoteNever {
+		for _, field := range record {
+			if q.needsQuotes(field) {
+				return fmt.Errorf("field %q requires quotes", field)
+			}
+		}
+	}
+	for i, field := range record {
+		if i > 0 {
+			q.w.WriteRune(q.comma)
+		}
+		quoted := q.needsQuotes(field)
+		switch q.policy {
+		case QuoteAll:
+			quoted = true
+		case QuoteStrings:
+			quoted = quoted || header
+		}
+		if q.policy != QuoteNever && i < len(q.quote) && q.quote[i] && !header {
+			quoted = true
+		}
+		if !quoted {
+			q.w.WriteString(field)
+			continue
+		}
+		field = strings.ReplaceAll(field, `"`, `""`)
+		if q.crlf {
+			field = strings.ReplaceAll(strings.ReplaceAll(field, "\r", ""), "\n", "\r\n")
+		}
+		q.w.WriteByte('"')
+		q.w.WriteString(field)
+		q.w.WriteByte('"')
+	}
+	if q.crlf {
+		_, err := q.w.WriteString("\r\n")
+		return err
+	}
+	_, err := q.w.WriteRune('\n')
+	return err
+}
+
+// needsQuotes reports whether field must be quoted to be read back, using
+// the rules of csv.Writer
+func (q *quoteWriter) needsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsRune(field, q.comma) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
+
+func (q *quoteWriter) Flush() {
+	q.w.Flush()
+}
+
+func (q *quoteWriter) Error() error {
+	_, err := q.w.Write(nil)
+	return err
+}
+
+// useQuoting reports whether the Writer needs quoting control for fields
+func (o *options) useQuoting(fields []fieldInfo) bool {
+	return o.quoting != QuoteMinimal || slices.ContainsFunc(fields, func(fi fieldInfo) bool { return fi.Quote })
+}
@@ -0,0 +1,134 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type QuotedItem struct {
+	SKU   string  `csv:"sku,quote"`
+	Name  string  `csv:"name"`
+	Price float64 `csv:"price"`
+}
+
+func TestQuoting(t *testing.T) {
+	items := []QuotedItem{{SKU: "A1", Name: "Widget, large", Price: 9.5}, {SKU: "B2", Name: "", Price: 0}}
+	tests := []struct {
+		name     string
+		policy   rowboat.QuotePolicy
+		expected string
+	}{
+		{"minimal", rowboat.QuoteMinimal, "sku,name,price\n\"A1\",\"Widget, large\",9.5\n\"B2\",,0\n"},
+		{"all", rowboat.QuoteAll, "\"sku\",\"name\",\"price\"\n\"A1\",\"Widget, large\",\"9.5\"\n\"B2\",\"\",\"0\"\n"},
+		{"strings", rowboat.QuoteStrings, "\"sku\",\"name\",\"price\"\n\"A1\",\"Widget, large\",9.5\n\"B2\",\"\",0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := rowboat.NewWriter[QuotedItem](&buf, rowboat.WithQuoting(tt.policy))
+			if err != nil {
+				t.Fatalf("Failed to create Writer: %v", err)
+			}
+			if err := writer.WriteHeader(); err != nil {
+				t.Fatalf("Failed to write header: %v", err)
+			}
+			if err := writer.WriteSlice(items); err != nil {
+				t.Fatalf("Failed to write records: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, buf.String())
+			}
+
+			// Quoted output reads back unchanged
+			rb, err := rowboat.NewReader[QuotedItem](strings.NewReader(buf.String()))
+			if err != nil {
+				t.Fatalf("Failed to create Reader: %v", err)
+			}
+			got, err := rb.ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to read records: %v", err)
+			}
+			if len(got) != len(items) || got[0] != items[0] || got[1] != items[1] {
+				t.Errorf("Expected: %+v\nGot: %+v", items, got)
+			}
+		})
+	}
+}
+
+func TestQuoteNever(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithQuoting(rowboat.QuoteNever))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Alice", Email: "alice@example.com", Age: 30}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Smith, Bob", Email: "bob@example.com", Age: 40}); err == nil {
+		t.Errorf("Expected an error for a field requiring quotes")
+	}
+	writer.Flush()
+	if expected := "Alice,alice@example.com,30\n"; buf.String() != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+	}
+}
+
+func TestEscapeFormulas(t *testing.T) {
+	items := []QuotedItem{
+		{SKU: "=HYPERLINK(\"http://evil\")", Name: "+1+cmd|' /C calc'!A0", Price: -5},
+		{SKU: "@SUM(A1)", Name: "-", Price: 1},
+		{SKU: "A1", Name: "\tindented", Price: 2},
+	}
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[QuotedItem](&buf, rowboat.WithEscapeFormulas("'"))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(items); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	expected := "\"'=HYPERLINK(\"\"http://evil\"\")\",'+1+cmd|' /C calc'!A0,-5\n" +
+		"\"'@SUM(A1)\",'-,1\n" +
+		"\"A1\",'\tindented,2\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, buf.String())
+	}
+}
+
+func FuzzQuoting(f *testing.F) {
+	for _, seed := range []string{"", "plain", "a,b", "\"quoted\"", " leading", "line\nbreak", "cr\r\nlf", `\.`, "=1+1"} {
+		f.Add(seed, "other")
+	}
+	policies := []rowboat.QuotePolicy{rowboat.QuoteMinimal, rowboat.QuoteAll, rowboat.QuoteStrings}
+	f.Fuzz(func(t *testing.T, sku, name string) {
+		if strings.Contains(sku+name, "\r\n") {
+			t.Skip("encoding/csv reads \\r\\n inside quoted fields as \\n")
+		}
+		item := QuotedItem{SKU: sku, Name: name, Price: 1.5}
+		for _, policy := range policies {
+			var buf bytes.Buffer
+			writer, err := rowboat.NewWriter[QuotedItem](&buf, rowboat.WithQuoting(policy))
+			if err != nil {
+				t.Fatalf("Failed to create Writer: %v", err)
+			}
+			if err := writer.Write(item); err != nil {
+				t.Fatalf("Failed to write record: %v", err)
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatalf("Failed to flush Writer: %v", err)
+			}
+			record, err := csv.NewReader(&buf).Read()
+			if err != nil {
+				t.Fatalf("Failed to read back %q: %v", buf.String(), err)
+			}
+			if expected := []string{sku, name, "1.5"}; !reflect.DeepEqual(record, expected) {
+				t.Errorf("Expected: %q\nGot: %q", expected, record)
+			}
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package rowboat
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// RangeReader reads byte ranges of an object in remote storage, such as S3
+// GetObject with a Range header. The returned body holds the bytes from
+// offset up to offset+length.
+type RangeReader interface {
+	ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+}
+
+// Defaults of NewRangeInput
+const (
+	defaultChunkSize = 8 << 20
+	defaultReadAhead = 2
+)
+
+// WithChunkSize sets the bytes NewRangeInput fetches per range request
+func WithChunkSize(n int64) Option {
+	return func(o *options) {
+		o.chunkSize = n
+	}
+}
+
+// WithReadAhead sets the number of chunks NewRangeInput fetches ahead of
+// the parser. Higher values hide more latency at the cost of memory.
+func WithReadAhead(n int) Option {
+	return func(o *options) {
+		o.readAhead = n
+	}
+}
+
+// NewRangeInput streams an object of the given size from rr in chunks of
+// WithChunkSize bytes, fetching WithReadAhead chunks concurrently while
+// earlier ones are parsed. Multi-gigabyte objects are read this way without
+// buffering them fully. Close stops the fetches in flight.
+func NewRangeInput(ctx context.Context, rr RangeReader, size int64, opts ...Option) io.ReadCloser {
+	o := newOptions(opts)
+	chunkSize, readAhead := o.chunkSize, o.readAhead
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	if readAhead <= 0 {
+		readAhead = defaultReadAhead
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	in := &rangeInput{ctx: ctx, cancel: cancel, chunks: make(chan chan rangeChunk, readAhead)}
+	go in.fetch(rr, size, chunkSize)
+	return in
+}
+
+// rangeChunk is the result of one range request
+type rangeChunk struct {
+	data []byte
+	err  error
+}
+
+// rangeInput is the io.ReadCloser returned by NewRangeInput
+type rangeInput struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	chunks chan chan rangeChunk // pending chunks in object order
+	data   []byte               // unread bytes of the current chunk
+	err    error
+}
+
+// fetch starts a request per chunk, staying at most cap(in.chunks) chunks
+// ahead of Read
+func (in *rangeInput) fetch(rr RangeReader, size, chunkSize int64) {
+	defer close(in.chunks)
+	for offset := int64(0); offset < size; offset += chunkSize {
+		result := make(chan rangeChunk, 1)
+		select {
+		case in.chunks <- result:
+		case <-in.ctx.Done():
+			return
+		}
+		go func(offset, length int64) {
+			result <- readRange(in.ctx, rr, offset, length)
+		}(offset, min(chunkSize, size-offset))
+	}
+}
+
+// readRange fetches a whole range
+func readRange(ctx context.Context, rr RangeReader, offset, length int64) rangeChunk {
+	body, err := rr.ReadRange(ctx, offset, length)
+	if err != nil {
+		return rangeChunk{err: fmt.Errorf("range %d-%d: %w", offset, offset+length-1, err)}
+	}
+	defer body.Close()
+	data := make([]byte, length)
+	if _, err := io.ReadFull(body, data); err != nil {
+		return rangeChunk{err: fmt.Errorf("range %d-%d: %w", offset, offset+length-1, err)}
+	}
+	return rangeChunk{data: data}
+}
+
+func (in *rangeInput) Read(p []byte) (int, error) {
+	for len(in.data) == 0 {
+		if in.err != nil {
+			return 0, in.err
+		}
+		result, ok := <-in.chunks
+		if !ok {
+			if in.err = in.ctx.Err(); in.err == nil {
+				in.err = io.EOF
+			}
+			continue
+		}
+		chunk := <-result
+		in.data, in.err = chunk.data, chunk.err
+	}
+	n := copy(p, in.data)
+	in.data = in.data[n:]
+	return n, nil
+}
+
+// Close cancels the pending range requests
+func (in *rangeInput) Close() error {
+	in.cancel()
+	return nil
+}
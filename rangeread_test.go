@@ -0,0 +1,66 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+// rangeObject serves byte ranges of an in-memory object
+type rangeObject struct {
+	data     []byte
+	failAt   int64 // offset of a range that fails, or -1
+	mu       sync.Mutex
+	requests int
+}
+
+func (o *rangeObject) ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	o.mu.Lock()
+	o.requests++
+	o.mu.Unlock()
+	if offset == o.failAt {
+		return nil, errors.New("connection reset")
+	}
+	return io.NopCloser(bytes.NewReader(o.data[offset : offset+length])), nil
+}
+
+func TestRangeInput(t *testing.T) {
+	var csvData strings.Builder
+	csvData.WriteString("Name,Email,Age\n")
+	for i := range 1000 {
+		fmt.Fprintf(&csvData, "P%d,p%d@example.com,%d\n", i, i, i%90)
+	}
+	obj := &rangeObject{data: []byte(csvData.String()), failAt: -1}
+	size := int64(len(obj.data))
+
+	input := rowboat.NewRangeInput(context.Background(), obj, size, rowboat.WithChunkSize(1000), rowboat.WithReadAhead(3))
+	defer input.Close()
+	rb, err := rowboat.NewReader[Person](input)
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(got) != 1000 || got[999].Name != "P999" {
+		t.Fatalf("Expected 1000 records ending with P999, got %d", len(got))
+	}
+	if expected := int((size + 999) / 1000); obj.requests != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, obj.requests)
+	}
+
+	obj.failAt = 3000
+	input = rowboat.NewRangeInput(context.Background(), obj, size, rowboat.WithChunkSize(1000))
+	defer input.Close()
+	if _, err := io.ReadAll(input); err == nil || !strings.Contains(err.Error(), "range 3000-3999: connection reset") {
+		t.Errorf("Expected the failed range in the error, got %v", err)
+	}
+}
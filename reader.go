@@ -1,13 +1,16 @@
 package rowboat
 
 import (
+	"bufio"
+	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"iter"
+	"math"
 	"reflect"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,153 +21,723 @@ type CSVUnmarshaler interface {
 	UnmarshalCSV(string) error
 }
 
+// RecordReader is a source of raw records. *csv.Reader implements it, and
+// other tabular formats can implement it to reuse rowboat's struct mapping.
+type RecordReader interface {
+	Read() (record []string, err error)
+}
+
+// fieldPositioner is implemented by record sources that know where a
+// record starts in the input, like *csv.Reader
+type fieldPositioner interface {
+	FieldPos(field int) (line, column int)
+}
+
+// inputOffsetter is implemented by record sources that track how much of
+// the input they consumed, like *csv.Reader
+type inputOffsetter interface {
+	InputOffset() int64
+}
+
 // Reader struct holds the CSV reader and mapping information
 type Reader[T any] struct {
-	reader   *csv.Reader
+	reader   RecordReader
+	opts     *options
 	headers  []string
-	fieldMap map[int]reflect.StructField
+	fields   []fieldInfo
+	fieldMap map[int]*fieldInfo
 	err      error
+	errs     []error
 	current  T
+	line     int
+	count    int // data rows accepted
+	seen     int // data rows read, including filtered and failed ones
+	records  int
+	init     func() error
+	initErr  error
+	validate []func(T) error
+	offset   int64 // position of the record source's start in the input
+	lines    int   // lines of the input preceding the record source
+	input    *progressReader
+	index    []int            // field positions for the generated decoder, nil if unused
+	scratch  reflect.Value    // pointer to the struct records are decoded into
+	trailer  []string         // trailer row ending the data, nil if not reached
+	sum      *bodyHash        // checksum of the rows read under WithChecksum
+	stats    *Stats           // statistics gathered under WithStats
+	columns  []int            // record position of each field for stats
+	buffered []bufferedRecord // records read ahead by Preview
+	replayed *bufferedRecord  // last record returned from buffered
+	raw      []string         // record of the current row, reused by the next read
+	padded   int              // empty cells appended to the current record by WithPadShortRows
+	checked  bool             // every field has a way to be decoded
 }
 
 // NewReader creates a new RowBoat reader instance
-func NewReader[T any](r io.Reader) (*Reader[T], error) {
-	rb := &Reader[T]{}
-	rb.reader = csv.NewReader(r)
+func NewReader[T any](r io.Reader, opts ...Option) (*Reader[T], error) {
+	rb, err := NewLazyReader[T](r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := rb.Init(); err != nil {
+		return nil, err
+	}
+	return rb, nil
+}
 
-	// Read headers
-	headers, err := rb.reader.Read()
+// NewLazyReader creates a Reader without touching r, so that it can be
+// constructed before any data is available. The header is read by Init or,
+// failing that, by the first read of a record.
+func NewLazyReader[T any](r io.Reader, opts ...Option) (*Reader[T], error) {
+	rb, err := newReader[T](opts)
 	if err != nil {
 		return nil, err
 	}
-	rb.headers = headers
 
-	// Map CSV headers to struct fields
-	if err := rb.createFieldMap(); err != nil {
+	rb.init = func() error {
+		r, bom, err := rb.opts.openInput(rb.trackProgress(r, 0))
+		if err != nil {
+			return err
+		}
+		r, rb.offset, rb.lines, err = rb.opts.skipPreamble(r)
+		if err != nil {
+			return err
+		}
+		rb.offset += bom
+		if rb.opts.sniff {
+			r = rb.opts.sniffInput(r)
+		}
+
+		// Verify the schema fingerprint preceding the header
+		if rb.opts.schemaHash {
+			br := bufio.NewReader(r)
+			n, err := verifySchemaComment(br, rb.fields)
+			if err != nil {
+				return err
+			}
+			rb.offset += int64(n)
+			rb.lines++
+			r = br
+		}
+		csvReader := csv.NewReader(r)
+		rb.opts.configureReader(csvReader)
+		csvReader.ReuseRecord = true
+		rb.reader = csvReader
+
+		return rb.readHeader()
+	}
+	return rb, nil
+}
+
+// Init reads the header of a Reader created by NewLazyReader. It only
+// reads once and returns the same error on later calls.
+func (rb *Reader[T]) Init() error {
+	if rb.init != nil {
+		rb.initErr = rb.init()
+		rb.init = nil
+	}
+	return rb.initErr
+}
+
+// NewReaderAt creates a Reader resuming at offset, a value previously
+// returned by Offset. The header is not read again; headers supplies the
+// column names instead. Line numbers count from the resumed position.
+// Offsets are positions in the CSV text, so resuming does not work with
+// compressed or transcoded inputs.
+func NewReaderAt[T any](r io.ReadSeeker, offset int64, headers []string, opts ...Option) (*Reader[T], error) {
+	rb, err := newReader[T](opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
 		return nil, err
 	}
+	input, err := rb.opts.wrapInput(rb.trackProgress(r, offset))
+	if err != nil {
+		return nil, err
+	}
+	csvReader := csv.NewReader(input)
+	rb.opts.configureReader(csvReader)
+	csvReader.ReuseRecord = true
+	rb.reader = csvReader
+	rb.offset = offset
+	rb.headers = slices.Clone(headers)
 
+	if err := rb.createFieldMap(); err != nil {
+		return nil, err
+	}
 	return rb, nil
 }
 
-// createFieldMap maps CSV headers to struct fields using struct tags
-func (rb *Reader[T]) createFieldMap() error {
-	rb.fieldMap = make(map[int]reflect.StructField)
+// FromCSVReader creates a Reader decoding the records of a configured
+// csv.Reader, keeping its settings such as Comma, Comment, FieldsPerRecord
+// and ReuseRecord. Options setting the format, like WithDelimiter, have no
+// effect; the first record is read as the header.
+func FromCSVReader[T any](r *csv.Reader, opts ...Option) (*Reader[T], error) {
+	return NewRecordReader[T](r, opts...)
+}
 
-	var t T
-	tType := reflect.TypeOf(t)
-	if tType.Kind() != reflect.Struct {
-		return errors.New("generic type T must be a struct")
+// NewRecordReader creates a Reader decoding the records of src. The first
+// record is read as the header. Options acting on the raw byte stream, such
+// as decompression or the schema fingerprint, do not apply.
+func NewRecordReader[T any](src RecordReader, opts ...Option) (*Reader[T], error) {
+	rb, err := newReader[T](opts)
+	if err != nil {
+		return nil, err
 	}
+	rb.reader = src
 
-	// First pass: collect fields and their indexes
-	type fieldInfo struct {
-		field reflect.StructField
-		name  string
-		index int
+	if err := rb.readHeader(); err != nil {
+		return nil, err
 	}
-	fields := make([]fieldInfo, 0, tType.NumField())
-	maxIndex := -1
+	return rb, nil
+}
 
-	for i := 0; i < tType.NumField(); i++ {
-		field := tType.Field(i)
-		csvTag := field.Tag.Get("csv")
-		if csvTag == "-" {
-			continue
+// newReader creates a Reader for T without a record source
+func newReader[T any](opts []Option) (*Reader[T], error) {
+	rb := &Reader[T]{opts: newTypeOptions[T](opts)}
+	rb.sum = newBodyHash(rb.opts.checksum)
+
+	fields, err := cachedFields(recordType[T]())
+	if err != nil {
+		return nil, err
+	}
+	rb.fields = fields
+
+	// Check that projected columns exist
+	for _, name := range rb.opts.columns {
+		if !slices.ContainsFunc(rb.fields, func(fi fieldInfo) bool { return fi.matches(name) }) {
+			return nil, fmt.Errorf("unknown column '%s'", name)
+		}
+	}
+	for _, name := range rb.opts.headerMapping {
+		if !slices.ContainsFunc(rb.fields, func(fi fieldInfo) bool { return fi.matches(name) }) {
+			return nil, fmt.Errorf("unknown field '%s'", name)
 		}
+	}
+
+	if err := rb.opts.applyLocale(rb.fields); err != nil {
+		return nil, err
+	}
+	if err := rb.opts.checkCipher(rb.fields); err != nil {
+		return nil, err
+	}
+	if rb.validate, err = recordValidators[T](rb.opts); err != nil {
+		return nil, err
+	}
 
-		name := field.Name
-		index := i // default index is field order
-		tagParts := strings.Split(csvTag, ",")
-		if len(tagParts) > 0 && tagParts[0] != "" {
-			name = tagParts[0]
+	// Attach converters and value maps supplied as options
+	for i := range rb.fields {
+		fn, ok, err := fieldOption(rb.opts.converters, rb.fields[i], "converters")
+		if err != nil {
+			return nil, err
 		}
+		if ok {
+			rb.fields[i].Decode = fn
+		}
+		values, ok, err := fieldOption(rb.opts.valueMaps, rb.fields[i], "value maps")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			rb.fields[i].Values = values
+		}
+	}
+	return rb, nil
+}
 
-		for _, part := range tagParts[1:] {
-			part = strings.TrimSpace(part)
-			if strings.HasPrefix(part, "index=") {
-				idxStr := strings.TrimPrefix(part, "index=")
-				idx, err := strconv.Atoi(idxStr)
-				if err != nil {
-					return fmt.Errorf("invalid index value '%s' in field '%s': %v", idxStr, field.Name, err)
-				}
-				index = idx
-				if index > maxIndex {
-					maxIndex = index
-				}
-			}
+// read reads the next raw record from the source
+func (rb *Reader[T]) read() ([]string, error) {
+	rb.replayed = nil
+	if len(rb.buffered) > 0 {
+		rb.replayed = &rb.buffered[0]
+		rb.buffered = rb.buffered[1:]
+		rb.padded = rb.replayed.padded
+		if rb.replayed.record != nil {
+			rb.records++
 		}
+		return rb.replayed.record, rb.replayed.err
+	}
+	record, err := rb.reader.Read()
+	if record != nil {
+		rb.records++
+	}
+	if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+		return record, err
+	}
+	return rb.checkRead(record, err, rb.recordLine())
+}
+
+// checkRead applies the size limits to a record read with err, and accepts,
+// truncates or describes rows with a wrong number of fields
+func (rb *Reader[T]) checkRead(record []string, err error, line int) ([]string, error) {
+	rb.padded = 0
+	if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+		return record, err
+	}
+	if limitErr := rb.opts.checkRecord(record); limitErr != nil {
+		return record, &RowError{Line: line, Err: limitErr}
+	}
+	if err == nil {
+		return record, nil
+	}
+	expected := len(rb.headers)
+	if cr, ok := rb.reader.(*csv.Reader); ok && cr.FieldsPerRecord > 0 {
+		expected = cr.FieldsPerRecord
+	}
+	switch {
+	case len(record) < expected && rb.opts.padShort:
+		rb.padded = expected - len(record)
+		return append(slices.Clip(record), make([]string, rb.padded)...), nil
+	case len(record) > expected && rb.opts.truncateLong:
+		return record[:expected], nil
+	}
+	countErr := &FieldCountError{Expected: expected, Got: len(record)}
+	if len(record) < expected && len(record) < len(rb.headers) {
+		countErr.Missing = slices.Clone(rb.headers[len(record):min(expected, len(rb.headers))])
+	}
+	return record, &RowError{Line: line, Err: countErr}
+}
+
+// Offset returns the byte offset in the input just past the last record
+// read. Passing it to NewReaderAt resumes reading at the next record. It is
+// zero for record sources that do not track their offset.
+func (rb *Reader[T]) Offset() int64 {
+	if rb.replayed != nil {
+		return rb.replayed.offset
+	}
+	return rb.sourceOffset()
+}
+
+// sourceOffset returns the byte offset in the input just past the last
+// record read from the source
+func (rb *Reader[T]) sourceOffset() int64 {
+	if oi, ok := rb.reader.(inputOffsetter); ok {
+		return rb.offset + oi.InputOffset()
+	}
+	return 0
+}
+
+// Line returns the line where the last record read starts
+func (rb *Reader[T]) Line() int {
+	return rb.line
+}
+
+// recordLine returns the line where the last record read starts
+func (rb *Reader[T]) recordLine() int {
+	if rb.replayed != nil {
+		return rb.replayed.line
+	}
+	return rb.sourceLine(rb.records)
+}
 
-		fields = append(fields, fieldInfo{
-			field: field,
-			name:  name,
-			index: index,
-		})
+// sourceLine returns the line where the last record read from the source
+// starts, given the number of records read including it
+func (rb *Reader[T]) sourceLine(records int) int {
+	if fp, ok := rb.reader.(fieldPositioner); ok {
+		line, _ := fp.FieldPos(0)
+		return rb.lines + line
 	}
+	return records
+}
 
-	// Assign indexes to fields without explicit index
-	nextIndex := maxIndex + 1
-	for i := range fields {
-		if fields[i].index == fields[i].field.Index[0] {
-			fields[i].index = nextIndex
-			nextIndex++
+// readHeader reads the header row and maps it to the struct fields
+func (rb *Reader[T]) readHeader() error {
+	headers, err := rb.read()
+	if err != nil {
+		return err
+	}
+	if len(headers) > 0 {
+		headers[0] = strings.TrimPrefix(headers[0], utf8BOM)
+	}
+	rb.headers = slices.Clone(headers) // the source may reuse the record
+
+	// Skip the type row describing the columns
+	if rb.opts.typeRow {
+		if _, err := rb.read(); err != nil {
+			return err
 		}
 	}
 
-	// Sort fields by index
-	sort.Slice(fields, func(i, j int) bool {
-		return fields[i].index < fields[j].index
-	})
+	// Map CSV headers to struct fields
+	return rb.createFieldMap()
+}
+
+// createFieldMap maps CSV headers to struct fields using struct tags
+func (rb *Reader[T]) createFieldMap() error {
+	rb.fieldMap = make(map[int]*fieldInfo)
 
-	// Map headers to fields
+	// Map fields to the columns at their index, ignoring header names
+	if rb.opts.positional {
+		for i := range rb.fields {
+			if rb.selected(rb.fields[i]) {
+				rb.fieldMap[rb.fields[i].Index] = &rb.fields[i]
+			}
+		}
+		rb.index = rb.codecIndex()
+		return nil
+	}
+
+	// Map headers to fields, resolving duplicates by the policy
 	headerMap := make(map[string]int)
+	counts := make(map[string]int)
+	var duplicated []string
+	taken := make(map[string]bool) // names that suffixed duplicates must avoid
+	for _, header := range rb.headers {
+		taken[strings.TrimSpace(header)] = true
+	}
+	suffixes := make(map[string]int)
 	for i, header := range rb.headers {
-		headerMap[strings.TrimSpace(header)] = i
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue // blank columns, e.g. index gaps
+		}
+		counts[header]++
+		if counts[header] == 1 {
+			headerMap[header] = i
+			continue
+		}
+		switch rb.opts.duplicates {
+		case DuplicateLastWins:
+			headerMap[header] = i
+		case DuplicateError:
+			if counts[header] == 2 {
+				duplicated = append(duplicated, header)
+			}
+		case DuplicateSuffix:
+			// Skip suffixes naming another column, e.g. a real email_2
+			n := max(suffixes[header], 1)
+			name := ""
+			for n++; ; n++ {
+				if name = fmt.Sprintf("%s_%d", header, n); !taken[name] {
+					break
+				}
+			}
+			suffixes[header], taken[name] = n, true
+			rb.headers[i] = name
+			headerMap[name] = i
+		}
+	}
+	if len(duplicated) > 0 {
+		return &HeaderError{Duplicated: duplicated}
+	}
+
+	// Bind headers mapped at runtime before matching tag names
+	bound := make(map[*fieldInfo]bool)
+	for idx, header := range rb.headers {
+		header = strings.TrimSpace(header)
+		target, ok := rb.opts.headerMapping[header]
+		if !ok || headerMap[header] != idx {
+			continue
+		}
+		for i := range rb.fields {
+			if rb.fields[i].matches(target) && rb.selected(rb.fields[i]) {
+				rb.fieldMap[idx] = &rb.fields[i]
+				bound[&rb.fields[i]] = true
+			}
+		}
 	}
 
 	// Create final field mapping
-	for _, fi := range fields {
-		if idx, ok := headerMap[fi.name]; ok {
-			rb.fieldMap[idx] = fi.field
+	for i := range rb.fields {
+		if !rb.selected(rb.fields[i]) || bound[&rb.fields[i]] {
+			continue
+		}
+		for _, name := range rb.fields[i].columnNames() {
+			if idx, ok := headerMap[name]; ok && rb.fieldMap[idx] == nil {
+				rb.fieldMap[idx] = &rb.fields[i]
+				break
+			}
+		}
+	}
+
+	rb.index = rb.codecIndex()
+	return nil
+}
+
+// fieldColumns returns the record position of each field, or -1 for
+// fields matching no column
+func (rb *Reader[T]) fieldColumns() []int {
+	columns := make([]int, len(rb.fields))
+	for i := range rb.fields {
+		columns[i] = -1
+		for pos, fi := range rb.fieldMap {
+			if fi == &rb.fields[i] {
+				columns[i] = pos
+			}
 		}
 	}
+	return columns
+}
+
+// selected reports whether the field is decoded under WithColumns
+func (rb *Reader[T]) selected(fi fieldInfo) bool {
+	if len(rb.opts.columns) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(rb.opts.columns, fi.matches)
+}
+
+// checkFields fails on fields the Reader cannot decode. It runs on the
+// first read rather than in NewReader, so that RegisterConverter can map
+// such fields first.
+func (rb *Reader[T]) checkFields() error {
+	if rb.checked {
+		return nil
+	}
+	if err := checkMappable(rb.fields, false); err != nil {
+		return err
+	}
+	rb.checked = true
+	return nil
+}
 
+// RegisterConverter attaches a conversion function to the field identified
+// by its Go field name or CSV column name. The converter replaces the
+// built-in decoding for that field; its result must be assignable to the
+// field's type, or a number that fits in it.
+func (rb *Reader[T]) RegisterConverter(fieldName string, fn func(string) (any, error)) error {
+	found := false
+	for i := range rb.fields {
+		if rb.fields[i].matches(fieldName) {
+			rb.fields[i].Decode = fn
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown field '%s'", fieldName)
+	}
+	rb.index = nil
 	return nil
 }
 
 // nextRow advances the iterator and parses the next record
 func (rb *Reader[T]) nextRow() bool {
-	record, err := rb.reader.Read()
-	if err == io.EOF {
+	if err := rb.Init(); err != nil {
+		rb.err = err
 		return false
 	}
-	if err != nil {
+	if err := rb.checkFields(); err != nil {
 		rb.err = err
 		return false
 	}
+	if rb.trailer != nil {
+		return false
+	}
+	for {
+		record, err := rb.read()
+		if err == io.EOF {
+			if rb.sum != nil {
+				rb.err = fmt.Errorf("%w: missing trailer", ErrChecksum)
+			}
+			rb.reportProgress()
+			return false
+		}
+		// Stop at the trailer, which may have a different number of fields
+		if rb.isTrailerRecord(record, err) {
+			rb.trailer = slices.Clone(record)
+			if rb.sum != nil {
+				rb.err = rb.sum.verify(rb.trailer)
+			}
+			if rb.err == nil {
+				rb.err = rb.checkAfterTrailer()
+			}
+			rb.reportProgress()
+			return false
+		}
+		if err == nil && rb.sum != nil {
+			rb.sum.add(record)
+		}
+		if err != nil {
+			if skippable(err) {
+				attachRaw(err, record)
+				if rb.tolerate(err) {
+					continue
+				}
+				return false
+			}
+			rb.err = err
+			return false
+		}
 
-	var t T
-	tValue := reflect.ValueOf(&t).Elem()
+		rb.seen++
 
-	for idx, value := range record {
-		if field, ok := rb.fieldMap[idx]; ok {
-			fieldValue := tValue.FieldByName(field.Name)
-			if !fieldValue.CanSet() {
+		// Skip rows rejected by the raw row filter before decoding
+		if rb.opts.rowFilter != nil && !rb.opts.rowFilter(record) {
+			continue
+		}
+
+		if rb.opts.maxRows > 0 && rb.count >= rb.opts.maxRows {
+			rb.err = fmt.Errorf("%w: more than %d rows", ErrRowLimit, rb.opts.maxRows)
+			return false
+		}
+
+		rb.line = rb.recordLine()
+
+		t, err := rb.decodeRecord(record)
+		if err == nil {
+			if err = validateRecord(rb.validate, t); err != nil {
+				err = &RowError{Line: rb.line, Err: err}
+			}
+		}
+		if err != nil {
+			attachRaw(err, record)
+			if rb.tolerate(err) {
 				continue
 			}
-			if err := setFieldValue(fieldValue, value); err != nil {
-				rb.err = fmt.Errorf("error setting field %s: %w", field.Name, err)
-				return false
+			return false
+		}
+		if rb.opts.stats {
+			if rb.stats == nil {
+				rb.stats = newStats(rb.fields, true, rb.opts)
+				rb.columns = rb.fieldColumns()
+			}
+			rb.stats.add(rb.scratch.Elem(), rb.fields, record, rb.columns)
+		}
+		rb.current = t
+		rb.raw = record
+		rb.count++
+		rb.reportProgress()
+		return true
+	}
+}
+
+// decodeRecord converts a raw record into a value of type T
+func (rb *Reader[T]) decodeRecord(record []string) (T, error) {
+	// Decode into a reused value, so that rows do not escape to the heap
+	if !rb.scratch.IsValid() {
+		rb.scratch = reflect.New(recordType[T]())
+	}
+	// Fields of padded cells keep their zero values
+	record = record[:len(record)-rb.padded]
+
+	if rb.index != nil {
+		err := rb.scratch.Interface().(RowUnmarshaler).UnmarshalCSVRow(record, rb.index)
+		if err == nil {
+			err = afterDecode(rb.scratch)
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErr.Line = rb.line
+		} else if err != nil {
+			err = &RowError{Line: rb.line, Err: err}
+		}
+		return rb.result(), err
+	}
+	tValue := rb.scratch.Elem()
+	tValue.SetZero()
+
+	for idx, value := range record {
+		if fi, ok := rb.fieldMap[idx]; ok {
+			fieldValue := fi.settable(tValue)
+			if fi.Encrypt && value != "" && !rb.opts.isNull(value, fi.Field.Type) {
+				var err error
+				if value, err = rb.opts.decrypt(fi.Name, value); err != nil {
+					return rb.result(), &RowError{
+						Line:   rb.line,
+						Column: fi.Name,
+						Err:    fmt.Errorf("error decrypting field %s: %w", fi.Field.Name, err),
+					}
+				}
+			}
+			value = fi.trim(value, rb.opts)
+			if mapped, ok := fi.Values[value]; ok && !fi.Raw {
+				value = mapped
+			}
+			if err := setFieldValue(fieldValue, value, *fi, rb.opts); err != nil {
+				return rb.result(), &RowError{
+					Line:   rb.line,
+					Column: fi.Name,
+					Err:    fmt.Errorf("error setting field %s: %w", fi.Field.Name, err),
+				}
 			}
 		}
 	}
-	rb.current = t
-	return true
+	if err := afterDecode(rb.scratch); err != nil {
+		return rb.result(), &RowError{Line: rb.line, Err: err}
+	}
+	return rb.result(), nil
 }
 
+// result returns the decoded record. Pointer records get a copy of the
+// reused struct.
+func (rb *Reader[T]) result() T {
+	if ptr, ok := rb.scratch.Interface().(*T); ok {
+		return *ptr
+	}
+	ptr := reflect.New(rb.scratch.Type().Elem())
+	ptr.Elem().Set(rb.scratch.Elem())
+	return ptr.Interface().(T)
+}
+
+// tolerate records a row error and reports whether reading may continue
+// under the WithMaxErrors budget. Otherwise it stores the error that
+// terminates reading.
+func (rb *Reader[T]) tolerate(err error) bool {
+	if rb.opts.maxErrors <= 0 {
+		rb.err = err
+		return false
+	}
+	rb.errs = append(rb.errs, err)
+	if len(rb.errs) <= rb.opts.maxErrors {
+		return true
+	}
+	rb.err = &MultiRowError{Errors: slices.Clone(rb.errs)}
+	return false
+}
+
+// Errors returns the row errors skipped so far under WithMaxErrors
+func (rb *Reader[T]) Errors() []error {
+	return rb.errs
+}
+
+// csvUnmarshalerType is the reflect.Type of the CSVUnmarshaler interface
+var csvUnmarshalerType = reflect.TypeOf((*CSVUnmarshaler)(nil)).Elem()
+
 // setFieldValue sets the value of a struct field based on its type
-func setFieldValue(field reflect.Value, value string) error {
-	csvUnmarshalerType := reflect.TypeOf((*CSVUnmarshaler)(nil)).Elem()
+func setFieldValue(field reflect.Value, value string, fi fieldInfo, opts *options) error {
+	// Leave empty and null cells at the zero value
+	if (value == "" && fi.omitEmpty(opts)) || opts.isNull(value, field.Type()) {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if err := fi.checkEnum(value, opts); err != nil {
+		return err
+	}
+
+	// Decode pointer fields into a newly allocated value
+	if field.Kind() == reflect.Pointer && fi.Decode == nil {
+		elem := reflect.New(field.Type().Elem())
+		if err := setFieldValue(elem.Elem(), value, fi, opts); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	// Store raw cells verbatim
+	if fi.Raw {
+		if field.Kind() == reflect.String {
+			field.SetString(value)
+		} else {
+			field.SetBytes([]byte(value))
+		}
+		return nil
+	}
+
+	// Use a converter registered at runtime
+	if fi.Decode != nil {
+		v, err := fi.Decode(value)
+		if err != nil {
+			return err
+		}
+		return assignValue(field, v)
+	}
+
+	// Parse decimals like other numbers, before their UnmarshalCSV method
+	if field.Type() == decimalType {
+		return decodeDecimal(field, value, fi, opts)
+	}
 
 	// Check if the field implements CSVUnmarshaler
 	if field.CanInterface() && field.Type().Implements(csvUnmarshalerType) {
@@ -178,9 +751,32 @@ func setFieldValue(field reflect.Value, value string) error {
 		return unmarshaler.UnmarshalCSV(value)
 	}
 
+	// Use the constants of registered enums
+	if m := lookupEnum(field.Type()); m != nil {
+		return m.decode(field, value)
+	}
+
+	// Handle ISO 8601 durations and week dates
+	switch fi.Format {
+	case tagISODuration:
+		d, err := parseISODuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	case tagISOWeek:
+		t, err := parseISOWeek(value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
 	// Handle specific types like time.Time
 	if field.Type() == reflect.TypeOf(time.Time{}) {
-		t, err := time.Parse(time.RFC3339, value)
+		t, err := time.Parse(fi.timeLayout(opts), value)
 		if err != nil {
 			return err
 		}
@@ -193,16 +789,32 @@ func setFieldValue(field reflect.Value, value string) error {
 	case reflect.String:
 		field.SetString(value)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = fi.normalizeNumber(value, opts)
 		intValue, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
 			return err
 		}
 		field.SetInt(intValue)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = fi.normalizeNumber(value, opts)
+		uintValue, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(uintValue)
 	case reflect.Float32, reflect.Float64:
-		floatValue, err := strconv.ParseFloat(value, 64)
+		if value == "" && opts.nonFinite == NonFiniteEmpty {
+			field.SetFloat(math.NaN())
+			return nil
+		}
+		value = fi.normalizeNumber(value, opts)
+		floatValue, err := strconv.ParseFloat(value, field.Type().Bits())
 		if err != nil {
 			return err
 		}
+		if opts.nonFinite == NonFiniteError && (math.IsNaN(floatValue) || math.IsInf(floatValue, 0)) {
+			return fmt.Errorf("non-finite value %s not allowed", value)
+		}
 		field.SetFloat(floatValue)
 	case reflect.Bool:
 		boolValue, err := strconv.ParseBool(value)
@@ -240,16 +852,103 @@ func (rb *Reader[T]) All() iter.Seq[T] {
 	}
 }
 
-// Filter returns a sequence that contains the elements
-// of s for which f returns true.
-func Filter[V any](f func(V) bool, s iter.Seq[V]) iter.Seq[V] {
-	return func(yield func(V) bool) {
-		for v := range s {
-			if f(v) {
-				if !yield(v) {
-					return
-				}
+// AllWithRaw returns an iterator over all records together with the raw
+// record each was decoded from, e.g. to quarantine or re-emit the original
+// of a row rejected downstream. Each raw record is a copy that may be
+// retained. Like All, it panics if reading fails. Rows rejected by the
+// Reader itself carry their raw record in RowError.Raw, see Errors.
+func (rb *Reader[T]) AllWithRaw() iter.Seq2[T, []string] {
+	return func(yield func(T, []string) bool) {
+		for rb.nextRow() {
+			if !yield(rb.current, slices.Clone(rb.raw)) {
+				return
+			}
+		}
+		if rb.err != nil && rb.err != io.EOF {
+			panic(rb.err)
+		}
+	}
+}
+
+// ReadAll reads all remaining records and returns them with the first
+// error that stopped reading, instead of panicking like All
+func (rb *Reader[T]) ReadAll() ([]T, error) {
+	var records []T
+	for record, err := range rb.rows() {
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// AllContext returns an iterator over all records that stops once ctx is
+// done, yielding the context's error. A read already blocked on the input
+// is not interrupted. Any other error stopping iteration is yielded as the
+// final element.
+func (rb *Reader[T]) AllContext(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !rb.nextRow() {
+				break
+			}
+			if !yield(rb.current, nil) {
+				return
+			}
+		}
+		if rb.err != nil {
+			yield(zero, rb.err)
+		}
+	}
+}
+
+// Batches returns an iterator over batches of up to n records. All batches
+// share one backing array that is cleared and reused once the loop body
+// returns, so a batch is only valid until the next one is yielded; copy
+// the records that must outlive it. Only the slice is reused: strings and
+// other values the records point to are allocated per row as with All. An
+// error stopping iteration is yielded after the last batch.
+func (rb *Reader[T]) Batches(n int) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		batch := make([]T, 0, max(n, 1))
+		for rb.nextRow() {
+			batch = append(batch, rb.current)
+			if len(batch) < cap(batch) {
+				continue
+			}
+			if !yield(batch, nil) {
+				return
+			}
+			clear(batch)
+			batch = batch[:0]
+		}
+		if len(batch) > 0 && !yield(batch, nil) {
+			return
+		}
+		if rb.err != nil {
+			yield(nil, rb.err)
+		}
+	}
+}
+
+// rows returns an iterator over all records that yields the error which
+// stopped iteration, if any, as its final element
+func (rb *Reader[T]) rows() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for rb.nextRow() {
+			if !yield(rb.current, nil) {
+				return
 			}
 		}
+		if rb.err != nil {
+			var zero T
+			yield(zero, rb.err)
+		}
 	}
 }
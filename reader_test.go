@@ -1,8 +1,14 @@
 package rowboat_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -166,3 +172,772 @@ func TestCustomUnmarshaler(t *testing.T) {
 		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
 	}
 }
+
+func TestConverterResultTypes(t *testing.T) {
+	type Row struct {
+		Code  string `csv:"code"`
+		Small int8   `csv:"small"`
+		Whole int    `csv:"whole"`
+	}
+	tests := []struct {
+		field  string
+		result any
+		ok     bool
+	}{
+		{"code", 65, false},          // not a rune
+		{"small", int64(300), false}, // overflow
+		{"small", -1.0, true},        // exact float
+		{"whole", 1.5, false},        // truncation
+		{"whole", uint8(7), true},    // widening
+		{"whole", uint64(1 << 63), false},
+	}
+	for _, tt := range tests {
+		rb := mustReader[Row](t, "code,small,whole\nA,1,1\n")
+		if err := rb.RegisterConverter(tt.field, func(string) (any, error) { return tt.result, nil }); err != nil {
+			t.Fatalf("Failed to register converter: %v", err)
+		}
+		if _, err := rb.ReadAll(); (err == nil) != tt.ok {
+			t.Errorf("Converting %T %v for %s: expected success %v, got %v", tt.result, tt.result, tt.field, tt.ok, err)
+		}
+	}
+}
+
+func TestRegisterConverter(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,ALICE@EXAMPLE.COM,thirty
+Bob,BOB@EXAMPLE.COM,25`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+
+	words := map[string]int{"thirty": 30}
+	if err := rb.RegisterConverter("Age", func(s string) (any, error) {
+		if n, ok := words[s]; ok {
+			return n, nil
+		}
+		return strconv.Atoi(s)
+	}); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	if err := rb.RegisterConverter("Email", func(s string) (any, error) {
+		return strings.ToLower(s), nil
+	}); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	if err := rb.RegisterConverter("Missing", func(s string) (any, error) { return s, nil }); err == nil {
+		t.Errorf("Expected error registering converter for unknown field")
+	}
+
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	results := slices.Collect(rb.All())
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestRowFilter(t *testing.T) {
+	// Rows of type "X" carry a different layout and would fail to decode
+	csvData := `Type,Name,Email,Age
+D,Alice,alice@example.com,30
+X,not a person,,n/a
+D,Bob,bob@example.com,25`
+
+	type TypedPerson struct {
+		Type  string `csv:"Type"`
+		Name  string `csv:"Name"`
+		Email string `csv:"Email"`
+		Age   int    `csv:"Age"`
+	}
+
+	rb, err := rowboat.NewReader[TypedPerson](strings.NewReader(csvData), rowboat.WithRowFilter(func(raw []string) bool {
+		return raw[0] == "D"
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+
+	expected := []TypedPerson{
+		{Type: "D", Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Type: "D", Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	results := slices.Collect(rb.All())
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestRawTag(t *testing.T) {
+	csvData := "id,payload,signature\n1,  0042.50 ,\"AbC=\"\n"
+
+	type Signed struct {
+		ID        int    `csv:"id"`
+		Payload   string `csv:"payload,raw"`
+		Signature []byte `csv:"signature,raw"`
+	}
+
+	// Raw fields ignore value maps and converters
+	rb, err := rowboat.NewReader[Signed](strings.NewReader(csvData),
+		rowboat.WithValueMap("payload", map[string]string{"  0042.50 ": "changed"}),
+		rowboat.WithConverter("signature", func(string) (any, error) { return []byte("changed"), nil }))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	results := slices.Collect(rb.All())
+	expected := []Signed{{ID: 1, Payload: "  0042.50 ", Signature: []byte("AbC=")}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Signed](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(results)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	// Leading spaces are quoted so the cell reads back unchanged
+	if want := "id,payload,signature\n1,\"  0042.50 \",AbC=\n"; buf.String() != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, buf.String())
+	}
+
+	type Invalid struct {
+		Amount float64 `csv:"amount,raw"`
+	}
+	if _, err := rowboat.NewWriter[Invalid](&buf); err == nil {
+		t.Errorf("Expected error for raw tag on a float field")
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	// Errors are returned along with the records read before them
+	rb, err = rowboat.NewReader[Person](strings.NewReader(csvData + "\nCharlie,charlie@example.com,old"))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	results, err = rb.ReadAll()
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 4 {
+		t.Fatalf("Expected RowError on line 4, got %v", err)
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestLazyReader(t *testing.T) {
+	// Constructing the reader must not block on the empty pipe
+	pr, pw := io.Pipe()
+	rb, err := rowboat.NewLazyReader[Person](pr)
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+
+	go func() {
+		io.WriteString(pw, "Name,Email,Age\nAlice,alice@example.com,30\n")
+		pw.Close()
+	}()
+
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	// Header errors surface from Init and from reading
+	rb, err = rowboat.NewLazyReader[Person](strings.NewReader(""))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	if err := rb.Init(); err != io.EOF {
+		t.Fatalf("Expected EOF from Init, got %v", err)
+	}
+	if _, err := rb.ReadAll(); err != io.EOF {
+		t.Errorf("Expected EOF from ReadAll, got %v", err)
+	}
+}
+
+func TestAllContext(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25
+Charlie,charlie@example.com,35`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var names []string
+	var lastErr error
+	for person, err := range rb.AllContext(ctx) {
+		if err != nil {
+			lastErr = err
+			break
+		}
+		names = append(names, person.Name)
+		if person.Name == "Bob" {
+			cancel()
+		}
+	}
+	if !errors.Is(lastErr, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", lastErr)
+	}
+	if want := []string{"Alice", "Bob"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected: %v\nGot: %v", want, names)
+	}
+}
+
+func TestAllWithRaw(t *testing.T) {
+	csvData := `Name,Email,Age
+ Alice ,alice@example.com,030
+Bob,bob@example.com,25`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithTrimSpace())
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+
+	var people []Person
+	var raws [][]string
+	for person, raw := range rb.AllWithRaw() {
+		people = append(people, person)
+		raws = append(raws, raw)
+	}
+	expectedPeople := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+	if !reflect.DeepEqual(people, expectedPeople) {
+		t.Errorf("Expected: %+v\nGot: %+v", expectedPeople, people)
+	}
+	expectedRaws := [][]string{
+		{" Alice ", "alice@example.com", "030"},
+		{"Bob", "bob@example.com", "25"},
+	}
+	if !reflect.DeepEqual(raws, expectedRaws) {
+		t.Errorf("Expected: %+v\nGot: %+v", expectedRaws, raws)
+	}
+}
+
+func TestRowErrorRaw(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,thirty\nBob\nCarol,carol@example.com,40\n"
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithMaxErrors(2))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	var raws [][]string
+	for _, raw := range rb.AllWithRaw() {
+		raws = append(raws, raw)
+	}
+	for _, err := range rb.Errors() {
+		var rowErr *rowboat.RowError
+		if !errors.As(err, &rowErr) {
+			t.Fatalf("Expected a RowError, got %v", err)
+		}
+		raws = append(raws, rowErr.Raw)
+	}
+	expected := [][]string{
+		{"Carol", "carol@example.com", "40"},
+		{"Alice", "alice@example.com", "thirty"},
+		{"Bob"},
+	}
+	if !reflect.DeepEqual(raws, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, raws)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25
+Charlie,charlie@example.com,35`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+
+	var sizes []int
+	var names []string
+	var first *Person
+	for batch, err := range rb.Batches(2) {
+		if err != nil {
+			t.Fatalf("Failed to read batch: %v", err)
+		}
+		if first == nil {
+			first = &batch[0]
+		} else if &batch[0] != first {
+			t.Errorf("Expected batches to share backing storage")
+		}
+		sizes = append(sizes, len(batch))
+		for _, p := range batch {
+			names = append(names, p.Name)
+		}
+	}
+	if want := []int{2, 1}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("Expected batch sizes %v, got %v", want, sizes)
+	}
+	if want := []string{"Alice", "Bob", "Charlie"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected: %v\nGot: %v", want, names)
+	}
+}
+
+func TestReaderAt(t *testing.T) {
+	csvData := "Name,Email,Age\r\nAlice,alice@example.com,30\r\nBob,bob@example.com,25\r\nCharlie,charlie@example.com,35\r\n"
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	var offset int64
+	for p, err := range rb.AllContext(context.Background()) {
+		if err != nil {
+			t.Fatalf("Failed to read record: %v", err)
+		}
+		if p.Name == "Alice" {
+			offset = rb.Offset()
+			if rb.Line() != 2 {
+				t.Errorf("Expected line 2, got %d", rb.Line())
+			}
+			break
+		}
+	}
+	if want := int64(len("Name,Email,Age\r\nAlice,alice@example.com,30\r\n")); offset != want {
+		t.Fatalf("Expected offset %d, got %d", want, offset)
+	}
+
+	resumed, err := rowboat.NewReaderAt[Person](strings.NewReader(csvData), offset, rb.Headers())
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	records, err := resumed.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+		{Name: "Charlie", Email: "charlie@example.com", Age: 35},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, records)
+	}
+	if resumed.Offset() != int64(len(csvData)) {
+		t.Errorf("Expected offset %d, got %d", len(csvData), resumed.Offset())
+	}
+}
+
+func TestReaderAtSchemaHash(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithSchemaHash())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice([]Person{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 25}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	rb, err := rowboat.NewReader[Person](bytes.NewReader(buf.Bytes()), rowboat.WithSchemaHash())
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	for range rb.All() {
+		break
+	}
+	resumed, err := rowboat.NewReaderAt[Person](bytes.NewReader(buf.Bytes()), rb.Offset(), rb.Headers())
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	records, err := resumed.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if expected := []Person{{Name: "Bob", Age: 25}}; !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, records)
+	}
+}
+
+func TestFromCSVReader(t *testing.T) {
+	csvData := `# exported people
+Name;Email;Age
+Alice;alice@example.com;30
+Bob;bob@example.com;25`
+
+	cr := csv.NewReader(strings.NewReader(csvData))
+	cr.Comma = ';'
+	cr.Comment = '#'
+	cr.ReuseRecord = true
+	rb, err := rowboat.FromCSVReader[Person](cr)
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	records, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, records)
+	}
+	if want := []string{"Name", "Email", "Age"}; !reflect.DeepEqual(rb.Headers(), want) {
+		t.Errorf("Expected headers %v, got %v", want, rb.Headers())
+	}
+}
+
+func TestReaderAllocations(t *testing.T) {
+	const rows = 1000
+	csvData := "Name,Email,Age\n" + strings.Repeat("Alice,alice@example.com,30\n", rows)
+
+	allocs := testing.AllocsPerRun(5, func() {
+		rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("Failed to create RowBoat: %v", err)
+		}
+		for range rb.All() {
+		}
+	})
+	// Each row allocates only the string holding its cells
+	if allocs > rows*1.1 {
+		t.Errorf("Expected about %d allocations, got %v", rows, allocs)
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	csvData := "Name,Email,Age\n" + strings.Repeat("Alice,alice@example.com,30\n", 1000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(csvData)))
+	for i := 0; i < b.N; i++ {
+		rb, err := rowboat.NewReader[Person](strings.NewReader(csvData))
+		if err != nil {
+			b.Fatalf("Failed to create RowBoat: %v", err)
+		}
+		for range rb.All() {
+		}
+	}
+}
+
+// Timestamps is embedded by several record types
+type Timestamps struct {
+	CreatedAt time.Time `csv:"created_at"`
+	UpdatedAt time.Time `csv:"updated_at"`
+}
+
+type Audit struct {
+	Version int `csv:"version"`
+}
+
+type Account struct {
+	Timestamps
+	Audit `csv:"-"`
+	ID    int    `csv:"id"`
+	Email string `csv:"email"`
+}
+
+func TestEmbeddedStruct(t *testing.T) {
+	csvData := `created_at,updated_at,id,email,version
+2024-01-02T03:04:05Z,2024-02-03T04:05:06Z,1,alice@example.com,7
+`
+	rb, err := rowboat.NewReader[Account](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Account{{
+		Timestamps: Timestamps{
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+		},
+		ID:    1,
+		Email: "alice@example.com",
+	}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func TestEmbeddedStructShadowing(t *testing.T) {
+	type Base struct {
+		ID   int    `csv:"id"`
+		Name string `csv:"name"`
+	}
+	type Derived struct {
+		Base
+		Name string `csv:"name"`
+	}
+	rb, err := rowboat.NewReader[Derived](strings.NewReader("id,name\n1,outer\n"))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Derived{{Base: Base{ID: 1}, Name: "outer"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	type Other struct {
+		Name string `csv:"name"`
+	}
+	type Ambiguous struct {
+		Base
+		Other
+	}
+	if _, err := rowboat.NewReader[Ambiguous](strings.NewReader("id,name\n")); err == nil {
+		t.Error("Expected an error for an ambiguous promoted column")
+	}
+}
+
+func TestEmbeddedPointerAndUnexported(t *testing.T) {
+	type Base struct {
+		ID int `csv:"id"`
+	}
+	type meta struct {
+		Source string `csv:"source"`
+	}
+	type Row struct {
+		*Base
+		meta
+		Name  string `csv:"name"`
+		notes string
+		cache map[string]int
+	}
+	rb, err := rowboat.NewReader[Row](strings.NewReader("id,source,name\n1,crm,Alice\n2,erp,Bob\n"))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Row{
+		{Base: &Base{ID: 1}, meta: meta{Source: "crm"}, Name: "Alice"},
+		{Base: &Base{ID: 2}, meta: meta{Source: "erp"}, Name: "Bob"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	// A nil embedded pointer is written as empty cells
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Row](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	rows := []Row{expected[0], {Name: "Carol", notes: "ignored"}}
+	if err := writer.WriteSlice(rows); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "1,crm,Alice\n,,Carol\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestUnsupportedFields(t *testing.T) {
+	type Tagged struct {
+		Name string            `csv:"name"`
+		Tags []string          `csv:"tags"`
+		Meta map[string]string `csv:"meta"`
+	}
+	// Fields are checked on the first read or write, after converters
+	// could be registered
+	_, err := mustReader[Tagged](t, "name,tags,meta\nAlice,a,b\n").ReadAll()
+	if !errors.Is(err, rowboat.ErrUnsupportedField) {
+		t.Fatalf("Expected ErrUnsupportedField, got %v", err)
+	}
+	for _, want := range []string{"Tags ([]string)", "Meta (map[string]string)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected the error to list %s, got %q", want, err.Error())
+		}
+	}
+	unmapped, err := rowboat.NewWriter[Tagged](&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := unmapped.Write(Tagged{}); !errors.Is(err, rowboat.ErrUnsupportedField) {
+		t.Errorf("Expected ErrUnsupportedField, got %v", err)
+	}
+
+	// Converters make the fields mappable
+	split := func(s string) (any, error) { return strings.Split(s, "|"), nil }
+	skip := func(string) (any, error) { return nil, nil }
+	rb := mustReader[Tagged](t, "name,tags,meta\nAlice,a|b,x\n", rowboat.WithConverter("tags", split))
+	if err := rb.RegisterConverter("meta", skip); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Tagged{{Name: "Alice", Tags: []string{"a", "b"}}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	join := func(v any) (string, error) { return strings.Join(v.([]string), "|"), nil }
+	empty := func(any) (string, error) { return "", nil }
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Tagged](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.RegisterConverter("Tags", join); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	if err := writer.RegisterConverter("meta", empty); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	if err := writer.WriteSlice(expected); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "Alice,a|b,\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestPointerRecords(t *testing.T) {
+	csvData := "Name,Email,Age\nAlice,alice@example.com,30\nBob,bob@example.com,25\n"
+	rb, err := rowboat.NewReader[*Person](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []*Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[*Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(got); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if buf.String() != csvData {
+		t.Errorf("Expected: %q\nGot: %q", csvData, buf.String())
+	}
+	if err := writer.Write(nil); err == nil {
+		t.Error("Expected an error writing a nil record")
+	}
+}
+
+func TestTrimSpace(t *testing.T) {
+	type Padded struct {
+		Code  string  `csv:"code"`
+		Qty   int     `csv:"qty,trim"`
+		Price float64 `csv:"price,trim"`
+		Note  string  `csv:"note,raw"`
+	}
+	csvData := "code,qty,price,note\n A1 ,  42, 9.50 , keep \n"
+
+	rb, err := rowboat.NewReader[Padded](strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Padded{{Code: " A1 ", Qty: 42, Price: 9.5, Note: " keep "}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	rb, err = rowboat.NewReader[Padded](strings.NewReader(csvData), rowboat.WithTrimSpace())
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err = rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected = []Padded{{Code: "A1", Qty: 42, Price: 9.5, Note: " keep "}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func TestPositional(t *testing.T) {
+	type Order struct {
+		ID    int     `csv:"id,index=0"`
+		Total float64 `csv:"total,index=2"`
+		Item  string  `csv:"item,index=1"`
+	}
+	csvData := "Bestellnummer,Artikel,Summe\n7,Lampe,19.99\n"
+
+	rb, err := rowboat.NewReader[Order](strings.NewReader(csvData), rowboat.WithPositional())
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Order{{ID: 7, Total: 19.99, Item: "Lampe"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if want := []string{"Bestellnummer", "Artikel", "Summe"}; !reflect.DeepEqual(rb.Headers(), want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, rb.Headers())
+	}
+}
@@ -0,0 +1,346 @@
+package rowboat
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaField is a column of a Schema
+type SchemaField struct {
+	Column
+	Required bool // cells may not be empty
+}
+
+// FieldOption configures a field added to a Schema
+type FieldOption func(*SchemaField)
+
+// Required rejects empty cells in the field when reading and nil values
+// when writing
+func Required() FieldOption {
+	return func(f *SchemaField) {
+		f.Required = true
+	}
+}
+
+// Layout sets the time layout of a Time field, or the locale of a Float
+// field such as "de". The default is RFC 3339 for times and plain numbers
+// for floats.
+func Layout(layout string) FieldOption {
+	return func(f *SchemaField) {
+		f.Layout = layout
+	}
+}
+
+// Schema describes the columns of a file at runtime, for files whose layout
+// is not known at compile time. Schemas are immutable: Field returns an
+// extended copy.
+//
+//	schema := rowboat.NewSchema().
+//		Field("Name", rowboat.String, rowboat.Required()).
+//		Field("Age", rowboat.Int)
+type Schema struct {
+	fields []SchemaField
+}
+
+// NewSchema returns an empty Schema
+func NewSchema() Schema {
+	return Schema{}
+}
+
+// Field returns a copy of the schema with a column appended
+func (s Schema) Field(name string, t ColumnType, opts ...FieldOption) Schema {
+	f := SchemaField{Column: Column{Name: name, Type: t}}
+	if t == Time {
+		f.Layout = time.RFC3339
+	}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	s.fields = append(slices.Clip(s.fields), f)
+	return s
+}
+
+// Fields returns the fields of the schema in column order
+func (s Schema) Fields() []SchemaField {
+	return slices.Clone(s.fields)
+}
+
+// Columns returns the columns of the schema in order
+func (s Schema) Columns() []Column {
+	columns := make([]Column, len(s.fields))
+	for i, f := range s.fields {
+		columns[i] = f.Column
+	}
+	return columns
+}
+
+// validate checks that the schema's column names are set and unique
+func (s Schema) validate() error {
+	if len(s.fields) == 0 {
+		return errors.New("schema has no fields")
+	}
+	seen := make(map[string]bool, len(s.fields))
+	for _, f := range s.fields {
+		if f.Name == "" {
+			return errors.New("schema field has no name")
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate schema field '%s'", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	return nil
+}
+
+// NewSchemaReader creates a DynamicReader decoding a CSV file with the
+// columns of schema. Columns are matched to the header by name and header
+// columns outside the schema are ignored. A missing required column fails
+// with a *HeaderError; missing optional columns read as empty cells.
+func NewSchemaReader(r io.Reader, schema Schema, opts ...Option) (*DynamicReader, error) {
+	if err := schema.validate(); err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	r, err := o.wrapInput(r)
+	if err != nil {
+		return nil, err
+	}
+	dr := &DynamicReader{reader: csv.NewReader(r), columns: schema.Columns()}
+	o.configureReader(dr.reader)
+
+	headers, err := dr.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if o.typeRow {
+		if _, err := dr.reader.Read(); err != nil {
+			return nil, fmt.Errorf("error reading type row: %w", err)
+		}
+	}
+
+	positions := make(map[string]int, len(headers))
+	for i, header := range headers {
+		positions[strings.TrimSpace(header)] = i
+	}
+	var missing []string
+	dr.positions = make([]int, len(schema.fields))
+	dr.required = make([]bool, len(schema.fields))
+	for i, f := range schema.fields {
+		pos, ok := positions[f.Name]
+		if !ok {
+			pos = -1
+			if f.Required {
+				missing = append(missing, f.Name)
+			}
+		}
+		dr.positions[i] = pos
+		dr.required[i] = f.Required
+	}
+	if len(missing) > 0 {
+		return nil, &HeaderError{Missing: missing}
+	}
+	return dr, nil
+}
+
+// DynamicWriter writes maps of values as CSV rows with the columns of a
+// Schema
+type DynamicWriter struct {
+	writer *csv.Writer
+	closer io.Closer
+	opts   *options
+	fields []SchemaField
+}
+
+// NewDynamicWriter creates a writer producing the columns of schema. With
+// WithTypeRow the header is followed by a type row, so the output can be
+// read back with NewDynamicReader.
+func NewDynamicWriter(w io.Writer, schema Schema, opts ...Option) (*DynamicWriter, error) {
+	if err := schema.validate(); err != nil {
+		return nil, err
+	}
+	dw := &DynamicWriter{opts: newOptions(opts), fields: schema.Fields()}
+	if dw.opts.gzip {
+		gz := gzip.NewWriter(w)
+		w, dw.closer = gz, gz
+	}
+	dw.writer = csv.NewWriter(w)
+	dw.opts.configureWriter(dw.writer)
+	return dw, nil
+}
+
+// WriteHeader writes the header row, and the type row under WithTypeRow
+func (dw *DynamicWriter) WriteHeader() error {
+	headers := make([]string, len(dw.fields))
+	types := make([]string, len(dw.fields))
+	for i, f := range dw.fields {
+		headers[i] = f.Name
+		types[i] = f.typeToken()
+	}
+	if err := dw.writer.Write(headers); err != nil {
+		return err
+	}
+	if dw.opts.typeRow {
+		return dw.writer.Write(types)
+	}
+	return nil
+}
+
+// Write writes a row holding the values of row, keyed by column name.
+// Absent keys and nil values are written as empty cells. Values are
+// converted by the column type, and strings are accepted for any column as
+// long as they parse as its type. Records are buffered until Flush or Close.
+func (dw *DynamicWriter) Write(row map[string]any) error {
+	for name := range row {
+		if !slices.ContainsFunc(dw.fields, func(f SchemaField) bool { return f.Name == name }) {
+			return fmt.Errorf("unknown column '%s'", name)
+		}
+	}
+	record := make([]string, len(dw.fields))
+	for i, f := range dw.fields {
+		v := row[f.Name]
+		if v == nil {
+			if f.Required {
+				return fmt.Errorf("column '%s': required value missing", f.Name)
+			}
+			continue
+		}
+		s, err := formatColumnValue(f.Column, v)
+		if err != nil {
+			return fmt.Errorf("column '%s': %w", f.Name, err)
+		}
+		record[i] = s
+	}
+	return dw.writer.Write(record)
+}
+
+// Flush writes buffered rows to the underlying io.Writer
+func (dw *DynamicWriter) Flush() error {
+	dw.writer.Flush()
+	return dw.writer.Error()
+}
+
+// Close flushes buffered rows and finishes the compressed stream under
+// WithGzip. It does not close the underlying io.Writer.
+func (dw *DynamicWriter) Close() error {
+	if err := dw.Flush(); err != nil {
+		return err
+	}
+	if dw.closer != nil {
+		return dw.closer.Close()
+	}
+	return nil
+}
+
+// formatColumnValue converts a Go value into a cell of the column's type
+func formatColumnValue(col Column, v any) (string, error) {
+	if s, ok := v.(string); ok {
+		if _, err := parseColumnValue(col, s); err != nil {
+			return "", err
+		}
+		return s, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch col.Type {
+	case Int:
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(rv.Int(), 10), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(rv.Uint(), 10), nil
+		}
+	case Uint:
+		switch rv.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(rv.Uint(), 10), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if rv.Int() >= 0 {
+				return strconv.FormatInt(rv.Int(), 10), nil
+			}
+		}
+	case Float:
+		switch rv.Kind() {
+		case reflect.Float32, reflect.Float64:
+			s := strconv.FormatFloat(rv.Float(), 'f', -1, rv.Type().Bits())
+			if col.Layout != "" {
+				loc, err := lookupLocale(col.Layout)
+				if err != nil {
+					return "", err
+				}
+				s = loc.formatNumber(s)
+			}
+			return s, nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(rv.Int(), 10), nil
+		}
+	case Bool:
+		if rv.Kind() == reflect.Bool {
+			return strconv.FormatBool(rv.Bool()), nil
+		}
+	case Time:
+		if t, ok := v.(time.Time); ok {
+			return t.Format(col.Layout), nil
+		}
+	default:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("cannot write %T as %s", v, col.Type)
+}
+
+// inferLayouts are the time layouts InferSchema recognizes, in order of
+// preference
+var inferLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	time.DateOnly,
+	"01/02/2006",
+	"1/2/2006",
+	"02.01.2006",
+}
+
+// InferSchema reads the header and up to sampleRows data rows of a CSV file,
+// or all of them when sampleRows is not positive, and returns a Schema
+// giving each column the most specific type parsing all of its non-empty
+// cells: int, float, bool, time or string. Times are recognized in RFC 3339,
+// ISO date and time, ISO date, US and European date layouts, after the
+// layout set by WithTimeLayout if any.
+func InferSchema(r io.Reader, sampleRows int, opts ...Option) (Schema, error) {
+	o := newOptions(opts)
+	r, err := o.wrapInput(r)
+	if err != nil {
+		return Schema{}, err
+	}
+	reader := csv.NewReader(r)
+	o.configureReader(reader)
+
+	headers, err := reader.Read()
+	if err != nil {
+		return Schema{}, err
+	}
+	columns := make([]Column, len(headers))
+	for i, header := range headers {
+		columns[i].Name = strings.TrimSpace(header)
+	}
+
+	layouts := inferLayouts
+	if o.timeLayout != "" {
+		layouts = append([]string{o.timeLayout}, inferLayouts...)
+	}
+	if err := inferColumns(reader, columns, sampleRows, layouts); err != nil {
+		return Schema{}, err
+	}
+
+	schema := NewSchema()
+	for _, col := range columns {
+		schema = schema.Field(col.Name, col.Type, Layout(col.Layout))
+	}
+	return schema, nil
+}
@@ -0,0 +1,170 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestSchemaReader(t *testing.T) {
+	csvData := `Age,Name,Notes,Joined
+30,Alice,x,2024-01-02
+,Bob,y,2024-03-04`
+
+	schema := rowboat.NewSchema().
+		Field("Name", rowboat.String, rowboat.Required()).
+		Field("Age", rowboat.Int).
+		Field("Joined", rowboat.Time, rowboat.Layout(time.DateOnly))
+
+	dr, err := rowboat.NewSchemaReader(strings.NewReader(csvData), schema)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	var rows []map[string]any
+	for row, err := range dr.All() {
+		if err != nil {
+			t.Fatalf("Failed to read row: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	expected := []map[string]any{
+		{"Name": "Alice", "Age": int64(30), "Joined": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Name": "Bob", "Age": nil, "Joined": time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, rows)
+	}
+}
+
+func TestSchemaReaderRequired(t *testing.T) {
+	schema := rowboat.NewSchema().
+		Field("Name", rowboat.String, rowboat.Required()).
+		Field("Email", rowboat.String, rowboat.Required())
+
+	_, err := rowboat.NewSchemaReader(strings.NewReader("Name\nAlice\n"), schema)
+	var headerErr *rowboat.HeaderError
+	if !errors.As(err, &headerErr) || !reflect.DeepEqual(headerErr.Missing, []string{"Email"}) {
+		t.Fatalf("Expected missing Email column, got %v", err)
+	}
+
+	dr, err := rowboat.NewSchemaReader(strings.NewReader("Name,Email\n,a@example.com\n"), schema)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	for _, err := range dr.All() {
+		var rowErr *rowboat.RowError
+		if !errors.As(err, &rowErr) || rowErr.Column != "Name" {
+			t.Errorf("Expected RowError for Name, got %v", err)
+		}
+	}
+}
+
+func TestDynamicWriter(t *testing.T) {
+	schema := rowboat.NewSchema().
+		Field("Name", rowboat.String, rowboat.Required()).
+		Field("Age", rowboat.Int).
+		Field("Score", rowboat.Float).
+		Field("Joined", rowboat.Time, rowboat.Layout(time.DateOnly))
+
+	var buf bytes.Buffer
+	dw, err := rowboat.NewDynamicWriter(&buf, schema, rowboat.WithTypeRow())
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	if err := dw.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	rows := []map[string]any{
+		{"Name": "Alice", "Age": 30, "Score": 9.5, "Joined": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Name": "Bob", "Age": "25"},
+	}
+	for _, row := range rows {
+		if err := dw.Write(row); err != nil {
+			t.Fatalf("Failed to write row: %v", err)
+		}
+	}
+	if err := dw.Close(); err != nil {
+		t.Fatalf("Failed to close writer: %v", err)
+	}
+
+	expected := "Name,Age,Score,Joined\nstring,int,float,time:2006-01-02\nAlice,30,9.5,2024-01-02\nBob,25,,\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+
+	// The output reads back with NewDynamicReader
+	dr, err := rowboat.NewDynamicReader(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	if !reflect.DeepEqual(dr.Columns(), schema.Columns()) {
+		t.Errorf("Expected: %+v\nGot: %+v", schema.Columns(), dr.Columns())
+	}
+}
+
+func TestDynamicWriterErrors(t *testing.T) {
+	schema := rowboat.NewSchema().
+		Field("Name", rowboat.String, rowboat.Required()).
+		Field("Age", rowboat.Int)
+
+	dw, err := rowboat.NewDynamicWriter(&bytes.Buffer{}, schema)
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	for _, row := range []map[string]any{
+		{"Age": 30},
+		{"Name": "Alice", "Age": "thirty"},
+		{"Name": "Alice", "Age": 1.5},
+		{"Name": "Alice", "Email": "alice@example.com"},
+	} {
+		if err := dw.Write(row); err == nil {
+			t.Errorf("Expected error writing %v", row)
+		}
+	}
+
+	if _, err := rowboat.NewDynamicWriter(&bytes.Buffer{}, schema.Field("Name", rowboat.String)); err == nil {
+		t.Errorf("Expected error for duplicate schema field")
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	csvData := `id,price,active,joined,seen,name
+1,9.5,true,2024-01-02,2024-01-02 10:00:00,Alice
+2,10,false,2024-02-03,,Bob
+3,,TRUE,2024-03-04,2024-05-06 07:08:09,
+x,1,1,not a date,,Dave`
+
+	schema, err := rowboat.InferSchema(strings.NewReader(csvData), 3)
+	if err != nil {
+		t.Fatalf("Failed to infer schema: %v", err)
+	}
+	expected := []rowboat.Column{
+		{Name: "id", Type: rowboat.Int},
+		{Name: "price", Type: rowboat.Float},
+		{Name: "active", Type: rowboat.Bool},
+		{Name: "joined", Type: rowboat.Time, Layout: time.DateOnly},
+		{Name: "seen", Type: rowboat.Time, Layout: time.DateTime},
+		{Name: "name", Type: rowboat.String},
+	}
+	if got := schema.Columns(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+
+	// Sampling every row sees the cells that break the inferred types
+	schema, err = rowboat.InferSchema(strings.NewReader(csvData), 0)
+	if err != nil {
+		t.Fatalf("Failed to infer schema: %v", err)
+	}
+	var types []rowboat.ColumnType
+	for _, col := range schema.Columns() {
+		types = append(types, col.Type)
+	}
+	if want := []rowboat.ColumnType{rowboat.String, rowboat.Float, rowboat.Bool, rowboat.String, rowboat.Time, rowboat.String}; !reflect.DeepEqual(types, want) {
+		t.Errorf("Expected: %v\nGot: %v", want, types)
+	}
+}
@@ -0,0 +1,183 @@
+package rowboat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// WithSectionSeparator sets how Sections recognizes the lines separating
+// sections, such as sentinel rows like "#DATA". fn receives each raw line
+// without its line ending. By default, blank lines and lines holding only
+// delimiters separate sections.
+func WithSectionSeparator(fn func(line string) bool) Option {
+	return func(o *options) {
+		o.sectionSeparator = fn
+	}
+}
+
+// Sections reads files bundling several tables, each a header row followed
+// by data rows, separated by blank lines or sentinel rows. Sections are
+// visited one at a time with Next and decoded with ReadSection. Separator
+// lines inside quoted fields do not end a section.
+type Sections struct {
+	br        *bufio.Reader
+	opts      []Option
+	separator func(line string) bool
+	delimiter rune
+	current   *sectionInput
+	headers   []string // peeked header of the current section
+	label     string   // separator line preceding the current section
+	nextLabel string   // last non-blank separator line since then
+	done      bool     // the input is exhausted
+	err       error
+}
+
+// NewSections creates a Sections reading r. Call Next to advance to the
+// first section.
+func NewSections(r io.Reader, opts ...Option) (*Sections, error) {
+	o := newOptions(opts)
+	r, err := o.wrapInput(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &Sections{br: bufio.NewReader(r), opts: opts, separator: o.sectionSeparator, delimiter: o.delimiter}
+	if s.delimiter == 0 {
+		s.delimiter = ','
+	}
+	if s.separator == nil {
+		s.separator = func(line string) bool {
+			return strings.TrimFunc(line, func(r rune) bool { return unicode.IsSpace(r) || r == s.delimiter }) == ""
+		}
+	}
+	return s, nil
+}
+
+// Next advances to the next section, skipping whatever remains of the
+// current one. It returns false at the end of the input or on error.
+func (s *Sections) Next() bool {
+	if s.current != nil {
+		if _, err := io.Copy(io.Discard, s.current); err != nil {
+			s.err = err
+		}
+		s.current, s.headers = nil, nil
+	}
+	for !s.done && s.err == nil {
+		in := &sectionInput{sections: s}
+		if in.next() {
+			s.current = in
+			s.label, s.nextLabel = s.nextLabel, ""
+			return true
+		}
+	}
+	return false
+}
+
+// Headers returns the header row of the current section without consuming
+// it, so that the struct type can be chosen before calling ReadSection
+func (s *Sections) Headers() ([]string, error) {
+	if s.current == nil {
+		return nil, errors.New("no current section")
+	}
+	if s.headers == nil {
+		in := s.current
+		for in.quoted && in.next() {
+		}
+		cr := csv.NewReader(bytes.NewReader(in.pending))
+		cr.Comma = s.delimiter
+		headers, err := cr.Read()
+		if err != nil {
+			return nil, err
+		}
+		s.headers = headers
+	}
+	return slices.Clone(s.headers), nil
+}
+
+// Separator returns the last non-blank separator line preceding the current
+// section, such as a sentinel row naming the table, or "" if there was none
+func (s *Sections) Separator() string {
+	return s.label
+}
+
+// Err returns the first error encountered while reading the input
+func (s *Sections) Err() error {
+	return s.err
+}
+
+// ReadSection creates a Reader decoding the current section into T. The
+// first row of the section is read as the header. Options passed here
+// apply after the Sections' own; options acting on the raw byte stream,
+// such as decompression, are applied once by NewSections.
+func ReadSection[T any](s *Sections, opts ...Option) (*Reader[T], error) {
+	if s.current == nil {
+		return nil, errors.New("no current section")
+	}
+	rb, err := newReader[T](append(slices.Clip(s.opts), opts...))
+	if err != nil {
+		return nil, err
+	}
+	csvReader := csv.NewReader(s.current)
+	rb.opts.configureReader(csvReader)
+	csvReader.ReuseRecord = true
+	rb.reader = csvReader
+
+	if err := rb.readHeader(); err != nil {
+		return nil, err
+	}
+	return rb, nil
+}
+
+// sectionInput reads the lines of one section
+type sectionInput struct {
+	sections *Sections
+	pending  []byte // lines read but not yet returned
+	quoted   bool   // the last line ends inside a quoted field
+	err      error  // returned once pending is drained
+}
+
+// next appends the next line of the section to pending and reports whether
+// there was one
+func (in *sectionInput) next() bool {
+	if in.err != nil {
+		return false
+	}
+	s := in.sections
+	line, err := s.br.ReadString('\n')
+	if err != nil {
+		s.done = true
+		if err != io.EOF {
+			s.err = err
+		}
+		in.err = err
+	}
+	if line == "" {
+		return false
+	}
+	if !in.quoted && s.separator(strings.TrimRight(line, "\r\n")) {
+		if label := strings.TrimRight(line, "\r\n"); strings.TrimSpace(label) != "" {
+			s.nextLabel = label
+		}
+		in.err = io.EOF
+		return false
+	}
+	if strings.Count(line, `"`)%2 == 1 {
+		in.quoted = !in.quoted
+	}
+	in.pending = append(in.pending, line...)
+	return true
+}
+
+func (in *sectionInput) Read(p []byte) (int, error) {
+	if len(in.pending) == 0 && !in.next() {
+		return 0, in.err
+	}
+	n := copy(p, in.pending)
+	in.pending = in.pending[n:]
+	return n, nil
+}
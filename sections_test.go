@@ -0,0 +1,113 @@
+package rowboat_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type InstrumentInfo struct {
+	Key   string `csv:"key"`
+	Value string `csv:"value"`
+}
+
+type Sample struct {
+	Time  int     `csv:"t"`
+	Value float64 `csv:"value"`
+	Note  string  `csv:"note"`
+}
+
+func TestSections(t *testing.T) {
+	csvData := "key,value\nmodel,X100\nserial,42\n\n,,\nt,value,note\n1,0.5,\"first\n\nline\"\n2,0.75,\n\n"
+
+	sections, err := rowboat.NewSections(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("Failed to create Sections: %v", err)
+	}
+
+	if !sections.Next() {
+		t.Fatalf("Expected a first section: %v", sections.Err())
+	}
+	info, err := rowboat.ReadSection[InstrumentInfo](sections)
+	if err != nil {
+		t.Fatalf("Failed to read section: %v", err)
+	}
+	gotInfo, err := info.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expectedInfo := []InstrumentInfo{{"model", "X100"}, {"serial", "42"}}
+	if !reflect.DeepEqual(gotInfo, expectedInfo) {
+		t.Errorf("Expected: %+v\nGot: %+v", expectedInfo, gotInfo)
+	}
+
+	if !sections.Next() {
+		t.Fatalf("Expected a second section: %v", sections.Err())
+	}
+	headers, err := sections.Headers()
+	if err != nil {
+		t.Fatalf("Failed to peek headers: %v", err)
+	}
+	if want := []string{"t", "value", "note"}; !slices.Equal(headers, want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, headers)
+	}
+	data, err := rowboat.ReadSection[Sample](sections)
+	if err != nil {
+		t.Fatalf("Failed to read section: %v", err)
+	}
+	gotData, err := data.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expectedData := []Sample{{1, 0.5, "first\n\nline"}, {2, 0.75, ""}}
+	if !reflect.DeepEqual(gotData, expectedData) {
+		t.Errorf("Expected: %+v\nGot: %+v", expectedData, gotData)
+	}
+
+	if sections.Next() {
+		t.Errorf("Expected no more sections")
+	}
+	if err := sections.Err(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSectionSentinels(t *testing.T) {
+	csvData := "#META\nkey,value\nmodel,X100\n#DATA\nt,value,note\n1,0.5,a\n"
+	sections, err := rowboat.NewSections(strings.NewReader(csvData),
+		rowboat.WithSectionSeparator(func(line string) bool { return strings.HasPrefix(line, "#") }))
+	if err != nil {
+		t.Fatalf("Failed to create Sections: %v", err)
+	}
+
+	var labels []string
+	var rows int
+	for sections.Next() {
+		labels = append(labels, sections.Separator())
+		// Sections can be skipped without reading them
+		if sections.Separator() != "#DATA" {
+			continue
+		}
+		rb, err := rowboat.ReadSection[Sample](sections)
+		if err != nil {
+			t.Fatalf("Failed to read section: %v", err)
+		}
+		records, err := rb.ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read records: %v", err)
+		}
+		rows += len(records)
+	}
+	if err := sections.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"#META", "#DATA"}; !slices.Equal(labels, want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, labels)
+	}
+	if rows != 1 {
+		t.Errorf("Expected: %+v\nGot: %+v", 1, rows)
+	}
+}
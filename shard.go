@@ -0,0 +1,159 @@
+package rowboat
+
+import (
+	"errors"
+	"io"
+	"slices"
+)
+
+// WithMaxShardRows makes a ShardedWriter start a new part file once a part
+// holds n records
+func WithMaxShardRows(n int) Option {
+	return func(o *options) {
+		o.shardRows = n
+	}
+}
+
+// WithMaxShardBytes makes a ShardedWriter start a new part file once a part
+// holds about n bytes of records
+func WithMaxShardBytes(n int64) Option {
+	return func(o *options) {
+		o.shardBytes = n
+	}
+}
+
+// ShardedWriter splits records over part files, each starting with its own
+// header. Records are grouped by a key, e.g. one file per region, and a
+// group rolls over to a new part once it reaches WithMaxShardRows or
+// WithMaxShardBytes. Like a Writer, it is not safe for concurrent use.
+type ShardedWriter[T any] struct {
+	create func(key string, part int) (io.WriteCloser, error)
+	key    func(T) string
+	opts   []Option
+	o      *options
+	shards map[string]*shard[T]
+	keys   []string // shard keys in the order they were first written
+}
+
+// shard is the open part file of a key
+type shard[T any] struct {
+	part   int // number of the last part opened
+	writer *Writer[T]
+	file   io.WriteCloser
+}
+
+// NewShardedWriter creates a ShardedWriter. create opens the part file
+// numbered part, counting from zero, for records with the given key. key
+// returns the shard key of a record; a nil key puts all records in one
+// group with an empty key. opts configure the Writer of each part.
+func NewShardedWriter[T any](create func(key string, part int) (io.WriteCloser, error), key func(T) string, opts ...Option) (*ShardedWriter[T], error) {
+	if create == nil {
+		return nil, errors.New("sharded writer requires a create function")
+	}
+	if key == nil {
+		key = func(T) string { return "" }
+	}
+	sw := &ShardedWriter[T]{
+		create: create,
+		key:    key,
+		opts:   opts,
+		o:      newTypeOptions[T](opts),
+		shards: make(map[string]*shard[T]),
+	}
+	// Fail on invalid structs before any file is created
+	if _, err := NewWriter[T](io.Discard, opts...); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// Write writes a record to the part file of its key, closing a full part
+// and opening the next one as needed
+func (sw *ShardedWriter[T]) Write(record T) error {
+	k := sw.key(record)
+	s, ok := sw.shards[k]
+	if !ok {
+		s = &shard[T]{part: -1}
+		sw.shards[k] = s
+		sw.keys = append(sw.keys, k)
+	} else if s.full(sw.o) {
+		if err := s.close(); err != nil {
+			return err
+		}
+	}
+	if s.writer == nil {
+		if err := sw.open(k, s); err != nil {
+			return err
+		}
+	}
+	return s.writer.Write(record)
+}
+
+// WriteSlice writes records and closes all part files
+func (sw *ShardedWriter[T]) WriteSlice(records []T) error {
+	for _, record := range records {
+		if err := sw.Write(record); err != nil {
+			return err
+		}
+	}
+	return sw.Close()
+}
+
+// Keys returns the shard keys written so far, in the order they first
+// appeared
+func (sw *ShardedWriter[T]) Keys() []string {
+	return slices.Clone(sw.keys)
+}
+
+// Close closes the open part file of every key and returns the first error
+func (sw *ShardedWriter[T]) Close() error {
+	var err error
+	for _, k := range sw.keys {
+		if closeErr := sw.shards[k].close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// open creates the next part file of key k and writes its header
+func (sw *ShardedWriter[T]) open(k string, s *shard[T]) error {
+	file, err := sw.create(k, s.part+1)
+	if err != nil {
+		return err
+	}
+	writer, err := NewWriter[T](file, sw.opts...)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.WriteHeader(); err != nil {
+		file.Close()
+		return err
+	}
+	s.file, s.writer = file, writer
+	s.part++
+	return nil
+}
+
+// full reports whether the part reached the configured size
+func (s *shard[T]) full(o *options) bool {
+	if s.writer == nil {
+		return false
+	}
+	return (o.shardRows > 0 && s.writer.rows >= o.shardRows) ||
+		(o.shardBytes > 0 && s.writer.written >= o.shardBytes)
+}
+
+// close closes the Writer and the file of the open part, if any
+func (s *shard[T]) close() error {
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	s.writer, s.file = nil, nil
+	return err
+}
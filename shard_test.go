@@ -0,0 +1,121 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Sale struct {
+	Region string `csv:"region"`
+	Amount int    `csv:"amount"`
+}
+
+// memFile is an in-memory part file
+type memFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (f *memFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestShardedWriter(t *testing.T) {
+	files := make(map[string]*memFile)
+	create := func(key string, part int) (io.WriteCloser, error) {
+		f := &memFile{}
+		files[fmt.Sprintf("%s-%d.csv", key, part)] = f
+		return f, nil
+	}
+	sales := []Sale{
+		{"eu", 1}, {"us", 2}, {"eu", 3}, {"eu", 4}, {"us", 5},
+	}
+	sw, err := rowboat.NewShardedWriter(create, func(s Sale) string { return s.Region }, rowboat.WithMaxShardRows(2))
+	if err != nil {
+		t.Fatalf("Failed to create ShardedWriter: %v", err)
+	}
+	if err := sw.WriteSlice(sales); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	expected := map[string]string{
+		"eu-0.csv": "region,amount\neu,1\neu,3\n",
+		"eu-1.csv": "region,amount\neu,4\n",
+		"us-0.csv": "region,amount\nus,2\nus,5\n",
+	}
+	got := make(map[string]string)
+	for name, f := range files {
+		if !f.closed {
+			t.Errorf("Expected %s to be closed", name)
+		}
+		got[name] = f.String()
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if keys := sw.Keys(); !reflect.DeepEqual(keys, []string{"eu", "us"}) {
+		t.Errorf("Expected: %+v\nGot: %+v", []string{"eu", "us"}, keys)
+	}
+}
+
+func TestShardedWriterBytes(t *testing.T) {
+	var parts []*memFile
+	create := func(key string, part int) (io.WriteCloser, error) {
+		if part != len(parts) {
+			t.Fatalf("Expected: %+v\nGot: %+v", len(parts), part)
+		}
+		parts = append(parts, &memFile{})
+		return parts[part], nil
+	}
+	// Each record is 7 bytes, so parts roll over after the second record
+	sw, err := rowboat.NewShardedWriter[Sale](create, nil, rowboat.WithMaxShardBytes(14))
+	if err != nil {
+		t.Fatalf("Failed to create ShardedWriter: %v", err)
+	}
+	for i := range 5 {
+		if err := sw.Write(Sale{Region: "eu", Amount: 100 + i}); err != nil {
+			t.Fatalf("Failed to write record: %v", err)
+		}
+	}
+	if err := sw.Close(); err != nil {
+		t.Fatalf("Failed to close ShardedWriter: %v", err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected: %+v\nGot: %+v", 3, len(parts))
+	}
+	if expected := "region,amount\neu,104\n"; parts[2].String() != expected {
+		t.Errorf("Expected: %q\nGot: %q", expected, parts[2].String())
+	}
+}
+
+// DailySale limits its own part files
+type DailySale Sale
+
+func (DailySale) CSVOptions() []rowboat.Option {
+	return []rowboat.Option{rowboat.WithMaxShardRows(1)}
+}
+
+func TestShardedWriterCSVOptions(t *testing.T) {
+	files := make(map[string]*memFile)
+	create := func(key string, part int) (io.WriteCloser, error) {
+		f := &memFile{}
+		files[fmt.Sprintf("%s-%d.csv", key, part)] = f
+		return f, nil
+	}
+	sw, err := rowboat.NewShardedWriter[DailySale](create, nil)
+	if err != nil {
+		t.Fatalf("Failed to create ShardedWriter: %v", err)
+	}
+	if err := sw.WriteSlice([]DailySale{{"eu", 1}, {"us", 2}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Expected a part file per record, got %d", len(files))
+	}
+}
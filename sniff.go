@@ -0,0 +1,107 @@
+package rowboat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// sniffSize is the number of bytes Sniff inspects
+const sniffSize = 8 << 10
+
+// sniffDelimiters are the delimiters Sniff chooses from, in order of
+// preference on ties
+var sniffDelimiters = []rune{',', ';', '\t', '|'}
+
+// Sniff guesses the dialect of the CSV data in r by inspecting its first
+// few KB. The delimiter is the one of comma, semicolon, tab and pipe that
+// splits the most rows into the same number of fields; files with a single
+// column are reported as comma-separated.
+func Sniff(r io.Reader) (Dialect, error) {
+	sample, err := io.ReadAll(io.LimitReader(r, sniffSize))
+	if err != nil {
+		return Dialect{}, err
+	}
+	return sniffSample(sample, len(sample) == sniffSize), nil
+}
+
+// WithAutoDetectDelimiter makes the Reader sniff the delimiter and quote
+// style from the start of the input, as Sniff does, instead of using
+// WithDelimiter and WithLazyQuotes
+func WithAutoDetectDelimiter() Option {
+	return func(o *options) {
+		o.sniff = true
+	}
+}
+
+// sniffInput sets the delimiter and quote style of o from the start of r
+// and returns a reader yielding all of r
+func (o *options) sniffInput(r io.Reader) io.Reader {
+	br := bufio.NewReaderSize(r, sniffSize)
+	sample, _ := br.Peek(sniffSize)
+	d := sniffSample(sample, len(sample) == sniffSize)
+	o.delimiter, o.lazyQuotes = d.Delimiter, d.LazyQuotes
+	return br
+}
+
+// sniffSample guesses the dialect of sample. A truncated sample ends in
+// the middle of a line, which is ignored.
+func sniffSample(sample []byte, truncated bool) Dialect {
+	if i := bytes.LastIndexByte(sample, '\n'); truncated && i >= 0 {
+		sample = sample[:i+1]
+	}
+	d := Dialect{Delimiter: ',', UseCRLF: bytes.Contains(sample, []byte("\r\n"))}
+
+	best := 0
+	for _, delimiter := range sniffDelimiters {
+		if score := delimiterScore(sample, delimiter); score > best {
+			d.Delimiter, best = delimiter, score
+		}
+	}
+
+	// Strict parsing fails on quotes inside unquoted fields
+	cr := csv.NewReader(bytes.NewReader(sample))
+	cr.Comma = d.Delimiter
+	cr.FieldsPerRecord = -1
+	for {
+		_, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, csv.ErrBareQuote) || errors.Is(err, csv.ErrQuote) {
+			d.LazyQuotes = true
+			break
+		}
+	}
+	return d
+}
+
+// delimiterScore returns the number of rows of sample having the most
+// common number of fields when split by delimiter, or 0 if that number is
+// one
+func delimiterScore(sample []byte, delimiter rune) int {
+	cr := csv.NewReader(bytes.NewReader(sample))
+	cr.Comma = delimiter
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	counts := make(map[int]int)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0
+		}
+		counts[len(record)]++
+	}
+	score := 0
+	for fields, rows := range counts {
+		if fields > 1 && rows > score {
+			score = rows
+		}
+	}
+	return score
+}
@@ -0,0 +1,53 @@
+package rowboat_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected rowboat.Dialect
+	}{
+		{"comma", "Name,Email,Age\nAlice,alice@example.com,30\n", rowboat.Dialect{Delimiter: ','}},
+		{"semicolon", "Name;Price\nWidget;1,50\nGadget;2,75\n", rowboat.Dialect{Delimiter: ';'}},
+		{"tab", "Name\tEmail\r\nAlice\talice@example.com\r\n", rowboat.Dialect{Delimiter: '\t', UseCRLF: true}},
+		{"pipe", "a|b|c\n1|2|3\n4|5|6\n", rowboat.Dialect{Delimiter: '|'}},
+		{"bare quotes", "Name,Height\nBob,6'2\"\n", rowboat.Dialect{Delimiter: ',', LazyQuotes: true}},
+		{"single column", "Name\nAlice\n", rowboat.Dialect{Delimiter: ','}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rowboat.Sniff(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Failed to sniff: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected: %+v\nGot: %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAutoDetectDelimiter(t *testing.T) {
+	csvData := "Name;Email;Age\n" + strings.Repeat("Alice;alice@example.com;30\n", 1000)
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithAutoDetectDelimiter())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(got) != 1000 {
+		t.Fatalf("Expected: %+v\nGot: %+v", 1000, len(got))
+	}
+	if expected := (Person{Name: "Alice", Email: "alice@example.com", Age: 30}); !reflect.DeepEqual(got[999], expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got[999])
+	}
+}
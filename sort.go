@@ -0,0 +1,372 @@
+package rowboat
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"reflect"
+	"slices"
+)
+
+// DefaultSortBuffer is the number of elements SortBy holds in memory before
+// spilling sorted runs to disk
+const DefaultSortBuffer = 100_000
+
+// SortOption configures SortBy
+type SortOption func(*sortConfig)
+
+// sortConfig holds the settings of SortBy
+type sortConfig struct {
+	buffer int
+	dir    string
+}
+
+// WithSortBuffer sets the number of elements SortBy sorts in memory. Larger
+// inputs are split into runs of this size that are spilled to disk and
+// merged.
+func WithSortBuffer(n int) SortOption {
+	return func(c *sortConfig) {
+		c.buffer = n
+	}
+}
+
+// WithSortDir sets the directory holding spilled runs. The default is the
+// system's temporary directory.
+func WithSortDir(dir string) SortOption {
+	return func(c *sortConfig) {
+		c.dir = dir
+	}
+}
+
+// sortFanIn is the number of runs SortBy merges at once, bounding the
+// number of open files
+const sortFanIn = 64
+
+// sortRun is a sorted run spilled to disk. Runs merged from level n runs
+// are at level n+1.
+type sortRun struct {
+	path  string
+	level int
+}
+
+// SortBy returns the elements of s in the order defined by less. The sort
+// is stable. Inputs that do not fit the sort buffer are spilled to
+// temporary files with encoding/gob, so V must be gob-encodable and its
+// unexported fields are not preserved; a struct V with unexported fields is
+// reported as an error once spilling is needed. Runs are merged 64 at a
+// time, so large inputs need few open files. The files are removed once
+// iteration ends. Errors reading or writing spilled runs are yielded as the
+// final element.
+func SortBy[V any](less func(a, b V) bool, s iter.Seq[V], opts ...SortOption) iter.Seq2[V, error] {
+	cfg := sortConfig{buffer: DefaultSortBuffer}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.buffer = max(cfg.buffer, 1)
+	cmp := func(a, b V) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	}
+
+	return func(yield func(V, error) bool) {
+		var zero V
+		var runs []sortRun
+		defer func() {
+			for _, run := range runs {
+				os.Remove(run.path)
+			}
+		}()
+
+		buf := make([]V, 0, min(cfg.buffer, 1024))
+		for v := range s {
+			buf = append(buf, v)
+			if len(buf) < cfg.buffer {
+				continue
+			}
+			if len(runs) == 0 {
+				if err := checkGobFields[V](); err != nil {
+					yield(zero, err)
+					return
+				}
+			}
+			slices.SortStableFunc(buf, cmp)
+			path, err := spillRun(cfg.dir, slices.Values(buf))
+			if path != "" {
+				runs = append(runs, sortRun{path: path})
+			}
+			if err == nil {
+				runs, err = compactRuns(cfg.dir, cmp, runs)
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			clear(buf)
+			buf = buf[:0]
+		}
+		slices.SortStableFunc(buf, cmp)
+
+		// Everything fit in memory
+		if len(runs) == 0 {
+			for _, v := range buf {
+				if !yield(v, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		// Merge runs in passes until they fit the fan-in together with the
+		// in-memory remainder
+		for len(runs) >= sortFanIn {
+			merged, err := mergeRunFiles(cfg.dir, cmp, runs[:sortFanIn])
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			runs = append([]sortRun{merged}, runs[sortFanIn:]...)
+		}
+
+		// Merge the spilled runs with the in-memory remainder, which comes
+		// last in input order
+		sources, closeRuns, err := openRuns[V](runs)
+		defer closeRuns()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		sources = append(sources, &runSource[V]{mem: buf})
+		err = mergeRuns(cmp, sources, func(v V) bool { return yield(v, nil) })
+		if err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// compactRuns merges the last sortFanIn runs into one while they are at the
+// same level. Merging neighbouring runs keeps the sort stable, and merging
+// equal levels reads each element once per level.
+func compactRuns[V any](dir string, cmp func(a, b V) int, runs []sortRun) ([]sortRun, error) {
+	for n := len(runs); n >= sortFanIn; n = len(runs) {
+		group := runs[n-sortFanIn:]
+		if group[0].level != group[len(group)-1].level {
+			break
+		}
+		merged, err := mergeRunFiles(dir, cmp, group)
+		if err != nil {
+			return runs, err
+		}
+		merged.level = group[0].level + 1
+		runs = append(runs[:n-sortFanIn], merged)
+	}
+	return runs, nil
+}
+
+// mergeRunFiles merges runs into a new run file and removes them
+func mergeRunFiles[V any](dir string, cmp func(a, b V) int, runs []sortRun) (sortRun, error) {
+	sources, closeRuns, err := openRuns[V](runs)
+	defer closeRuns()
+	if err != nil {
+		return sortRun{}, err
+	}
+	var mergeErr error
+	path, err := spillRun(dir, func(yield func(V) bool) {
+		mergeErr = mergeRuns(cmp, sources, yield)
+	})
+	if err == nil {
+		err = mergeErr
+	}
+	if err != nil {
+		if path != "" {
+			os.Remove(path)
+		}
+		return sortRun{}, err
+	}
+	for _, run := range runs {
+		os.Remove(run.path)
+	}
+	return sortRun{path: path}, nil
+}
+
+// openRuns opens run files for merging. The returned function closes them.
+func openRuns[V any](runs []sortRun) ([]*runSource[V], func(), error) {
+	var files []*os.File
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	sources := make([]*runSource[V], 0, len(runs)+1)
+	for _, run := range runs {
+		f, err := os.Open(run.path)
+		if err != nil {
+			return nil, closeAll, err
+		}
+		files = append(files, f)
+		sources = append(sources, &runSource[V]{dec: gob.NewDecoder(bufio.NewReader(f))})
+	}
+	return sources, closeAll, nil
+}
+
+// mergeRuns passes the elements of sorted runs to emit in order, taking
+// equal elements from earlier runs first, until emit returns false
+func mergeRuns[V any](cmp func(a, b V) int, sources []*runSource[V], emit func(V) bool) error {
+	h := &mergeHeap[V]{cmp: cmp}
+	for i, src := range sources {
+		if err := h.push(src, i); err != nil {
+			return err
+		}
+	}
+	for h.Len() > 0 {
+		top := h.items[0]
+		if !emit(top.value) {
+			return nil
+		}
+		ok, err := top.src.next(&top.value)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
+
+// checkGobFields reports an error if V is a struct with unexported fields,
+// which encoding/gob would drop from spilled elements
+func checkGobFields[V any]() error {
+	t := reflect.TypeFor[V]()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := range t.NumField() {
+		if f := t.Field(i); !f.IsExported() && !f.Anonymous {
+			return fmt.Errorf("SortBy cannot spill %s: unexported field %s would be lost", t, f.Name)
+		}
+	}
+	return nil
+}
+
+// spillRun writes a sorted run to a new temporary file in dir and returns
+// its path
+func spillRun[V any](dir string, run iter.Seq[V]) (string, error) {
+	f, err := os.CreateTemp(dir, "rowboat-sort-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	enc := gob.NewEncoder(w)
+	for v := range run {
+		if err = enc.Encode(&v); err != nil {
+			return f.Name(), err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return f.Name(), err
+	}
+	return f.Name(), f.Close()
+}
+
+// runSource yields the elements of a sorted run, from a spilled file or
+// from memory
+type runSource[V any] struct {
+	dec *gob.Decoder
+	mem []V
+}
+
+// next stores the next element of the run in v and reports whether there
+// was one
+func (r *runSource[V]) next(v *V) (bool, error) {
+	if r.dec == nil {
+		if len(r.mem) == 0 {
+			return false, nil
+		}
+		*v, r.mem = r.mem[0], r.mem[1:]
+		return true, nil
+	}
+	var zero V
+	*v = zero
+	err := r.dec.Decode(v)
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// mergeItem is the current head of a run during the merge
+type mergeItem[V any] struct {
+	value V
+	run   int
+	src   *runSource[V]
+}
+
+// mergeHeap orders run heads by value, then by run to keep the sort stable
+type mergeHeap[V any] struct {
+	items []*mergeItem[V]
+	cmp   func(a, b V) int
+}
+
+// push adds the head of a run to the heap unless the run is empty
+func (h *mergeHeap[V]) push(src *runSource[V], run int) error {
+	item := &mergeItem[V]{run: run, src: src}
+	ok, err := src.next(&item.value)
+	if ok {
+		heap.Push(h, item)
+	}
+	return err
+}
+
+func (h *mergeHeap[V]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[V]) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].value, h.items[j].value); c != 0 {
+		return c < 0
+	}
+	return h.items[i].run < h.items[j].run
+}
+
+func (h *mergeHeap[V]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[V]) Push(x any) { h.items = append(h.items, x.(*mergeItem[V])) }
+
+func (h *mergeHeap[V]) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+// DedupeBy returns a sequence that contains the elements of s whose key has
+// not been seen before, keeping the first occurrence. Keys are held in
+// memory.
+func DedupeBy[V any, K comparable](key func(V) K, s iter.Seq[V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		seen := make(map[K]struct{})
+		for v := range s {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
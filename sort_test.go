@@ -0,0 +1,106 @@
+package rowboat_test
+
+import (
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestSortBy(t *testing.T) {
+	people := []Person{
+		{Name: "Eve", Age: 40},
+		{Name: "Alice", Age: 30},
+		{Name: "Bob", Age: 25},
+		{Name: "Carol", Age: 30},
+		{Name: "Dan", Age: 25},
+		{Name: "Frank", Age: 50},
+		{Name: "Grace", Age: 30},
+	}
+	byAge := func(a, b Person) bool { return a.Age < b.Age }
+	expected := []string{"Bob", "Dan", "Alice", "Carol", "Grace", "Eve", "Frank"}
+
+	for _, buffer := range []int{100, 2} {
+		dir := t.TempDir()
+		var names []string
+		for p, err := range rowboat.SortBy(byAge, slices.Values(people), rowboat.WithSortBuffer(buffer), rowboat.WithSortDir(dir)) {
+			if err != nil {
+				t.Fatalf("Failed to sort: %v", err)
+			}
+			names = append(names, p.Name)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Buffer %d: expected %v, got %v", buffer, expected, names)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Errorf("Buffer %d: expected spilled runs to be removed, found %d files", buffer, len(entries))
+		}
+	}
+}
+
+func TestDedupeBy(t *testing.T) {
+	people := slices.Values([]Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Alice B.", Email: "alice@example.com", Age: 31},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	})
+	var names []string
+	for p := range rowboat.DedupeBy(func(p Person) string { return p.Email }, people) {
+		names = append(names, p.Name)
+	}
+	if want := []string{"Alice", "Bob"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected: %v\nGot: %v", want, names)
+	}
+}
+
+func TestSortByManyRuns(t *testing.T) {
+	dir := t.TempDir()
+	var input []int
+	for i := range 1000 {
+		input = append(input, (i*7919)%1000)
+	}
+	var got []int
+	for v, err := range rowboat.SortBy(func(a, b int) bool { return a < b }, slices.Values(input), rowboat.WithSortBuffer(3), rowboat.WithSortDir(dir)) {
+		if err != nil {
+			t.Fatalf("Failed to sort: %v", err)
+		}
+		got = append(got, v)
+	}
+	expected := slices.Sorted(slices.Values(input))
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %v\nGot: %v", expected, got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected spilled runs to be removed, found %d files", len(entries))
+	}
+}
+
+func TestSortByUnexportedFields(t *testing.T) {
+	type secret struct {
+		Name string
+		key  string
+	}
+	items := []secret{{"b", "x"}, {"a", "y"}, {"c", "z"}}
+	less := func(a, b secret) bool { return a.Name < b.Name }
+
+	var err error
+	for _, err = range rowboat.SortBy(less, slices.Values(items), rowboat.WithSortBuffer(1), rowboat.WithSortDir(t.TempDir())) {
+	}
+	if err == nil || !strings.Contains(err.Error(), "unexported field key") {
+		t.Errorf("Expected an unexported field error, got %v", err)
+	}
+
+	var sorted []secret
+	for v, err := range rowboat.SortBy(less, slices.Values(items)) {
+		if err != nil {
+			t.Fatalf("Failed to sort in memory: %v", err)
+		}
+		sorted = append(sorted, v)
+	}
+	if expected := []secret{{"a", "y"}, {"b", "x"}, {"c", "z"}}; !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, sorted)
+	}
+}
@@ -0,0 +1,98 @@
+package rowboat
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// FieldSpec describes how a struct field maps to a CSV column, as parsed
+// from its csv tag. Tooling such as documentation generators and form
+// builders can use it to introspect a type's CSV schema.
+type FieldSpec struct {
+	Field     reflect.StructField // struct field; for Fields, Index is its path in T
+	Skip      bool                // tagged csv:"-"
+	Name      string              // column name
+	Aliases   []string            // alternative header names accepted on read
+	Index     int                 // column position, -1 if ParseTag found no index option
+	OmitEmpty bool                // zero values are written as empty cells
+	Raw       bool                // cell text is stored and written verbatim
+	Quote     bool                // cells are always quoted on write
+	Trim      bool                // surrounding whitespace is trimmed before decoding
+	Currency  bool                // currency symbols are stripped before parsing
+	Encrypt   bool                // cells are encrypted with the Encrypter of WithCipher
+	Pad       int                 // width integers are zero-padded to on write
+	Prec      int                 // digits after the decimal point of floats and decimals, -1 for shortest
+	FloatFmt  string              // fmt verb formatting floats, e.g. %.4f
+	Format    string              // iso8601, isoweek or a time layout
+	Enum      []string            // allowed cell values, nil for any
+	Locale    string              // locale of the locale option
+	Mask      string              // redaction of the mask option: all, lastN or firstN
+}
+
+// ParseTag parses the csv tag of a struct field the way Readers and
+// Writers do, reporting invalid tag options as errors
+func ParseTag(field reflect.StructField) (FieldSpec, error) {
+	csvTag := field.Tag.Get("csv")
+	if csvTag == "-" {
+		return FieldSpec{Field: field, Skip: true, Name: field.Name, Index: -1, Prec: -1}, nil
+	}
+	if len(field.Index) == 0 {
+		field.Index = []int{0}
+	}
+	fi, err := parseTag(field, strings.Split(csvTag, ","))
+	if err != nil {
+		return FieldSpec{}, err
+	}
+	spec := fi.spec()
+	if !fi.Indexed {
+		spec.Index = -1
+	}
+	return spec, nil
+}
+
+// Fields returns the columns of T in column order, with embedded structs
+// promoted and indexes assigned, exactly as a Reader or Writer maps them
+func Fields[T any]() ([]FieldSpec, error) {
+	fields, err := cachedFields(recordType[T]())
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]FieldSpec, len(fields))
+	for i, fi := range fields {
+		specs[i] = fi.spec()
+	}
+	return specs, nil
+}
+
+// spec returns the public description of the field
+func (fi fieldInfo) spec() FieldSpec {
+	spec := FieldSpec{
+		Field:     fi.Field,
+		Name:      fi.Name,
+		Aliases:   slices.Clone(fi.Aliases),
+		Index:     fi.Index,
+		OmitEmpty: fi.Omit,
+		Raw:       fi.Raw,
+		Quote:     fi.Quote,
+		Trim:      fi.Trim,
+		Currency:  fi.Currency,
+		Encrypt:   fi.Encrypt,
+		Pad:       fi.Pad,
+		Prec:      fi.Prec,
+		FloatFmt:  fi.FloatFmt,
+		Format:    fi.Format,
+		Enum:      slices.Clone(fi.Enum),
+	}
+	// Locales and masks are parsed into values, so take their names from
+	// the tag
+	for _, part := range strings.Split(fi.Field.Tag.Get("csv"), ",")[1:] {
+		part = strings.TrimSpace(part)
+		if name, ok := strings.CutPrefix(part, "locale="); ok {
+			spec.Locale = name
+		} else if mask, ok := strings.CutPrefix(part, "mask="); ok {
+			spec.Mask = mask
+		}
+	}
+	return spec
+}
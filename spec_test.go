@@ -0,0 +1,52 @@
+package rowboat_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestParseTag(t *testing.T) {
+	type Order struct {
+		ID       int       `csv:"id,index=2,pad=6"`
+		Customer string    `csv:"customer,alias=client|buyer,trim"`
+		Total    float64   `csv:"total,prec=2,locale=de"`
+		Card     string    `csv:"card,mask=last4"`
+		Placed   time.Time `csv:",format=2006-01-02"`
+		Internal string    `csv:"-"`
+	}
+	typ := reflect.TypeFor[Order]()
+
+	spec, err := rowboat.ParseTag(typ.Field(1))
+	if err != nil {
+		t.Fatalf("Failed to parse tag: %v", err)
+	}
+	expected := rowboat.FieldSpec{Field: typ.Field(1), Name: "customer", Aliases: []string{"client", "buyer"}, Index: -1, Trim: true, Prec: -1}
+	if !reflect.DeepEqual(spec, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, spec)
+	}
+	if spec, _ := rowboat.ParseTag(typ.Field(5)); !spec.Skip {
+		t.Errorf("Expected a skipped field, got %+v", spec)
+	}
+	bad := reflect.StructField{Name: "Qty", Type: reflect.TypeFor[string](), Tag: `csv:"qty,pad=3"`}
+	if _, err := rowboat.ParseTag(bad); err == nil {
+		t.Error("Expected an error for pad on a string field")
+	}
+
+	specs, err := rowboat.Fields[Order]()
+	if err != nil {
+		t.Fatalf("Failed to get fields: %v", err)
+	}
+	var got []string
+	for _, s := range specs {
+		got = append(got, s.Name)
+	}
+	if want := []string{"customer", "total", "id", "card", "Placed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, got)
+	}
+	if specs[1].Locale != "de" || specs[1].Prec != 2 || specs[2].Pad != 6 || specs[3].Mask != "last4" || specs[4].Format != "2006-01-02" {
+		t.Errorf("Unexpected tag options: %+v", specs)
+	}
+}
@@ -0,0 +1,242 @@
+package rowboat
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteRows spools a query result to w as CSV, with a header of the
+// result's column names. NULLs are written as empty cells and times in the
+// WithTimeLayout layout, RFC 3339 by default. It does not close rows.
+func WriteRows(w io.Writer, rows *sql.Rows, opts ...Option) error {
+	o := newOptions(opts)
+	var closer io.Closer
+	if o.gzip {
+		gz := gzip.NewWriter(w)
+		w, closer = gz, gz
+	}
+	writer := csv.NewWriter(w)
+	o.configureWriter(writer)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			record[i] = formatSQLValue(v, o)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	if closer != nil {
+		return closer.Close()
+	}
+	return nil
+}
+
+// formatSQLValue converts a scanned column value to a cell
+func formatSQLValue(v any, o *options) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		layout := time.RFC3339
+		if o.timeLayout != "" {
+			layout = o.timeLayout
+		}
+		return v.Format(layout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Execer executes statements, like *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Placeholders formats the n-th (1-based) bind parameter of a statement
+type Placeholders func(n int) string
+
+var (
+	// QuestionPlaceholders writes parameters as ?, as MySQL and SQLite expect
+	QuestionPlaceholders Placeholders = func(int) string { return "?" }
+	// DollarPlaceholders writes parameters as $1, $2, ..., as Postgres expects
+	DollarPlaceholders Placeholders = func(n int) string { return "$" + strconv.Itoa(n) }
+)
+
+// DefaultCopyBatch is the number of records CopyFrom inserts per statement
+const DefaultCopyBatch = 500
+
+// DefaultMaxParams is the number of bind parameters CopyFrom uses per
+// statement at most, the limit of Postgres
+const DefaultMaxParams = 65535
+
+// CopyOption configures CopyFrom
+type CopyOption func(*copyConfig)
+
+// copyConfig holds the settings of CopyFrom
+type copyConfig struct {
+	batch        int
+	maxParams    int
+	placeholders Placeholders
+}
+
+// WithCopyBatch sets the number of records inserted per statement
+func WithCopyBatch(n int) CopyOption {
+	return func(c *copyConfig) {
+		c.batch = n
+	}
+}
+
+// WithMaxParams sets the number of bind parameters a statement may have,
+// such as 32766 for SQLite. Batches of wide tables are made smaller to stay
+// within it. The default is DefaultMaxParams.
+func WithMaxParams(n int) CopyOption {
+	return func(c *copyConfig) {
+		c.maxParams = n
+	}
+}
+
+// WithPlaceholders sets the bind parameter syntax of the database. The
+// default is QuestionPlaceholders.
+func WithPlaceholders(p Placeholders) CopyOption {
+	return func(c *copyConfig) {
+		c.placeholders = p
+	}
+}
+
+// CopyFrom inserts the records of r into table with multi-row INSERT
+// statements and returns the number of records inserted. Columns are named
+// after the CSV columns of T, narrowed by WithColumns; table and column
+// names are not quoted. Statements have at most WithMaxParams bind
+// parameters. Field values implementing driver.Valuer, basic kinds and
+// time.Time are passed to the driver as is, other fields as their CSV text.
+// Run it in a transaction to make the import atomic.
+func CopyFrom[T any](ctx context.Context, db Execer, table string, r *Reader[T], opts ...CopyOption) (int64, error) {
+	cfg := copyConfig{batch: DefaultCopyBatch, maxParams: DefaultMaxParams, placeholders: QuestionPlaceholders}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var fields []fieldInfo
+	for _, fi := range r.fields {
+		if r.selected(fi) {
+			fields = append(fields, fi)
+		}
+	}
+	columns := make([]string, len(fields))
+	for i, fi := range fields {
+		columns[i] = fi.Name
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", table, strings.Join(columns, ", "))
+	if len(fields) > 0 && cfg.maxParams > 0 {
+		cfg.batch = min(cfg.batch, cfg.maxParams/len(fields))
+	}
+	cfg.batch = max(cfg.batch, 1)
+
+	var inserted int64
+	for batch, err := range r.Batches(cfg.batch) {
+		if err != nil {
+			return inserted, err
+		}
+		var query strings.Builder
+		query.WriteString(prefix)
+		args := make([]any, 0, len(batch)*len(fields))
+		for i, record := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			query.WriteByte('(')
+			v := reflect.Indirect(reflect.ValueOf(record))
+			for j, fi := range fields {
+				if j > 0 {
+					query.WriteString(", ")
+				}
+				arg, err := any(nil), error(nil)
+				if field, ok := fi.value(v); ok {
+					arg, err = sqlValue(field, fi, r.opts)
+				}
+				if err != nil {
+					return inserted, fmt.Errorf("error converting field %s: %w", fi.Field.Name, err)
+				}
+				args = append(args, arg)
+				query.WriteString(cfg.placeholders(len(args)))
+			}
+			query.WriteByte(')')
+		}
+		if _, err := db.ExecContext(ctx, query.String(), args...); err != nil {
+			return inserted, err
+		}
+		inserted += int64(len(batch))
+	}
+	return inserted, nil
+}
+
+// valuerType is the reflect.Type of the driver.Valuer interface
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// sqlValue converts a field into a statement argument
+func sqlValue(field reflect.Value, fi fieldInfo, opts *options) (any, error) {
+	if field.Kind() == reflect.Pointer && fi.Encode == nil && !field.Type().Implements(valuerType) {
+		if field.IsNil() {
+			return nil, nil
+		}
+		return sqlValue(field.Elem(), fi, opts)
+	}
+	if field.Type().Implements(valuerType) || field.Type() == reflect.TypeOf(time.Time{}) {
+		return field.Interface(), nil
+	}
+	if !field.Type().Implements(csvMarshalerType) && fi.Encode == nil {
+		switch field.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return field.Interface(), nil
+		}
+	}
+	return getFieldStringValue(field, fi, opts)
+}
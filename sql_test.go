@@ -0,0 +1,164 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+// fakeDriver is a database/sql driver recording executed statements and
+// serving a fixed result set to queries
+type fakeDriver struct {
+	mu      sync.Mutex
+	execs   []fakeExec
+	columns []string
+	rows    [][]driver.Value
+}
+
+type fakeExec struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *fakeConn) Commit() error             { return nil }
+func (c *fakeConn) Rollback() error           { return nil }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, fakeExec{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// openFake opens a database backed by a new fakeDriver
+func openFake(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{}
+	name := "rowboat-fake-" + t.Name()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestWriteRows(t *testing.T) {
+	db, d := openFake(t)
+	d.columns = []string{"id", "name", "balance", "active", "created"}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	d.rows = [][]driver.Value{
+		{int64(1), "Alice", 10.5, true, created},
+		{int64(2), []byte("Bob, Jr."), nil, false, nil},
+	}
+
+	rows, err := db.Query("SELECT * FROM accounts")
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	defer rows.Close()
+	var buf bytes.Buffer
+	if err := rowboat.WriteRows(&buf, rows); err != nil {
+		t.Fatalf("Failed to write rows: %v", err)
+	}
+
+	expected := "id,name,balance,active,created\n1,Alice,10.5,true,2024-01-02T03:04:05Z\n2,\"Bob, Jr.\",,false,\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	db, d := openFake(t)
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25
+Charlie,charlie@example.com,35`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithColumns("Name", "Age"))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	n, err := rowboat.CopyFrom(context.Background(), db, "people", rb,
+		rowboat.WithCopyBatch(2), rowboat.WithPlaceholders(rowboat.DollarPlaceholders))
+	if err != nil {
+		t.Fatalf("Failed to copy records: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Expected 3 records inserted, got %d", n)
+	}
+
+	expected := []fakeExec{
+		{
+			query: "INSERT INTO people (Name, Age) VALUES ($1, $2), ($3, $4)",
+			args:  []driver.Value{"Alice", int64(30), "Bob", int64(25)},
+		},
+		{
+			query: "INSERT INTO people (Name, Age) VALUES ($1, $2)",
+			args:  []driver.Value{"Charlie", int64(35)},
+		},
+	}
+	if !reflect.DeepEqual(d.execs, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, d.execs)
+	}
+
+	// Batches shrink to stay within the bind parameter limit
+	d.execs = nil
+	rb, err = rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithColumns("Name", "Age"))
+	if err != nil {
+		t.Fatalf("Failed to create RowBoat: %v", err)
+	}
+	if _, err := rowboat.CopyFrom(context.Background(), db, "people", rb, rowboat.WithMaxParams(5)); err != nil {
+		t.Fatalf("Failed to copy records: %v", err)
+	}
+	if len(d.execs) != 2 || len(d.execs[0].args) != 4 {
+		t.Errorf("Expected 2 statements of at most 4 parameters, got %+v", d.execs)
+	}
+}
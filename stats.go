@@ -0,0 +1,192 @@
+package rowboat
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"reflect"
+)
+
+// Stats summarizes the records that passed through a Writer, or through a
+// Reader under WithStats
+type Stats struct {
+	Records int                     // number of records written or read
+	Columns map[string]*ColumnStats // per-column statistics, keyed by column name
+	opts    *options
+}
+
+// ColumnStats accumulates the values of a column. Sum, Min and Max cover
+// the numeric values of numeric columns.
+type ColumnStats struct {
+	Sum      float64 // sum of all values
+	Count    int     // number of numeric values
+	Min      float64 // smallest value
+	Max      float64 // largest value
+	Nulls    int     // number of empty or null cells
+	distinct sketch
+}
+
+// Mean returns the average of the numeric values, or 0 if there are none
+func (cs *ColumnStats) Mean() float64 {
+	if cs.Count == 0 {
+		return 0
+	}
+	return cs.Sum / float64(cs.Count)
+}
+
+// Distinct returns an estimate of the number of distinct cells, within a
+// few percent for large counts
+func (cs *ColumnStats) Distinct() int {
+	return cs.distinct.estimate()
+}
+
+// WithStats makes the Reader gather statistics about every column while
+// reading, such as the range of numeric values and the number of null and
+// distinct cells. They are returned by Reader.Stats.
+func WithStats() Option {
+	return func(o *options) {
+		o.stats = true
+	}
+}
+
+// Sum returns the sum of the named numeric column, or 0 if the column is
+// not numeric
+func (s Stats) Sum(column string) float64 {
+	if cs, ok := s.Columns[column]; ok {
+		return cs.Sum
+	}
+	return 0
+}
+
+// Stats returns the statistics gathered under WithStats from the records
+// read so far, typically after iteration
+func (rb *Reader[T]) Stats() Stats {
+	if rb.stats == nil {
+		return Stats{Columns: make(map[string]*ColumnStats)}
+	}
+	return *rb.stats
+}
+
+// newStats creates Stats tracking the numeric columns among fields, or
+// every column if all is set
+func newStats(fields []fieldInfo, all bool, opts *options) *Stats {
+	s := &Stats{Columns: make(map[string]*ColumnStats), opts: opts}
+	for _, fi := range fields {
+		if all || numericValue(reflect.Zero(fi.Field.Type)) != nil {
+			s.Columns[fi.Name] = &ColumnStats{}
+		}
+	}
+	return s
+}
+
+// add accumulates a record's values into the statistics. The cell of the
+// field i is record[columns[i]], or missing if columns[i] is negative.
+// Cells beyond the end of a short record count as nulls.
+func (s *Stats) add(v reflect.Value, fields []fieldInfo, record []string, columns []int) {
+	s.Records++
+	for i, fi := range fields {
+		cs, ok := s.Columns[fi.Name]
+		if !ok || columns[i] < 0 {
+			continue
+		}
+		var cell string
+		if columns[i] < len(record) {
+			cell = record[columns[i]]
+		}
+		if cell == "" || s.opts.isNull(cell, fi.Field.Type) {
+			cs.Nulls++
+		} else if field, ok := fi.value(v); !ok {
+			cs.Nulls++
+		} else if n := numericValue(reflect.Indirect(field)); n != nil {
+			if cs.Count == 0 || *n < cs.Min {
+				cs.Min = *n
+			}
+			if cs.Count == 0 || *n > cs.Max {
+				cs.Max = *n
+			}
+			cs.Sum += *n
+			cs.Count++
+		}
+		cs.distinct.add(cell)
+	}
+}
+
+// numericValue returns the value of a numeric field as a float64, or nil if
+// the field is not numeric
+func numericValue(field reflect.Value) *float64 {
+	var f float64
+	if field.Type() == decimalType {
+		f = field.Interface().(Decimal).Float64()
+		return &f
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(field.Uint())
+	case reflect.Float32, reflect.Float64:
+		f = field.Float()
+	default:
+		return nil
+	}
+	return &f
+}
+
+// sketchBits is the number of hash bits selecting a sketch register
+const sketchBits = 10
+
+// sketch estimates the number of distinct values added to it with
+// HyperLogLog
+type sketch [1 << sketchBits]uint8
+
+// add adds a value to the sketch. The hash is fixed, so that the same
+// input always yields the same estimate.
+func (s *sketch) add(value string) {
+	f := fnv.New64a()
+	f.Write([]byte(value))
+	h := mix64(f.Sum64())
+	rank := uint8(bits.LeadingZeros64(h<<sketchBits|1<<(sketchBits-1))) + 1
+	if i := h >> (64 - sketchBits); rank > s[i] {
+		s[i] = rank
+	}
+}
+
+// mix64 spreads the bits of an FNV hash over all 64 bits, like the
+// splitmix64 finalizer, since the sketch uses the top and leading bits
+func mix64(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// estimate returns the estimated number of distinct values, using linear
+// counting for small numbers
+func (s *sketch) estimate() int {
+	m := float64(len(s))
+	var sum float64
+	zeros := 0
+	for _, rank := range s {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+	e := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if e <= 2.5*m && zeros > 0 {
+		e = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(e))
+}
+
+// WithFooter makes the Writer append a summary row when it is closed. The
+// footer function receives statistics gathered while writing, such as the
+// record count and the sums of numeric columns, and returns the cells of
+// the final row.
+func WithFooter(footer func(stats Stats) []string) Option {
+	return func(o *options) {
+		o.footer = footer
+	}
+}
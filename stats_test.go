@@ -0,0 +1,140 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestWithFooter(t *testing.T) {
+	people := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var buf bytes.Buffer
+	footer := func(stats rowboat.Stats) []string {
+		return []string{"TOTAL", strconv.Itoa(stats.Records), strconv.FormatFloat(stats.Sum("Age"), 'f', -1, 64)}
+	}
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithFooter(footer))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(people)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+	// A second Close must not write the footer again
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer twice: %v", err)
+	}
+
+	expected := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob@example.com,25
+TOTAL,2,55
+`
+	if buf.String() != expected {
+		t.Fatalf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+func TestStatsNonNumericColumn(t *testing.T) {
+	var got rowboat.Stats
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithFooter(func(stats rowboat.Stats) []string {
+		got = stats
+		return []string{"end"}
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Alice", Age: 30}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+	if _, ok := got.Columns["Name"]; ok {
+		t.Errorf("Expected no statistics for string column Name")
+	}
+	if got.Records != 1 || got.Sum("Age") != 30 {
+		t.Errorf("Unexpected stats: records=%d age=%v", got.Records, got.Sum("Age"))
+	}
+}
+
+func TestReaderStats(t *testing.T) {
+	input := "id,name,score,count\n1,Alice,9.5,3\n2,,\\N,0\n3,Bob,4.5,3\n4,Alice,\\N,5\n"
+	reader, err := rowboat.NewReader[NullableRow](strings.NewReader(input), rowboat.WithStats(), rowboat.WithNullString(`\N`))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := reader.ReadAll(); err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	stats := reader.Stats()
+	if stats.Records != 4 {
+		t.Errorf("Expected: %+v\nGot: %+v", 4, stats.Records)
+	}
+
+	score := stats.Columns["score"]
+	if score.Count != 2 || score.Min != 4.5 || score.Max != 9.5 || score.Mean() != 7 || score.Nulls != 2 {
+		t.Errorf("Unexpected score stats: %+v mean=%v", *score, score.Mean())
+	}
+	count := stats.Columns["count"]
+	if count.Count != 4 || count.Min != 0 || count.Max != 5 || count.Sum != 11 || count.Distinct() != 3 {
+		t.Errorf("Unexpected count stats: %+v distinct=%v", *count, count.Distinct())
+	}
+	name := stats.Columns["name"]
+	if name.Count != 0 || name.Nulls != 1 || name.Distinct() != 3 {
+		t.Errorf("Unexpected name stats: %+v distinct=%v", *name, name.Distinct())
+	}
+}
+
+func TestReaderStatsDistinctEstimate(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("Name,Email,Age\n")
+	for i := range 20000 {
+		fmt.Fprintf(&sb, "user%d,user%d@example.com,%d\n", i%10000, i, i%100)
+	}
+	reader, err := rowboat.NewReader[Person](strings.NewReader(sb.String()), rowboat.WithStats())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := reader.ReadAll(); err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	stats := reader.Stats()
+	for column, expected := range map[string]int{"Name": 10000, "Email": 20000, "Age": 100} {
+		got := stats.Columns[column].Distinct()
+		if math.Abs(float64(got-expected)) > 0.1*float64(expected) {
+			t.Errorf("Column %s: Expected: about %+v\nGot: %+v", column, expected, got)
+		}
+	}
+}
+
+func TestReaderStatsShortRows(t *testing.T) {
+	input := "Name,Email,Age\nAlice,alice@example.com,30\nBob\n"
+	reader, err := rowboat.NewReader[Person](strings.NewReader(input), rowboat.WithStats(), rowboat.WithFieldsPerRecord(-1))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if _, err := reader.ReadAll(); err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	age := reader.Stats().Columns["Age"]
+	if age.Count != 1 || age.Nulls != 1 || age.Sum != 30 {
+		t.Errorf("Unexpected age stats: %+v", *age)
+	}
+}
@@ -0,0 +1,54 @@
+package rowboat
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// WithTrailer makes the Reader stop at the first row for which match
+// reports true, such as a control record or a "TOTAL" row ending the data.
+// The trailer row is not decoded and may have a different number of fields;
+// it is available from Trailer once reading stops. Any row after the
+// trailer is an error.
+func WithTrailer(match func(row []string) bool) Option {
+	return func(o *options) {
+		o.trailer = match
+	}
+}
+
+// isTrailer reports whether record is a trailer row ending the data
+func (rb *Reader[T]) isTrailer(record []string) bool {
+	if rb.sum != nil && isChecksumTrailer(record) {
+		return true
+	}
+	return rb.opts.trailer != nil && rb.opts.trailer(record)
+}
+
+// Trailer returns the trailer row matched by WithTrailer, or nil if reading
+// has not reached one
+func (rb *Reader[T]) Trailer() []string {
+	return slices.Clone(rb.trailer)
+}
+
+// isTrailerRecord reports whether a record read with err is the trailer,
+// which may have a different number of fields than the data rows
+func (rb *Reader[T]) isTrailerRecord(record []string, err error) bool {
+	return (err == nil || errors.Is(err, csv.ErrFieldCount)) && rb.isTrailer(record)
+}
+
+// checkAfterTrailer reports an error if any row follows the trailer. Under
+// WithChecksum the error wraps ErrChecksum, since the rows are not covered
+// by the checksum.
+func (rb *Reader[T]) checkAfterTrailer() error {
+	record, err := rb.read()
+	if err == io.EOF {
+		return nil
+	}
+	if rb.sum != nil {
+		return fmt.Errorf("%w: rows after trailer", ErrChecksum)
+	}
+	return &RowError{Line: rb.recordLine(), Raw: record, Err: errors.New("row after trailer")}
+}
@@ -0,0 +1,56 @@
+package rowboat_test
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+type Settlement struct {
+	Account string  `csv:"account"`
+	Amount  float64 `csv:"amount"`
+}
+
+func TestTrailer(t *testing.T) {
+	csvData := "account,amount\nA1,10.5\nA2,20\nTOTAL,30.5,2\n"
+	rb, err := rowboat.NewReader[Settlement](strings.NewReader(csvData),
+		rowboat.WithTrailer(func(row []string) bool { return row[0] == "TOTAL" }))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	if rb.Trailer() != nil {
+		t.Errorf("Expected no trailer before reading")
+	}
+	got, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	expected := []Settlement{{"A1", 10.5}, {"A2", 20}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, got)
+	}
+	if want := []string{"TOTAL", "30.5", "2"}; !slices.Equal(rb.Trailer(), want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, rb.Trailer())
+	}
+	if more, _ := rb.ReadAll(); len(more) != 0 {
+		t.Errorf("Expected no rows after the trailer, got %+v", more)
+	}
+}
+
+func TestTrailerFollowedByRows(t *testing.T) {
+	csvData := "account,amount\nA1,10.5\nTOTAL,10.5,1\nA2,20\n"
+	rb, err := rowboat.NewReader[Settlement](strings.NewReader(csvData),
+		rowboat.WithTrailer(func(row []string) bool { return row[0] == "TOTAL" }))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	_, err = rb.ReadAll()
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 4 {
+		t.Errorf("Expected a RowError on line 4, got %v", err)
+	}
+}
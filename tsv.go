@@ -0,0 +1,23 @@
+package rowboat
+
+import "io"
+
+// TSV returns the options for tab-separated files: a tab delimiter and
+// lenient quote handling, matching exports from tools such as BigQuery
+// that do not quote fields
+func TSV() Option {
+	return func(o *options) {
+		o.delimiter = '\t'
+		o.lazyQuotes = true
+	}
+}
+
+// NewTSVReader creates a Reader for tab-separated input
+func NewTSVReader[T any](r io.Reader, opts ...Option) (*Reader[T], error) {
+	return NewReader[T](r, append([]Option{TSV()}, opts...)...)
+}
+
+// NewTSVWriter creates a Writer producing tab-separated output
+func NewTSVWriter[T any](w io.Writer, opts ...Option) (*Writer[T], error) {
+	return NewWriter[T](w, append([]Option{TSV()}, opts...)...)
+}
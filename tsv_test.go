@@ -0,0 +1,63 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestTSV(t *testing.T) {
+	tsvData := "Name\tEmail\tAge\n" +
+		"Alice \"Al\"\talice@example.com\t30\n" +
+		"Bob, Jr.\tbob@example.com\t25\n"
+
+	rb, err := rowboat.NewTSVReader[Person](strings.NewReader(tsvData))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+
+	expected := []Person{
+		{Name: "Alice \"Al\"", Email: "alice@example.com", Age: 30},
+		{Name: "Bob, Jr.", Email: "bob@example.com", Age: 25},
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewTSVWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(expected[1]); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush Writer: %v", err)
+	}
+	expectedTSV := "Name\tEmail\tAge\nBob, Jr.\tbob@example.com\t25\n"
+	if buf.String() != expectedTSV {
+		t.Errorf("Written TSV does not match expected.\nExpected:\n%q\nGot:\n%q", expectedTSV, buf.String())
+	}
+}
+
+func TestWithDelimiter(t *testing.T) {
+	csvData := "Name;Email;Age\nAlice;alice@example.com;30\n"
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithDelimiter(';'))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	expected := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
@@ -0,0 +1,264 @@
+package rowboat
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnType identifies the type of the values in a column
+type ColumnType int
+
+const (
+	String ColumnType = iota
+	Int
+	Uint
+	Float
+	Bool
+	Time
+)
+
+// String returns the name used for the type in a type row
+func (t ColumnType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Uint:
+		return "uint"
+	case Float:
+		return "float"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time"
+	default:
+		return fmt.Sprintf("ColumnType(%d)", int(t))
+	}
+}
+
+// Column describes a named, typed column
+type Column struct {
+	Name   string
+	Type   ColumnType
+	Layout string // time layout of Time columns, or locale of Float columns
+}
+
+// typeToken returns the type row cell describing the column
+func (c Column) typeToken() string {
+	if c.Type == Time || (c.Type == Float && c.Layout != "") {
+		return c.Type.String() + ":" + c.Layout
+	}
+	return c.Type.String()
+}
+
+// checkTypeRow reports a type row with fewer cells than the header, which
+// readers accepting variable-length rows let through
+func checkTypeRow(headers, types []string) error {
+	if len(types) < len(headers) {
+		return fmt.Errorf("error reading type row: %w", &FieldCountError{
+			Expected: len(headers),
+			Got:      len(types),
+			Missing:  slices.Clone(headers[len(types):]),
+		})
+	}
+	return nil
+}
+
+// parseTypeToken parses a type row cell into the column's type and layout
+func parseTypeToken(token string) (ColumnType, string, error) {
+	token = strings.TrimSpace(token)
+	if layout, ok := strings.CutPrefix(token, "time:"); ok {
+		return Time, layout, nil
+	}
+	if name, ok := strings.CutPrefix(token, "float:"); ok {
+		if _, err := lookupLocale(name); err != nil {
+			return 0, "", err
+		}
+		return Float, name, nil
+	}
+	for _, t := range []ColumnType{String, Int, Uint, Float, Bool, Time} {
+		if token == t.String() {
+			if t == Time {
+				return Time, time.RFC3339, nil
+			}
+			return t, "", nil
+		}
+	}
+	return 0, "", fmt.Errorf("unknown column type '%s'", token)
+}
+
+// fieldColumn describes the column produced by a struct field
+func fieldColumn(fi fieldInfo, opts *options) Column {
+	col := Column{Name: fi.Name, Type: String}
+	fieldType := fi.Field.Type
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Implements(csvMarshalerType) || reflect.PointerTo(fieldType).Implements(csvMarshalerType) ||
+		fi.Format == tagISODuration || fi.Format == tagISOWeek || lookupEnum(fieldType) != nil {
+		return col
+	}
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		col.Type = Time
+		col.Layout = fi.timeLayout(opts)
+		return col
+	}
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		col.Type = Int
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		col.Type = Uint
+	case reflect.Float32, reflect.Float64:
+		col.Type = Float
+		if fi.Locale != nil {
+			col.Layout = fi.Locale.Name
+		}
+	case reflect.Bool:
+		col.Type = Bool
+	}
+	return col
+}
+
+// parseColumnValue converts a cell into the Go value for the column's type.
+// Empty cells in non-string columns are returned as nil.
+func parseColumnValue(col Column, value string) (any, error) {
+	if value == "" && col.Type != String {
+		return nil, nil
+	}
+	switch col.Type {
+	case Int:
+		return strconv.ParseInt(value, 10, 64)
+	case Uint:
+		return strconv.ParseUint(value, 10, 64)
+	case Float:
+		if col.Layout != "" {
+			loc, err := lookupLocale(col.Layout)
+			if err != nil {
+				return nil, err
+			}
+			value = loc.normalizeNumber(value)
+		}
+		return strconv.ParseFloat(value, 64)
+	case Bool:
+		return strconv.ParseBool(value)
+	case Time:
+		return time.Parse(col.Layout, value)
+	default:
+		return value, nil
+	}
+}
+
+// WithTypeRow makes the Writer emit a second header row describing the type
+// of each column (int, uint, float, bool, string or time:<layout>, with
+// float:<locale> for floats formatted for a locale), and makes the Reader
+// consume that row after the header.
+func WithTypeRow() Option {
+	return func(o *options) {
+		o.typeRow = true
+	}
+}
+
+// DynamicReader reads CSV files carrying a type row, or described by a
+// Schema, into maps of typed values without requiring a compiled struct
+type DynamicReader struct {
+	reader    *csv.Reader
+	columns   []Column
+	positions []int  // record position of each column, -1 if absent; nil for identity
+	required  []bool // columns rejecting empty cells
+}
+
+// NewDynamicReader creates a reader for files written with WithTypeRow. It
+// consumes the header and type rows immediately.
+func NewDynamicReader(r io.Reader, opts ...Option) (*DynamicReader, error) {
+	o := newOptions(opts)
+	r, err := o.wrapInput(r)
+	if err != nil {
+		return nil, err
+	}
+	dr := &DynamicReader{reader: csv.NewReader(r)}
+	o.configureReader(dr.reader)
+
+	headers, err := dr.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	types, err := dr.reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading type row: %w", err)
+	}
+	if err := checkTypeRow(headers, types); err != nil {
+		return nil, err
+	}
+
+	dr.columns = make([]Column, len(headers))
+	for i, header := range headers {
+		t, layout, err := parseTypeToken(types[i])
+		if err != nil {
+			return nil, fmt.Errorf("column '%s': %w", header, err)
+		}
+		dr.columns[i] = Column{Name: strings.TrimSpace(header), Type: t, Layout: layout}
+	}
+	return dr, nil
+}
+
+// Columns returns the columns described by the header and type rows
+func (dr *DynamicReader) Columns() []Column {
+	return dr.columns
+}
+
+// All returns an iterator over all records, keyed by column name. Iteration
+// stops after yielding the first error.
+func (dr *DynamicReader) All() iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		for {
+			record, err := dr.reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			line, _ := dr.reader.FieldPos(0)
+
+			row := make(map[string]any, len(dr.columns))
+			for i, col := range dr.columns {
+				value := dr.cell(record, i)
+				if value == "" && dr.required != nil && dr.required[i] {
+					yield(nil, &RowError{Line: line, Column: col.Name, Err: errors.New("required value missing")})
+					return
+				}
+				v, err := parseColumnValue(col, value)
+				if err != nil {
+					yield(nil, &RowError{Line: line, Column: col.Name, Err: err})
+					return
+				}
+				row[col.Name] = v
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+// cell returns the value of the i-th column in record
+func (dr *DynamicReader) cell(record []string, i int) string {
+	pos := i
+	if dr.positions != nil {
+		pos = dr.positions[i]
+	}
+	if pos < 0 || pos >= len(record) {
+		return ""
+	}
+	return record[pos]
+}
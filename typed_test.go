@@ -0,0 +1,94 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+func TestTypeRow(t *testing.T) {
+	records := []ComplexRecord{
+		{
+			Name:      "John",
+			CreatedAt: time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+			Active:    true,
+			Score:     98.6,
+			Count:     42,
+			Rate:      1.5,
+			Tags:      "test;debug",
+		},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[ComplexRecord](&buf, rowboat.WithTypeRow())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(records)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	expectedCSV := `name,created_at,active,score,count,rate,tags
+string,time:2006-01-02T15:04:05Z07:00,bool,float,int,float,string
+John,2023-01-02T15:04:05Z,true,98.6,42,1.5,test;debug
+`
+	if buf.String() != expectedCSV {
+		t.Fatalf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+
+	// The typed reader skips the type row
+	rb, err := rowboat.NewReader[ComplexRecord](strings.NewReader(buf.String()), rowboat.WithTypeRow())
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, records) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", records, results)
+	}
+
+	// The dynamic reader reconstructs typed values without the struct
+	dr, err := rowboat.NewDynamicReader(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to create DynamicReader: %v", err)
+	}
+	if cols := dr.Columns(); len(cols) != 7 || cols[1].Type != rowboat.Time || cols[4].Type != rowboat.Int {
+		t.Errorf("Unexpected columns: %+v", cols)
+	}
+
+	var rows []map[string]any
+	for row, err := range dr.All() {
+		if err != nil {
+			t.Fatalf("Failed to read row: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	expected := []map[string]any{
+		{
+			"name":       "John",
+			"created_at": time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+			"active":     true,
+			"score":      98.6,
+			"count":      int64(42),
+			"rate":       1.5,
+			"tags":       "test;debug",
+		},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Dynamic results do not match expected.\nExpected: %+v\nGot: %+v", expected, rows)
+	}
+}
+
+func TestDynamicReaderUnknownType(t *testing.T) {
+	csvData := "a,b\nint,complex\n1,2\n"
+	if _, err := rowboat.NewDynamicReader(strings.NewReader(csvData)); err == nil {
+		t.Errorf("Expected error for unknown column type")
+	}
+}
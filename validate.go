@@ -0,0 +1,115 @@
+package rowboat
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// WithValidator checks every record with validate. The Reader rejects a
+// decoded record failing validation with a *RowError before it is yielded,
+// which counts against WithMaxErrors, and the Writer refuses to write it.
+// Validators run in the order they were added; validators for other record
+// types make the constructor fail.
+func WithValidator[T any](validate func(T) error) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, validate)
+	}
+}
+
+// recordValidators returns the validators of opts for records of type T
+func recordValidators[T any](opts *options) ([]func(T) error, error) {
+	validators := make([]func(T) error, 0, len(opts.validators))
+	for _, v := range opts.validators {
+		validate, ok := v.(func(T) error)
+		if !ok {
+			var t T
+			return nil, fmt.Errorf("validator %T does not accept %T", v, t)
+		}
+		validators = append(validators, validate)
+	}
+	return validators, nil
+}
+
+// validateRecord runs validators on record and returns the first failure
+func validateRecord[T any](validators []func(T) error, record T) error {
+	for _, validate := range validators {
+		if err := validate(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Report is the outcome of Validate
+type Report struct {
+	Rows   int          // data rows read
+	Valid  int          // rows that decoded and passed the validators
+	Header *HeaderError // mismatches between the header and the struct, nil if none
+	Errors []error      // errors of the failed rows in input order, usually *RowError
+}
+
+// OK reports whether the header matches the struct and every row is valid
+func (r Report) OK() bool {
+	return r.Header == nil && len(r.Errors) == 0
+}
+
+// Validate reads all of r as records of type T without keeping them and
+// reports every problem found, so that uploads can be checked before they
+// are committed. The returned error is reserved for failures to read the
+// input; a WithMaxErrors budget ends validation early with its error.
+func Validate[T any](r io.Reader, opts ...Option) (Report, error) {
+	var report Report
+	rb, err := NewReader[T](r, append([]Option{WithMaxErrors(math.MaxInt)}, opts...)...)
+	if err != nil {
+		if errors.As(err, &report.Header) {
+			return report, nil
+		}
+		return report, err
+	}
+	report.Header = rb.headerReport()
+	for _, err := range rb.rows() {
+		if err != nil {
+			report.Errors = rb.Errors()
+			report.Rows = report.Valid + len(report.Errors)
+			return report, err
+		}
+		report.Valid++
+	}
+	report.Errors = rb.Errors()
+	report.Rows = report.Valid + len(report.Errors)
+	return report, nil
+}
+
+// headerReport compares the header with the fields the Reader decodes, or
+// returns nil if they match
+func (rb *Reader[T]) headerReport() *HeaderError {
+	var report HeaderError
+	counts := make(map[string]int, len(rb.headers))
+	for i, header := range rb.headers {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue // blank columns, e.g. index gaps
+		}
+		if counts[header]++; counts[header] == 2 {
+			report.Duplicated = append(report.Duplicated, header)
+		}
+		_, mapped := rb.fieldMap[i]
+		if !mapped && counts[header] == 1 && !rb.opts.positional &&
+			!slices.ContainsFunc(rb.fields, func(fi fieldInfo) bool { return fi.hasColumn(header) }) {
+			report.Extra = append(report.Extra, header)
+		}
+	}
+	for i, column := range rb.fieldColumns() {
+		if column < 0 && rb.selected(rb.fields[i]) {
+			report.Missing = append(report.Missing, rb.fields[i].Name)
+		}
+	}
+	if len(report.Missing) == 0 && len(report.Extra) == 0 && len(report.Duplicated) == 0 {
+		return nil
+	}
+	return &report
+}
@@ -0,0 +1,108 @@
+package rowboat_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/notnil/rowboat"
+)
+
+func validatePerson(p Person) error {
+	if !strings.Contains(p.Email, "@") {
+		return errors.New("invalid email")
+	}
+	return nil
+}
+
+func TestReaderValidator(t *testing.T) {
+	csvData := `Name,Email,Age
+Alice,alice@example.com,30
+Bob,bob-at-example.com,25
+Charlie,charlie@example.com,35`
+
+	rb, err := rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithValidator(validatePerson))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	var rowErr *rowboat.RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 || rowErr.Err.Error() != "invalid email" {
+		t.Fatalf("Expected validation RowError on line 3, got %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 record before the failure, got %+v", results)
+	}
+
+	// Rejected rows count against the error budget
+	rb, err = rowboat.NewReader[Person](strings.NewReader(csvData), rowboat.WithValidator(validatePerson), rowboat.WithMaxErrors(1))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err = rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(results) != 2 || len(rb.Errors()) != 1 {
+		t.Errorf("Expected 2 records and 1 skipped error, got %+v and %v", results, rb.Errors())
+	}
+}
+
+func TestWriterValidator(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithValidator(validatePerson))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Bob", Email: "bob"}); err == nil {
+		t.Errorf("Expected validation error")
+	}
+	if err := writer.Flush(); err != nil || buf.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got %q (%v)", buf.String(), err)
+	}
+
+	// Validators must match the record type
+	if _, err := rowboat.NewWriter[ComplexRecord](&buf, rowboat.WithValidator(validatePerson)); err == nil {
+		t.Errorf("Expected error for validator of another record type")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	csvData := "Name,Age,Phone\nAlice,30,555\nBob,old,556\nCarol,41,557\n,22,558\n"
+	report, err := rowboat.Validate[Person](strings.NewReader(csvData), rowboat.WithValidator(func(p Person) error {
+		if p.Name == "" {
+			return errors.New("name is required")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Failed to validate: %v", err)
+	}
+	if report.OK() || report.Rows != 4 || report.Valid != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	expectedHeader := &rowboat.HeaderError{Missing: []string{"Email"}, Extra: []string{"Phone"}}
+	if !reflect.DeepEqual(report.Header, expectedHeader) {
+		t.Errorf("Expected: %+v\nGot: %+v", expectedHeader, report.Header)
+	}
+	var lines []int
+	for _, err := range report.Errors {
+		var rowErr *rowboat.RowError
+		if errors.As(err, &rowErr) {
+			lines = append(lines, rowErr.Line)
+		}
+	}
+	if expected := []int{3, 5}; !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, lines)
+	}
+
+	report, err = rowboat.Validate[Person](strings.NewReader("Name,Email,Age\nAlice,alice@example.com,30\n"))
+	if err != nil {
+		t.Fatalf("Failed to validate: %v", err)
+	}
+	if !report.OK() || report.Rows != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
@@ -1,15 +1,18 @@
 package rowboat
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"iter"
+	"math"
 	"reflect"
-	"sort"
+	"slices"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 )
 
@@ -18,65 +21,324 @@ type CSVMarshaler interface {
 	MarshalCSV() (string, error)
 }
 
-// fieldInfo contains information about a struct field for CSV writing
-type fieldInfo struct {
-	Index int
-	Name  string
-	Field reflect.StructField
+// RecordWriter is a destination for raw records. *csv.Writer implements
+// it, and other tabular formats can implement it to reuse rowboat's struct
+// mapping. The Writer reuses the record slice, so Write must not retain it.
+type RecordWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
 }
 
 // Writer struct holds the CSV writer and mapping information
 type Writer[T any] struct {
-	writer *csv.Writer
-	fields []fieldInfo
+	w        io.Writer
+	closer   io.Closer
+	writer   RecordWriter
+	opts     *options
+	fields   []fieldInfo
+	stats    *Stats
+	closed   bool
+	validate []func(T) error
+	codec    bool                // fields are encoded by the generated MarshalCSVRow
+	record   []string            // cells of the record being written
+	scratch  reflect.Value       // pointer to a copy of the struct being written
+	renamed  map[string]string   // output header by column name
+	rename   func(string) string // output header of other columns
+	columns  []int               // output column of each field
+	width    int                 // number of output columns, including gaps
+	sum      *bodyHash           // checksum of the rows written under WithChecksum
+	quoter   *quoteWriter        // record writer under quoting control, nil if unused
+	err      error               // first error of MustWrite
+	mu       *sync.Mutex         // serializes calls under WithConcurrentWrites, nil if unused
+	pending  int                 // records written since the last flush
+	buffered int                 // approximate bytes written since the last flush
+	rows     int                 // records written
+	written  int64               // approximate bytes of the records written
+	checked  bool                // every field has a way to be encoded
+}
+
+// WithConcurrentWrites makes the Writer safe for concurrent use, so that
+// many producer goroutines can fan in to one file. Each record is written
+// whole, in the order the calls acquire the Writer.
+func WithConcurrentWrites() Option {
+	return func(o *options) {
+		o.concurrent = true
+	}
+}
+
+// WithFlushEvery makes the Writer flush after every n records, so that
+// output reaches the underlying io.Writer in steady chunks
+func WithFlushEvery(n int) Option {
+	return func(o *options) {
+		o.flushRows = n
+	}
+}
+
+// WithFlushBytes makes the Writer flush once about n bytes of records are
+// buffered, e.g. to match the part size of a multipart upload
+func WithFlushBytes(n int) Option {
+	return func(o *options) {
+		o.flushBytes = n
+	}
 }
 
 // NewWriter creates a new RowBoat writer instance
-func NewWriter[T any](w io.Writer) (*Writer[T], error) {
-	rw := &Writer[T]{}
-	rw.writer = csv.NewWriter(w)
+func NewWriter[T any](w io.Writer, opts ...Option) (*Writer[T], error) {
+	rw := &Writer[T]{w: w, opts: newTypeOptions[T](opts)}
+	rw.sum = newBodyHash(rw.opts.checksum)
+	if rw.opts.concurrent {
+		rw.mu = new(sync.Mutex)
+	}
+	if rw.opts.gzip {
+		gz := gzip.NewWriter(w)
+		rw.w, rw.closer = gz, gz
+	}
+	rw.w = rw.opts.wrapOutput(rw.w)
+	csvWriter := csv.NewWriter(rw.w)
+	rw.opts.configureWriter(csvWriter)
+	rw.writer = csvWriter
 
 	// Analyze the struct fields
 	if err := rw.createFieldInfo(); err != nil {
 		return nil, err
 	}
+	if rw.opts.useQuoting(rw.fields) {
+		rw.quoter = newQuoteWriter(rw.w, rw.opts.delimiter, rw.opts.quoting, rw.opts.useCRLF)
+		rw.quoter.quote = quoteColumns(rw.fields, rw.columns, rw.width, rw.opts.quoting)
+		rw.writer = rw.quoter
+	}
 
 	return rw, nil
 }
 
+// FromCSVWriter creates a Writer encoding records into a configured
+// csv.Writer, keeping its settings such as Comma and UseCRLF. Options
+// setting the format, like WithDelimiter, have no effect, and compression
+// and schema fingerprints are not supported. Close flushes w without
+// closing its destination.
+func FromCSVWriter[T any](w *csv.Writer, opts ...Option) (*Writer[T], error) {
+	return NewRecordWriter[T](w, opts...)
+}
+
+// NewRecordWriter creates a Writer encoding records into dst. Options
+// acting on the raw byte stream, such as compression or the schema
+// fingerprint, are not supported.
+func NewRecordWriter[T any](dst RecordWriter, opts ...Option) (*Writer[T], error) {
+	rw := &Writer[T]{writer: dst, opts: newTypeOptions[T](opts)}
+	rw.sum = newBodyHash(rw.opts.checksum)
+	if rw.opts.concurrent {
+		rw.mu = new(sync.Mutex)
+	}
+	if rw.opts.gzip || rw.opts.schemaHash {
+		return nil, errors.New("compression and schema fingerprints require a byte stream")
+	}
+
+	// Analyze the struct fields
+	if err := rw.createFieldInfo(); err != nil {
+		return nil, err
+	}
+
+	return rw, nil
+}
+
+// WriteHeader writes the header row, preceded by the schema fingerprint
+// comment when WithSchemaHash is set. Like Write, it buffers its output
+// until Flush or Close.
 func (rw *Writer[T]) WriteHeader() error {
-	headers := make([]string, len(rw.fields))
-	for i, fi := range rw.fields {
-		headers[i] = fi.Name
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+	}
+	if rw.opts.schemaHash {
+		if err := writeSchemaComment(rw.w, rw.fields); err != nil {
+			return err
+		}
+	}
+	headers := rw.Headers()
+	if rw.quoter != nil {
+		rw.quoter.header = true
 	}
 	if err := rw.writer.Write(headers); err != nil {
 		return err
 	}
-	rw.writer.Flush()
+	if rw.opts.typeRow {
+		types := make([]string, rw.width)
+		for i := range types {
+			types[i] = String.String()
+		}
+		for i, fi := range rw.fields {
+			types[rw.columns[i]] = fieldColumn(fi, rw.opts).typeToken()
+		}
+		if err := rw.writer.Write(types); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// Write writes a single record to the CSV writer
-func (rw *Writer[T]) Write(record T) error {
-	recordValues := make([]string, len(rw.fields))
-	v := reflect.ValueOf(record)
+// Headers returns the header row in output order, reflecting column
+// selection and renaming
+func (rw *Writer[T]) Headers() []string {
+	headers := make([]string, rw.width)
 	for i, fi := range rw.fields {
-		fieldValue := v.FieldByName(fi.Field.Name)
-		strValue, err := getFieldStringValue(fieldValue)
+		headers[rw.columns[i]] = rw.header(fi)
+	}
+	return headers
+}
+
+// Write writes a single record to the CSV writer. Records are buffered;
+// call Flush or Close to write them to the underlying io.Writer.
+func (rw *Writer[T]) Write(record T) error {
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+	}
+	return rw.write(record)
+}
+
+// write encodes and writes a record
+func (rw *Writer[T]) write(record T) error {
+	if !rw.checked {
+		// Checked on the first write, so that RegisterConverter can map
+		// unsupported fields first
+		if err := checkMappable(rw.fields, true); err != nil {
+			return err
+		}
+		rw.checked = true
+	}
+	if err := validateRecord(rw.validate, record); err != nil {
+		return err
+	}
+	// Reuse the record and a copy of the value across calls; record
+	// writers must not retain the record
+	if len(rw.record) != rw.width {
+		rw.record = make([]string, rw.width)
+	}
+	if !rw.scratch.IsValid() {
+		rw.scratch = reflect.New(recordType[T]())
+	}
+	if ptr, ok := rw.scratch.Interface().(*T); ok {
+		*ptr = record
+	} else {
+		v, err := structValue(reflect.ValueOf(record))
 		if err != nil {
-			return fmt.Errorf("error marshaling field %s: %w", fi.Field.Name, err)
+			return err
 		}
-		recordValues[i] = strValue
+		rw.scratch.Elem().Set(v)
+	}
+	if err := beforeEncode(rw.scratch); err != nil {
+		return err
 	}
+	v := rw.scratch.Elem()
 
-	if err := rw.writer.Write(recordValues); err != nil {
+	if rw.codec {
+		clear(rw.record)
+		if err := rw.scratch.Interface().(RowMarshaler).MarshalCSVRow(rw.record); err != nil {
+			return err
+		}
+		if rw.opts.formulaPrefix != "" {
+			for i, cell := range rw.record {
+				rw.record[i] = escapeFormula(cell, rw.opts.formulaPrefix)
+			}
+		}
+	} else {
+		for i, fi := range rw.fields {
+			// Fields promoted through a nil embedded pointer are null
+			strValue, err, null := rw.opts.nullString, error(nil), true
+			if fieldValue, ok := fi.value(v); ok {
+				strValue, err = getFieldStringValue(fieldValue, fi, rw.opts)
+				null = isNull(fieldValue, fi)
+			}
+			if err != nil {
+				return fmt.Errorf("error marshaling field %s: %w", fi.Field.Name, err)
+			}
+			// Null markers are written as is, neither masked nor encrypted
+			if fi.Mask != nil && !null {
+				strValue = fi.Mask(strValue)
+			}
+			// Ciphertexts are not escaped, so that they still decrypt
+			if fi.Encrypt && strValue != "" && !null {
+				if strValue, err = rw.opts.encrypt(fi.Name, strValue); err != nil {
+					return fmt.Errorf("error encrypting field %s: %w", fi.Field.Name, err)
+				}
+			} else if rw.opts.formulaPrefix != "" {
+				strValue = escapeFormula(strValue, rw.opts.formulaPrefix)
+			}
+			rw.record[rw.columns[i]] = strValue
+		}
+	}
+
+	if err := rw.writer.Write(rw.record); err != nil {
 		return err
 	}
-	rw.writer.Flush()
+	if rw.sum != nil {
+		rw.sum.add(rw.record)
+	}
+	if rw.stats != nil {
+		rw.stats.add(v, rw.fields, rw.record, rw.columns)
+	}
+	size := recordSize(rw.record)
+	rw.rows++
+	rw.written += int64(size)
+	return rw.autoFlush(size)
+}
+
+// recordSize approximates the bytes a record takes in the output
+func recordSize(record []string) int {
+	size := 0
+	for _, cell := range record {
+		size += len(cell) + 1 // the delimiter or line ending
+	}
+	return size
+}
+
+// autoFlush flushes after a record of the given size was written once the
+// WithFlushEvery or WithFlushBytes threshold is reached
+func (rw *Writer[T]) autoFlush(size int) error {
+	if rw.opts.flushRows <= 0 && rw.opts.flushBytes <= 0 {
+		return nil
+	}
+	rw.pending++
+	rw.buffered += size
+	if (rw.opts.flushRows > 0 && rw.pending >= rw.opts.flushRows) ||
+		(rw.opts.flushBytes > 0 && rw.buffered >= rw.opts.flushBytes) {
+		return rw.flush()
+	}
+	return nil
+}
+
+// WriteBatch writes records without interleaving records of concurrent
+// writers under WithConcurrentWrites. Unlike WriteSlice, it only flushes as
+// configured by WithFlushEvery and WithFlushBytes.
+func (rw *Writer[T]) WriteBatch(records []T) error {
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+	}
+	for _, record := range records {
+		if err := rw.write(record); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// WriteAll writes multiple records from an iterator
+// MustWrite writes a record like Write without returning an error, for
+// tight loops feeding buffered destinations such as multipart uploads.
+// After the first error it writes nothing more; the error is reported by
+// Error, Flush and Close.
+func (rw *Writer[T]) MustWrite(record T) {
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+	}
+	if rw.err == nil {
+		rw.err = rw.write(record)
+	}
+}
+
+// WriteAll writes multiple records from an iterator and flushes them
 func (rw *Writer[T]) WriteAll(records iter.Seq[T]) error {
 	var err error
 	records(func(record T) bool {
@@ -85,77 +347,297 @@ func (rw *Writer[T]) WriteAll(records iter.Seq[T]) error {
 		}
 		return true
 	})
+	if err != nil {
+		return err
+	}
+	return rw.Flush()
+}
+
+// WriteSlice writes all records of a slice and flushes them
+func (rw *Writer[T]) WriteSlice(records []T) error {
+	return rw.WriteAll(slices.Values(records))
+}
+
+// WriteAllContext writes multiple records from an iterator like WriteAll,
+// stopping with the context's error once ctx is done. Records written
+// before cancellation are flushed.
+func (rw *Writer[T]) WriteAllContext(ctx context.Context, records iter.Seq[T]) error {
+	var err error
+	records(func(record T) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		if err = rw.Write(record); err != nil {
+			return false
+		}
+		return true
+	})
+	if flushErr := rw.Flush(); err == nil {
+		err = flushErr
+	}
 	return err
 }
 
-// createFieldInfo extracts information about struct fields, including indexes
-func (rw *Writer[T]) createFieldInfo() error {
-	var t T
-	tType := reflect.TypeOf(t)
-	if tType.Kind() != reflect.Struct {
-		return errors.New("generic type T must be a struct")
+// Flush writes any buffered records to the underlying io.Writer and
+// returns the first write error, such as a full disk
+func (rw *Writer[T]) Flush() error {
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
 	}
+	return rw.flush()
+}
+
+// flush writes any buffered records and returns the first error
+func (rw *Writer[T]) flush() error {
+	rw.pending, rw.buffered = 0, 0
+	rw.writer.Flush()
+	return rw.error()
+}
 
-	fields := make([]fieldInfo, 0, tType.NumField())
-	maxIndex := -1
+// Error returns the first error that occurred while writing or flushing
+// buffered records
+func (rw *Writer[T]) Error() error {
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+	}
+	return rw.error()
+}
 
-	for i := 0; i < tType.NumField(); i++ {
-		field := tType.Field(i)
-		csvTag := field.Tag.Get("csv")
-		if csvTag == "-" {
-			continue // skip field
+// error returns the first error that occurred while writing or flushing
+func (rw *Writer[T]) error() error {
+	if rw.err != nil {
+		return rw.err
+	}
+	return rw.writer.Error()
+}
+
+// Close writes the footer row when WithFooter is set and the checksum
+// trailer when WithChecksum is set, flushes any buffered
+// data and terminates the compressed stream when WithGzip is set. It does
+// not close the underlying io.Writer.
+func (rw *Writer[T]) Close() error {
+	if rw.mu != nil {
+		rw.mu.Lock()
+		defer rw.mu.Unlock()
+	}
+	if rw.closed {
+		return nil
+	}
+	rw.closed = true
+	if rw.opts.footer != nil {
+		footer := rw.opts.footer(*rw.stats)
+		if err := rw.writer.Write(footer); err != nil {
+			return err
+		}
+		if rw.sum != nil {
+			rw.sum.add(footer)
 		}
+	}
+	if rw.sum != nil {
+		if err := rw.writer.Write(rw.sum.trailer()); err != nil {
+			return err
+		}
+	}
+	err := rw.flush()
+	if rw.closer != nil {
+		if closeErr := rw.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
 
-		name := field.Name
-		index := i // default index is the field order
-		tagParts := strings.Split(csvTag, ",")
-		if len(tagParts) > 0 && tagParts[0] != "" {
-			name = tagParts[0]
+// RegisterConverter attaches a conversion function to the field identified
+// by its Go field name or CSV column name. The converter receives the field
+// value and replaces the built-in encoding for that field.
+func (rw *Writer[T]) RegisterConverter(fieldName string, fn func(any) (string, error)) error {
+	found := false
+	for i := range rw.fields {
+		if rw.fields[i].matches(fieldName) {
+			rw.fields[i].Encode = fn
+			found = true
 		}
+	}
+	if !found {
+		return fmt.Errorf("unknown field '%s'", fieldName)
+	}
+	rw.codec = false
+	return nil
+}
 
-		for _, part := range tagParts[1:] {
-			part = strings.TrimSpace(part)
-			if strings.HasPrefix(part, "index=") {
-				idxStr := strings.TrimPrefix(part, "index=")
-				idx, err := strconv.Atoi(idxStr)
-				if err != nil {
-					return fmt.Errorf("invalid index value '%s' in field '%s': %v", idxStr, field.Name, err)
-				}
-				index = idx
-				if index > maxIndex {
-					maxIndex = index
-				}
-			}
+// WithColumns narrows the output to the named columns, identified by CSV
+// column name or Go field name, in the given order. Call it before
+// WriteHeader.
+func (rw *Writer[T]) WithColumns(columns ...string) error {
+	fields := make([]fieldInfo, 0, len(columns))
+	for _, name := range columns {
+		i := slices.IndexFunc(rw.fields, func(fi fieldInfo) bool { return fi.matches(name) })
+		if i < 0 {
+			return fmt.Errorf("unknown column '%s'", name)
 		}
+		fields = append(fields, rw.fields[i])
+	}
+	rw.setFields(fields, false)
+	return nil
+}
 
-		fields = append(fields, fieldInfo{
-			Index: index,
-			Name:  name,
-			Field: field,
-		})
+// OmitColumns removes the named columns, identified by CSV column name or
+// Go field name, from the output. Call it before WriteHeader.
+func (rw *Writer[T]) OmitColumns(columns ...string) error {
+	for _, name := range columns {
+		if !slices.ContainsFunc(rw.fields, func(fi fieldInfo) bool { return fi.matches(name) }) {
+			return fmt.Errorf("unknown column '%s'", name)
+		}
 	}
+	rw.setFields(slices.DeleteFunc(slices.Clone(rw.fields), func(fi fieldInfo) bool {
+		return slices.ContainsFunc(columns, fi.matches)
+	}), false)
+	return nil
+}
 
-	// Assign indexes to fields without an explicit index, starting from maxIndex+1
-	nextIndex := maxIndex + 1
-	for i := range fields {
-		if fields[i].Index == fields[i].Field.Index[0] { // Field's default index
-			fields[i].Index = nextIndex
-			nextIndex++
+// WithHeaderNames renames output headers without changing the struct tags
+// used for reading. Keys are CSV column names or Go field names. Call it
+// before WriteHeader.
+func (rw *Writer[T]) WithHeaderNames(names map[string]string) error {
+	renamed := make(map[string]string, len(names))
+	for name, header := range names {
+		i := slices.IndexFunc(rw.fields, func(fi fieldInfo) bool { return fi.matches(name) })
+		if i < 0 {
+			return fmt.Errorf("unknown column '%s'", name)
 		}
+		renamed[rw.fields[i].Name] = header
 	}
+	rw.renamed = renamed
+	return nil
+}
 
-	// Sort the fields based on the index
-	sort.Slice(fields, func(i, j int) bool {
-		return fields[i].Index < fields[j].Index
-	})
+// WithHeaderFunc renames every output header not named by WithHeaderNames
+// with fn, which receives the CSV column name. Call it before WriteHeader.
+func (rw *Writer[T]) WithHeaderFunc(fn func(column string) string) {
+	rw.rename = fn
+}
+
+// header returns the output header of a field
+func (rw *Writer[T]) header(fi fieldInfo) string {
+	if header, ok := rw.renamed[fi.Name]; ok {
+		return header
+	}
+	if rw.rename != nil {
+		return rw.rename(fi.Name)
+	}
+	return fi.Name
+}
 
+// setFields replaces the written fields and resets the footer statistics.
+// With gaps, fields are written in the column at their index, leaving
+// columns without a field blank; otherwise they are written side by side.
+func (rw *Writer[T]) setFields(fields []fieldInfo, gaps bool) {
 	rw.fields = fields
+	rw.columns = make([]int, len(fields))
+	for i, fi := range fields {
+		rw.columns[i] = i
+		if gaps {
+			rw.columns[i] = fi.Index
+		}
+	}
+	rw.width = len(fields)
+	if gaps && len(fields) > 0 {
+		rw.width = fields[len(fields)-1].Index + 1
+	}
+	rw.codec = rw.width == len(fields) && rw.useCodec(fields)
+	if rw.quoter != nil {
+		rw.quoter.quote = quoteColumns(fields, rw.columns, rw.width, rw.opts.quoting)
+	}
+	if rw.opts.footer != nil {
+		rw.stats = newStats(fields, false, rw.opts)
+	}
+}
+
+// createFieldInfo extracts information about struct fields, including indexes
+func (rw *Writer[T]) createFieldInfo() error {
+	fields, err := cachedFields(recordType[T]())
+	if err != nil {
+		return err
+	}
+	if err := rw.opts.applyLocale(fields); err != nil {
+		return err
+	}
+	if rw.validate, err = recordValidators[T](rw.opts); err != nil {
+		return err
+	}
+	if err := rw.opts.checkCipher(fields); err != nil {
+		return err
+	}
+	for name, mask := range rw.opts.maskers {
+		found := false
+		for i := range fields {
+			if fields[i].matches(name) {
+				fields[i].Mask = mask
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown field '%s'", name)
+		}
+	}
+	rw.setFields(fields, true)
 	return nil
 }
 
-// getFieldStringValue converts a struct field value to string for CSV
-func getFieldStringValue(field reflect.Value) (string, error) {
-	csvMarshalerType := reflect.TypeOf((*CSVMarshaler)(nil)).Elem()
+// csvMarshalerType is the reflect.Type of the CSVMarshaler interface
+var csvMarshalerType = reflect.TypeOf((*CSVMarshaler)(nil)).Elem()
+
+// getFieldStringValue converts a struct field value to string for CSV,
+// checking the values allowed by the enum tag option. Nil pointers are
+// written as null whatever values are allowed.
+func getFieldStringValue(field reflect.Value, fi fieldInfo, opts *options) (string, error) {
+	s, err := formatFieldValue(field, fi, opts)
+	if err == nil && !isNull(field, fi) {
+		err = fi.checkEnum(s, opts)
+	}
+	return s, err
+}
+
+// isNull reports whether a field is written as null: a nil pointer without
+// a converter of its own
+func isNull(field reflect.Value, fi fieldInfo) bool {
+	return field.Kind() == reflect.Pointer && field.IsNil() && fi.Encode == nil
+}
+
+// formatFieldValue converts a struct field value to string for CSV
+func formatFieldValue(field reflect.Value, fi fieldInfo, opts *options) (string, error) {
+	// Write zero values as empty cells
+	if fi.omitEmpty(opts) && field.IsZero() {
+		return "", nil
+	}
+
+	// Write nil pointers as null and others as the value they point to
+	if field.Kind() == reflect.Pointer && fi.Encode == nil {
+		if field.IsNil() {
+			return opts.nullString, nil
+		}
+		return formatFieldValue(field.Elem(), fi, opts)
+	}
+
+	// Write raw cells verbatim
+	if fi.Raw {
+		if field.Kind() == reflect.String {
+			return field.String(), nil
+		}
+		return string(field.Bytes()), nil
+	}
+
+	// Use a converter registered at runtime
+	if fi.Encode != nil {
+		return fi.Encode(field.Interface())
+	}
+
+	// Format decimals like other numbers, before their MarshalCSV method
+	if field.Type() == decimalType {
+		return encodeDecimal(field, fi), nil
+	}
 
 	// Check if the field implements CSVMarshaler
 	if field.CanInterface() && field.Type().Implements(csvMarshalerType) {
@@ -169,10 +651,23 @@ func getFieldStringValue(field reflect.Value) (string, error) {
 		return marshaler.MarshalCSV()
 	}
 
+	// Use the names of registered enums
+	if m := lookupEnum(field.Type()); m != nil {
+		return m.encode(field)
+	}
+
+	// Handle ISO 8601 durations and week dates
+	switch fi.Format {
+	case tagISODuration:
+		return formatISODuration(time.Duration(field.Int())), nil
+	case tagISOWeek:
+		return formatISOWeek(field.Interface().(time.Time)), nil
+	}
+
 	// Handle specific types like time.Time
 	if field.Type() == reflect.TypeOf(time.Time{}) {
 		t := field.Interface().(time.Time)
-		return t.Format(time.RFC3339), nil
+		return t.Format(fi.timeLayout(opts)), nil
 	}
 
 	// Handle basic kinds
@@ -180,11 +675,35 @@ func getFieldStringValue(field reflect.Value) (string, error) {
 	case reflect.String:
 		return field.String(), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return strconv.FormatInt(field.Int(), 10), nil
+		return fi.padNumber(strconv.FormatInt(field.Int(), 10)), nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return strconv.FormatUint(field.Uint(), 10), nil
+		return fi.padNumber(strconv.FormatUint(field.Uint(), 10)), nil
 	case reflect.Float32, reflect.Float64:
-		return strconv.FormatFloat(field.Float(), 'f', -1, 64), nil
+		f := field.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			switch opts.nonFinite {
+			case NonFiniteEmpty:
+				return "", nil
+			case NonFiniteError:
+				return "", fmt.Errorf("non-finite value %v not allowed", f)
+			}
+		}
+		// Format float32 values at their own precision, so 3.14 is not
+		// written as 3.140000104904175
+		bitSize := field.Type().Bits()
+		var s string
+		switch {
+		case fi.FloatFmt != "" && bitSize == 32:
+			s = fmt.Sprintf(fi.FloatFmt, float32(f))
+		case fi.FloatFmt != "":
+			s = fmt.Sprintf(fi.FloatFmt, f)
+		default:
+			s = strconv.FormatFloat(f, 'f', fi.Prec, bitSize)
+		}
+		if fi.Locale != nil {
+			s = fi.Locale.formatNumber(s)
+		}
+		return s, nil
 	case reflect.Bool:
 		return strconv.FormatBool(field.Bool()), nil
 	default:
@@ -2,10 +2,16 @@ package rowboat_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
 	"iter"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -39,6 +45,9 @@ func TestWriter(t *testing.T) {
 			t.Fatalf("Failed to write record: %v", err)
 		}
 	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush Writer: %v", err)
+	}
 
 	// Get the written CSV data
 	csvData := buf.String()
@@ -99,6 +108,9 @@ func TestComplexWriter(t *testing.T) {
 			t.Fatalf("Failed to write record: %v", err)
 		}
 	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush Writer: %v", err)
+	}
 
 	// Read back and verify
 	reader := strings.NewReader(buf.String())
@@ -259,6 +271,9 @@ func TestWriterWithIndexing(t *testing.T) {
 			t.Fatalf("Failed to write record: %v", err)
 		}
 	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush Writer: %v", err)
+	}
 
 	// Expected CSV output
 	expectedCSV := `Age,Name,Email
@@ -296,3 +311,719 @@ func TestWriterWithIndexing(t *testing.T) {
 		t.Errorf("Written and read results do not match expected.\nExpected: %+v\nGot: %+v", people, readPeople)
 	}
 }
+
+func TestWriterRegisterConverter(t *testing.T) {
+	people := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+
+	if err := writer.RegisterConverter("Email", func(v any) (string, error) {
+		return strings.ToUpper(v.(string)), nil
+	}); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	if err := writer.RegisterConverter("Missing", func(v any) (string, error) { return "", nil }); err == nil {
+		t.Errorf("Expected error registering converter for unknown field")
+	}
+
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(people)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	expected := "Name,Email,Age\nAlice,ALICE@EXAMPLE.COM,30\nBob,BOB@EXAMPLE.COM,25\n"
+	if buf.String() != expected {
+		t.Errorf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriterFlushError(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Alice", Age: 30}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("Expected Write to buffer the record, got %q", buf.String())
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush Writer: %v", err)
+	}
+	if want := "Alice,,30\n"; buf.String() != want {
+		t.Fatalf("Expected: %q\nGot: %q", want, buf.String())
+	}
+
+	writer, err = rowboat.NewWriter[Person](failingWriter{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Alice", Age: 30}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	if err := writer.Flush(); err == nil || err.Error() != "disk full" {
+		t.Fatalf("Expected disk full error from Flush, got %v", err)
+	}
+	if err := writer.Error(); err == nil {
+		t.Errorf("Expected Error to report the write failure")
+	}
+	if err := writer.Close(); err == nil {
+		t.Errorf("Expected Close to report the write failure")
+	}
+}
+
+func TestWriterMustWrite(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithValidator(func(p Person) error {
+		if p.Age < 0 {
+			return errors.New("negative age")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	writer.MustWrite(Person{Name: "Alice", Age: 30})
+	writer.MustWrite(Person{Name: "Bob", Age: -1})
+	writer.MustWrite(Person{Name: "Carol", Age: 41})
+	if buf.Len() != 0 {
+		t.Fatalf("Expected MustWrite to buffer the records, got %q", buf.String())
+	}
+	if err := writer.Close(); err == nil || !strings.Contains(err.Error(), "negative age") {
+		t.Fatalf("Expected Close to report the validation error, got %v", err)
+	}
+	if err := writer.Error(); err == nil {
+		t.Errorf("Expected Error to report the validation error")
+	}
+	if want := "Alice,,30\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestPadTag(t *testing.T) {
+	type Account struct {
+		Code   int    `csv:"code,pad=5"`
+		Region uint8  `csv:"region,pad=3"`
+		Delta  int    `csv:"delta,pad=4"`
+		Name   string `csv:"name"`
+	}
+	accounts := []Account{
+		{Code: 42, Region: 7, Delta: -12, Name: "Alice"},
+		{Code: 1234567, Region: 255, Delta: 3, Name: "Bob"},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Account](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(accounts)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	expectedCSV := `code,region,delta,name
+00042,007,-0012,Alice
+1234567,255,0003,Bob
+`
+	if buf.String() != expectedCSV {
+		t.Fatalf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+
+	rb, err := rowboat.NewReader[Account](strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, accounts) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", accounts, results)
+	}
+
+	type Invalid struct {
+		Name string `csv:"name,pad=5"`
+	}
+	if _, err := rowboat.NewWriter[Invalid](&buf); err == nil {
+		t.Errorf("Expected error for pad tag on a string field")
+	}
+}
+
+func TestWriteSlice(t *testing.T) {
+	people := []Person{
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(people); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "Alice,alice@example.com,30\nBob,bob@example.com,25\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestWriteAllContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel after the second record has been produced
+	records := func(yield func(Person) bool) {
+		for i := 0; i < 5; i++ {
+			if i == 2 {
+				cancel()
+			}
+			if !yield(Person{Name: strconv.Itoa(i)}) {
+				return
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteAllContext(ctx, records); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if want := "0,,0\n1,,0\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestWriterColumnSelection(t *testing.T) {
+	people := []Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}
+
+	tests := []struct {
+		name     string
+		narrow   func(*rowboat.Writer[Person]) error
+		expected string
+	}{
+		{
+			name:     "with",
+			narrow:   func(w *rowboat.Writer[Person]) error { return w.WithColumns("Age", "Name") },
+			expected: "Age,Name\n30,Alice\n",
+		},
+		{
+			name:     "omit",
+			narrow:   func(w *rowboat.Writer[Person]) error { return w.OmitColumns("Email") },
+			expected: "Name,Age\nAlice,30\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := rowboat.NewWriter[Person](&buf)
+			if err != nil {
+				t.Fatalf("Failed to create Writer: %v", err)
+			}
+			if err := tt.narrow(writer); err != nil {
+				t.Fatalf("Failed to select columns: %v", err)
+			}
+			if err := writer.WriteHeader(); err != nil {
+				t.Fatalf("Failed to write header: %v", err)
+			}
+			if err := writer.WriteSlice(people); err != nil {
+				t.Fatalf("Failed to write records: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+
+	writer, err := rowboat.NewWriter[Person](&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.OmitColumns("Phone"); err == nil {
+		t.Errorf("Expected error for unknown column")
+	}
+}
+
+func TestWriterHeaderNames(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WithHeaderNames(map[string]string{"Email": "E-mail Address"}); err != nil {
+		t.Fatalf("Failed to rename headers: %v", err)
+	}
+	writer.WithHeaderFunc(strings.ToUpper)
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(Person{Name: "Alice", Email: "alice@example.com", Age: 30}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	writer.Flush()
+
+	expected := "NAME,E-mail Address,AGE\nAlice,alice@example.com,30\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+	if err := writer.WithHeaderNames(map[string]string{"Phone": "Tel"}); err == nil {
+		t.Errorf("Expected error for unknown column")
+	}
+}
+
+func TestOmitEmpty(t *testing.T) {
+	type Note struct {
+		ID      int       `csv:"id"`
+		Count   int       `csv:"count,omitempty"`
+		Done    bool      `csv:"done,omitempty"`
+		Created time.Time `csv:"created,omitempty"`
+	}
+	notes := []Note{
+		{ID: 1},
+		{ID: 0, Count: 3, Done: true, Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Note](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(notes); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	expectedCSV := `id,count,done,created
+1,,,
+0,3,true,2024-01-02T03:04:05Z
+`
+	if buf.String() != expectedCSV {
+		t.Fatalf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+
+	// Empty cells read back as zero values
+	rb, err := rowboat.NewReader[Note](strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(results, notes) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", notes, results)
+	}
+
+	// The writer-wide option applies to every field
+	buf.Reset()
+	writer, err = rowboat.NewWriter[Note](&buf, rowboat.WithOmitEmpty())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(notes[:1]); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "1,,,\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestFloatFormatTags(t *testing.T) {
+	type Quote struct {
+		Price float64 `csv:"price,prec=2"`
+		Rate  float64 `csv:"rate,fmt=%.4f"`
+		Ratio float64 `csv:"ratio,fmt=%.1e"`
+		Euro  float64 `csv:"euro,prec=2,locale=de"`
+	}
+	quotes := []Quote{{Price: 10, Rate: 0.12345678, Ratio: 1234.5, Euro: 3.456}}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Quote](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteSlice(quotes); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if want := "10.00,0.1235,1.2e+03,\"3,46\"\n"; buf.String() != want {
+		t.Errorf("Expected: %q\nGot: %q", want, buf.String())
+	}
+
+	type BadPrec struct {
+		Count int `csv:"count,prec=2"`
+	}
+	if _, err := rowboat.NewWriter[BadPrec](&buf); err == nil {
+		t.Errorf("Expected error for prec tag on an int field")
+	}
+	type BadFmt struct {
+		Rate float64 `csv:"rate,fmt=%d"`
+	}
+	if _, err := rowboat.NewWriter[BadFmt](&buf); err == nil {
+		t.Errorf("Expected error for fmt tag with a non-float verb")
+	}
+}
+
+func TestFloat32RoundTrip(t *testing.T) {
+	type Measurement struct {
+		Small  float32 `csv:"small"`
+		Large  float64 `csv:"large"`
+		Fixed  float32 `csv:"fixed,prec=3"`
+		Format float32 `csv:"format,fmt=%g"`
+	}
+	measurements := []Measurement{
+		{Small: 3.14, Large: 3.14, Fixed: 2.5, Format: 0.1},
+		{Small: 1e-7, Large: 1e-7, Fixed: -1.125, Format: 16777216},
+	}
+
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Measurement](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice(measurements); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+
+	expectedCSV := `small,large,fixed,format
+3.14,3.14,2.500,0.1
+0.0000001,0.0000001,-1.125,1.6777216e+07
+`
+	if buf.String() != expectedCSV {
+		t.Fatalf("Written CSV does not match expected.\nExpected:\n%s\nGot:\n%s", expectedCSV, buf.String())
+	}
+
+	rb, err := rowboat.NewReader[Measurement](strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results, err := rb.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if !reflect.DeepEqual(results, measurements) {
+		t.Errorf("Parsed results do not match expected.\nExpected: %+v\nGot: %+v", measurements, results)
+	}
+}
+
+func TestFromCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.Comma = '|'
+	cw.UseCRLF = true
+	writer, err := rowboat.FromCSVWriter[Person](cw)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteSlice([]Person{{Name: "Alice", Email: "alice@example.com", Age: 30}}); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+	if expected := "Name|Email|Age\r\nAlice|alice@example.com|30\r\n"; buf.String() != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, buf.String())
+	}
+}
+
+func BenchmarkWriter(b *testing.B) {
+	record := ComplexRecord{
+		Name:      "Alice",
+		CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		Active:    true,
+		Score:     98.6,
+		Count:     1234,
+		Rate:      0.5,
+		Tags:      "a;b",
+	}
+	writer, err := rowboat.NewWriter[ComplexRecord](io.Discard)
+	if err != nil {
+		b.Fatalf("Failed to create Writer: %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := writer.Write(record); err != nil {
+			b.Fatalf("Failed to write record: %v", err)
+		}
+	}
+	writer.Flush()
+}
+
+func BenchmarkWriterCodec(b *testing.B) {
+	parcel := Parcel{Ref: "A1", ID: 1, Weight: 1.5, Qty: 3, Shipped: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)}
+	b.Run("reflection", func(b *testing.B) {
+		benchmarkWrite(b, plainParcel(parcel))
+	})
+	b.Run("generated", func(b *testing.B) {
+		benchmarkWrite(b, parcel)
+	})
+}
+
+// benchmarkWrite measures writing record to a discarding Writer
+func benchmarkWrite[T any](b *testing.B, record T) {
+	writer, err := rowboat.NewWriter[T](io.Discard)
+	if err != nil {
+		b.Fatalf("Failed to create Writer: %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := writer.Write(record); err != nil {
+			b.Fatalf("Failed to write record: %v", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		b.Fatalf("Failed to flush Writer: %v", err)
+	}
+}
+
+func TestWriterEmbeddedStruct(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Account](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	account := Account{
+		Timestamps: Timestamps{
+			CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			UpdatedAt: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+		},
+		Audit: Audit{Version: 7},
+		ID:    1,
+		Email: "alice@example.com",
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.Write(account); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	writer.Flush()
+
+	expected := `created_at,updated_at,id,email
+2024-01-02T03:04:05Z,2024-02-03T04:05:06Z,1,alice@example.com
+`
+	if buf.String() != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+	}
+}
+
+func TestWriterHeaders(t *testing.T) {
+	writer, err := rowboat.NewWriter[Person](&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if want := []string{"Name", "Email", "Age"}; !slices.Equal(writer.Headers(), want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, writer.Headers())
+	}
+	if err := writer.OmitColumns("Name"); err != nil {
+		t.Fatalf("Failed to omit columns: %v", err)
+	}
+	if err := writer.WithHeaderNames(map[string]string{"Age": "Years"}); err != nil {
+		t.Fatalf("Failed to rename headers: %v", err)
+	}
+	if want := []string{"Email", "Years"}; !slices.Equal(writer.Headers(), want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, writer.Headers())
+	}
+}
+
+func TestIndexRules(t *testing.T) {
+	// Unindexed fields take the lowest free columns in declaration order
+	type Mixed struct {
+		A string `csv:"a,index=3"`
+		B string `csv:"b"`
+		C string `csv:"c,index=0"`
+		D string `csv:"d"`
+	}
+	writer, err := rowboat.NewWriter[Mixed](&bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if want := []string{"c", "b", "d", "a"}; !slices.Equal(writer.Headers(), want) {
+		t.Errorf("Expected: %+v\nGot: %+v", want, writer.Headers())
+	}
+
+	// Gaps are written as blank columns and skipped on read
+	type Sparse struct {
+		X string `csv:"x,index=0"`
+		Y string `csv:"y,index=2"`
+		Z string `csv:"z,index=5"`
+	}
+	var buf bytes.Buffer
+	writer2, err := rowboat.NewWriter[Sparse](&buf, rowboat.WithQuoting(rowboat.QuoteStrings))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer2.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer2.Write(Sparse{X: "1", Y: "2", Z: "3"}); err != nil {
+		t.Fatalf("Failed to write record: %v", err)
+	}
+	writer2.Flush()
+	expected := "\"x\",\"\",\"y\",\"\",\"\",\"z\"\n\"1\",,\"2\",,,\"3\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected: %+v\nGot: %+v", expected, buf.String())
+	}
+	for _, opt := range []rowboat.Option{rowboat.WithDuplicateHeaders(rowboat.DuplicateError), rowboat.WithPositional()} {
+		rb, err := rowboat.NewReader[Sparse](strings.NewReader(buf.String()), opt)
+		if err != nil {
+			t.Fatalf("Failed to create Reader: %v", err)
+		}
+		got, err := rb.ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read records: %v", err)
+		}
+		if want := []Sparse{{"1", "2", "3"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected: %+v\nGot: %+v", want, got)
+		}
+	}
+
+}
+
+func TestDuplicateIndex(t *testing.T) {
+	type Colliding struct {
+		ID    int    `csv:"id,index=0"`
+		Name  string `csv:"name,index=1"`
+		Email string `csv:"email,index=1"`
+	}
+	_, err := rowboat.NewWriter[Colliding](&bytes.Buffer{})
+	if !errors.Is(err, rowboat.ErrDuplicateIndex) {
+		t.Fatalf("Expected ErrDuplicateIndex, got %v", err)
+	}
+	if want := "fields 'Name' and 'Email' both have index 1"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error containing %q, got %q", want, err.Error())
+	}
+	if _, err := rowboat.NewReader[Colliding](strings.NewReader("id,name,email\n")); !errors.Is(err, rowboat.ErrDuplicateIndex) {
+		t.Errorf("Expected ErrDuplicateIndex, got %v", err)
+	}
+
+	src := "package models\n\ntype Colliding struct {\n\tName  string `csv:\"name,index=1\"`\n\tEmail string `csv:\"email,index=1\"`\n}\n"
+	if _, err := rowboat.GenerateCodecs([]byte(src)); !errors.Is(err, rowboat.ErrDuplicateIndex) {
+		t.Errorf("Expected ErrDuplicateIndex, got %v", err)
+	}
+}
+
+func TestWriterConcurrentWrites(t *testing.T) {
+	const producers, perProducer = 8, 500
+	var buf bytes.Buffer
+	writer, err := rowboat.NewWriter[Person](&buf, rowboat.WithConcurrentWrites())
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perProducer {
+				name := strings.Repeat(string(rune('a'+p)), 50)
+				if err := writer.Write(Person{Name: name, Email: name + "@example.com", Age: i}); err != nil {
+					t.Errorf("Failed to write record: %v", err)
+					return
+				}
+				if i%100 == 0 {
+					writer.Flush()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	reader, err := rowboat.NewReader[Person](&buf)
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	got, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read records: %v", err)
+	}
+	if len(got) != producers*perProducer {
+		t.Fatalf("Expected: %+v\nGot: %+v", producers*perProducer, len(got))
+	}
+	for _, p := range got {
+		if p.Email != p.Name+"@example.com" {
+			t.Fatalf("Expected whole records, got %+v", p)
+		}
+	}
+}
+
+// writeCounter counts the Write calls reaching it
+type writeCounter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestWriterFlushEvery(t *testing.T) {
+	people := make([]Person, 10)
+	for i := range people {
+		people[i] = Person{Name: "P" + strconv.Itoa(i), Email: "p@example.com", Age: i}
+	}
+
+	var out writeCounter
+	writer, err := rowboat.NewWriter[Person](&out, rowboat.WithFlushEvery(3))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteBatch(people); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	if out.writes != 3 {
+		t.Errorf("Expected: %+v\nGot: %+v", 3, out.writes)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 10 {
+		t.Errorf("Expected: %+v\nGot: %+v", 10, lines)
+	}
+
+	// Each record is 19 bytes, so a 50 byte threshold flushes every third
+	out = writeCounter{}
+	writer, err = rowboat.NewWriter[Person](&out, rowboat.WithFlushBytes(50))
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteBatch(people); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	if out.writes != 3 {
+		t.Errorf("Expected: %+v\nGot: %+v", 3, out.writes)
+	}
+}
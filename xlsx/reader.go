@@ -0,0 +1,435 @@
+// Package xlsx reads and writes Excel workbooks using the same struct tags
+// as rowboat. Worksheet rows are mapped exactly like CSV records: the first
+// row is the header and each following row is decoded into a struct.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/notnil/rowboat"
+)
+
+const relationshipsNS = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+
+// maxColumns is the number of columns of a worksheet, up to XFD
+const maxColumns = 16384
+
+// NewReader creates a rowboat Reader over the rows of the named worksheet in
+// the workbook read from r. An empty sheet name selects the first sheet.
+// Cells formatted as dates are converted to RFC 3339 text so they decode
+// into time.Time fields with the default layout.
+func NewReader[T any](r io.ReaderAt, size int64, sheet string, opts ...rowboat.Option) (*rowboat.Reader[T], error) {
+	sr, err := openSheet(r, size, sheet)
+	if err != nil {
+		return nil, err
+	}
+	return rowboat.NewRecordReader[T](sr, opts...)
+}
+
+// workbook is the subset of xl/workbook.xml needed to locate sheets
+type workbook struct {
+	Properties struct {
+		Date1904 bool `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// relationships is the content of a .rels part
+type relationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// styleSheet is the subset of xl/styles.xml needed to recognize dates
+type styleSheet struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// openSheet locates the named sheet and returns a reader over its rows
+func openSheet(r io.ReaderAt, size int64, sheet string) (*sheetReader, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var wb workbook
+	if err := decodePart(files, "xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	var rels relationships
+	if err := decodePart(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+
+	target := ""
+	for _, s := range wb.Sheets {
+		if sheet != "" && s.Name != sheet {
+			continue
+		}
+		for _, rel := range rels.Items {
+			if rel.ID == s.ID {
+				target = rel.Target
+			}
+		}
+		break
+	}
+	if target == "" {
+		return nil, fmt.Errorf("sheet '%s' not found", sheet)
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+
+	sr := &sheetReader{date1904: wb.Properties.Date1904}
+	if sr.shared, err = readSharedStrings(files); err != nil {
+		return nil, err
+	}
+	if sr.dateStyles, err = readDateStyles(files); err != nil {
+		return nil, err
+	}
+
+	f, ok := files[target]
+	if !ok {
+		return nil, fmt.Errorf("missing worksheet part %s", target)
+	}
+	if sr.rc, err = f.Open(); err != nil {
+		return nil, err
+	}
+	sr.dec = xml.NewDecoder(sr.rc)
+	return sr, nil
+}
+
+// decodePart unmarshals the XML part with the given name
+func decodePart(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("missing workbook part %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// readSharedStrings reads the shared string table, which is optional
+func readSharedStrings(files map[string]*zip.File) ([]string, error) {
+	f, ok := files["xl/sharedStrings.xml"]
+	if !ok {
+		return nil, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var shared []string
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "si" {
+			text, err := readText(dec, "si")
+			if err != nil {
+				return nil, err
+			}
+			shared = append(shared, text)
+		}
+	}
+}
+
+// readDateStyles returns, for each cell style index, whether it formats
+// numbers as dates. Styles are optional.
+func readDateStyles(files map[string]*zip.File) ([]bool, error) {
+	if _, ok := files["xl/styles.xml"]; !ok {
+		return nil, nil
+	}
+	var styles styleSheet
+	if err := decodePart(files, "xl/styles.xml", &styles); err != nil {
+		return nil, err
+	}
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, nf := range styles.NumFmts {
+		custom[nf.ID] = nf.Code
+	}
+	dates := make([]bool, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		if code, ok := custom[xf.NumFmtID]; ok {
+			dates[i] = isDateFormat(code)
+		} else {
+			dates[i] = isBuiltinDateFormat(xf.NumFmtID)
+		}
+	}
+	return dates, nil
+}
+
+// isBuiltinDateFormat reports whether a built-in number format is a date format
+func isBuiltinDateFormat(id int) bool {
+	return (id >= 14 && id <= 22) || (id >= 45 && id <= 47)
+}
+
+// isDateFormat reports whether a custom number format code formats dates
+func isDateFormat(code string) bool {
+	inQuote, inBracket := false, false
+	for _, r := range strings.ToLower(code) {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '[':
+			inBracket = true
+		case r == ']':
+			inBracket = false
+		case inBracket:
+		case strings.ContainsRune("ymdhs", r):
+			return true
+		}
+	}
+	return false
+}
+
+// readText concatenates the text of all <t> elements until the end of the
+// enclosing element, skipping phonetic runs
+func readText(dec *xml.Decoder, end string) (string, error) {
+	var sb strings.Builder
+	inText, depthPhonetic := false, 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "rPh":
+				depthPhonetic++
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "rPh":
+				depthPhonetic--
+			case end:
+				return sb.String(), nil
+			}
+		case xml.CharData:
+			if inText && depthPhonetic == 0 {
+				sb.Write(t)
+			}
+		}
+	}
+}
+
+// sheetReader streams the rows of a worksheet as records
+type sheetReader struct {
+	rc         io.ReadCloser
+	dec        *xml.Decoder
+	shared     []string
+	dateStyles []bool
+	date1904   bool
+	row        int
+}
+
+// Read returns the next non-empty row of the sheet
+func (sr *sheetReader) Read() ([]string, error) {
+	if sr.dec == nil {
+		return nil, io.EOF
+	}
+	for {
+		tok, err := sr.dec.Token()
+		if err == io.EOF {
+			sr.rc.Close()
+			sr.dec = nil
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "row" {
+			for _, attr := range se.Attr {
+				if attr.Name.Local == "r" {
+					sr.row, _ = strconv.Atoi(attr.Value)
+				}
+			}
+			return sr.readRow()
+		}
+	}
+}
+
+// FieldPos returns the sheet row of the last record read, so row errors
+// report spreadsheet row numbers
+func (sr *sheetReader) FieldPos(field int) (line, column int) {
+	return sr.row, field + 1
+}
+
+// readRow reads the cells of the current <row> element
+func (sr *sheetReader) readRow() ([]string, error) {
+	var record []string
+	next := 0
+	for {
+		tok, err := sr.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "c" {
+				continue
+			}
+			col := next
+			var ref, typ string
+			style := -1
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case "r":
+					ref = attr.Value
+					if c, ok := columnIndex(ref); ok {
+						col = c
+					}
+				case "t":
+					typ = attr.Value
+				case "s":
+					style, _ = strconv.Atoi(attr.Value)
+				}
+			}
+			// Reject columns a worksheet cannot have before growing the record
+			if col >= maxColumns {
+				return nil, fmt.Errorf("cell %s is beyond column XFD", ref)
+			}
+			value, err := sr.readCell(typ, style)
+			if err != nil {
+				return nil, err
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			record[col] = value
+			next = col + 1
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				return record, nil
+			}
+		}
+	}
+}
+
+// readCell reads the value of the current <c> element as text
+func (sr *sheetReader) readCell(typ string, style int) (string, error) {
+	var raw string
+	for {
+		tok, err := sr.dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "v":
+				var v string
+				if err := sr.dec.DecodeElement(&v, &t); err != nil {
+					return "", err
+				}
+				raw = v
+			case "is":
+				text, err := readText(sr.dec, "is")
+				if err != nil {
+					return "", err
+				}
+				raw = text
+			}
+		case xml.EndElement:
+			if t.Name.Local == "c" {
+				return sr.cellText(typ, style, raw)
+			}
+		}
+	}
+}
+
+// cellText converts a raw cell value according to its type and style
+func (sr *sheetReader) cellText(typ string, style int, raw string) (string, error) {
+	switch typ {
+	case "s":
+		idx, err := strconv.Atoi(raw)
+		if err != nil || idx < 0 || idx >= len(sr.shared) {
+			return "", fmt.Errorf("invalid shared string index '%s'", raw)
+		}
+		return sr.shared[idx], nil
+	case "b":
+		return strconv.FormatBool(raw == "1"), nil
+	case "e":
+		return "", errors.New("cell contains error " + raw)
+	case "", "n":
+		if raw != "" && style >= 0 && style < len(sr.dateStyles) && sr.dateStyles[style] {
+			serial, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return "", err
+			}
+			return serialToTime(serial, sr.date1904).Format(time.RFC3339), nil
+		}
+		return raw, nil
+	default: // "str", "inlineStr"
+		return raw, nil
+	}
+}
+
+// columnIndex converts a cell reference such as "AB12" to a zero-based
+// column. Columns beyond XFD are reported as maxColumns.
+func columnIndex(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = min(col*26+int(r-'A'+1), maxColumns+1)
+		n++
+	}
+	return col - 1, n > 0
+}
+
+// serialToTime converts an Excel serial date to a UTC time
+func serialToTime(serial float64, date1904 bool) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	ms := math.Round((serial - days) * 24 * 60 * 60 * 1000)
+	return epoch.AddDate(0, 0, int(days)).Add(time.Duration(ms) * time.Millisecond)
+}
@@ -0,0 +1,104 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat/xlsx"
+)
+
+// dataSheet is the Data worksheet of buildWorkbook
+const dataSheet = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>4</v></c></row>
+<row r="2"><c r="A2" t="str"><v>00042</v></c><c r="B2" t="s"><v>5</v></c><c r="C2"><v>10.25</v></c><c r="D2" t="b"><v>1</v></c><c r="E2" s="1"><v>45292</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>00043</t></is></c><c r="C4"><v>0</v></c><c r="D4" t="b"><v>0</v></c><c r="E4" s="2"><v>45292.5</v></c></row>
+</sheetData></worksheet>`
+
+// buildWorkbook creates a workbook the way Excel stores it: shared strings,
+// a date style and sparse cells
+func buildWorkbook(t *testing.T) []byte {
+	t.Helper()
+	return buildWorkbookWith(t, dataSheet)
+}
+
+// buildWorkbookWith creates the workbook of buildWorkbook with the given
+// Data worksheet
+func buildWorkbookWith(t *testing.T, sheet string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Data" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>code</t></si><si><t>owner</t></si><si><t>balance</t></si><si><t>active</t></si><si><t>opened</t></si>
+<si><r><t>Ali</t></r><r><t>ce</t></r></si>
+</sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd"/></numFmts>
+<cellXfs count="3"><xf numFmtId="0"/><xf numFmtId="164"/><xf numFmtId="14"/></cellXfs>
+</styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>ignored</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": sheet,
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create part: %v", err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	data := buildWorkbook(t)
+	rb, err := xlsx.NewReader[Account](bytes.NewReader(data), int64(len(data)), "Data")
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+
+	expected := []Account{
+		{Code: "00042", Owner: "Alice", Balance: 10.25, Active: true, Opened: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Code: "00043", Active: false, Opened: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Read results do not match expected.\nExpected: %+v\nGot: %+v", expected, results)
+	}
+}
+
+func TestReaderMissingSheet(t *testing.T) {
+	data := buildWorkbook(t)
+	if _, err := xlsx.NewReader[Account](bytes.NewReader(data), int64(len(data)), "Nope"); err == nil {
+		t.Errorf("Expected error for missing sheet")
+	}
+}
+
+func TestReaderColumnLimit(t *testing.T) {
+	sheet := `<worksheet><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>code</t></is></c><c r="XFDXFDXFD1" t="inlineStr"><is><t>x</t></is></c></row></sheetData></worksheet>`
+	data := buildWorkbookWith(t, sheet)
+	_, err := xlsx.NewReader[Account](bytes.NewReader(data), int64(len(data)), "Data")
+	if err == nil || !strings.Contains(err.Error(), "beyond column XFD") {
+		t.Errorf("Expected a column limit error, got %v", err)
+	}
+}
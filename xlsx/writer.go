@@ -0,0 +1,175 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/notnil/rowboat"
+)
+
+// Writer writes structs as rows of a single-sheet workbook. Call Close to
+// complete the workbook; it does not close the underlying io.Writer.
+type Writer[T any] struct {
+	*rowboat.Writer[T]
+	sheet *sheetWriter
+}
+
+// NewWriter creates a Writer producing a workbook with one worksheet of the
+// given name. Call WriteHeader before writing rows, as with rowboat.Writer.
+// Numeric values are stored as number cells; everything else, including
+// zero-padded codes, is stored as text.
+func NewWriter[T any](w io.Writer, sheet string, opts ...rowboat.Option) (*Writer[T], error) {
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	sw, err := newSheetWriter(w, sheet)
+	if err != nil {
+		return nil, err
+	}
+	rw, err := rowboat.NewRecordWriter[T](sw, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer[T]{Writer: rw, sheet: sw}, nil
+}
+
+// Close flushes all rows and writes the end of the workbook
+func (w *Writer[T]) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		return err
+	}
+	return w.sheet.close()
+}
+
+const (
+	contentTypesXML = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+	rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	workbookRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+	stylesXML = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+		`<borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf/></cellStyleXfs>` +
+		`<cellXfs count="1"><xf xfId="0"/></cellXfs>` +
+		`</styleSheet>`
+	sheetStartXML = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetEndXML   = `</sheetData></worksheet>`
+)
+
+// numberPattern matches values stored as number cells without changing
+// their text, which excludes zero-padded codes and long digit strings
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,14})(\.[0-9]{1,15})?$`)
+
+// sheetWriter streams rows into the worksheet part of a workbook
+type sheetWriter struct {
+	zw  *zip.Writer
+	buf *bufio.Writer
+	row int
+	err error
+}
+
+// newSheetWriter writes the fixed workbook parts and opens the worksheet
+func newSheetWriter(w io.Writer, sheet string) (*sheetWriter, error) {
+	zw := zip.NewWriter(w)
+
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheet))
+	workbookXML := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="` + relationshipsNS + `">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/styles.xml", stylesXML},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sw := &sheetWriter{zw: zw, buf: bufio.NewWriter(f)}
+	_, sw.err = sw.buf.WriteString(sheetStartXML)
+	return sw, nil
+}
+
+// Write appends a row to the worksheet
+func (sw *sheetWriter) Write(record []string) error {
+	if sw.err != nil {
+		return sw.err
+	}
+	sw.row++
+	row := strconv.Itoa(sw.row)
+	sw.buf.WriteString(`<row r="` + row + `">`)
+	for i, value := range record {
+		if value == "" {
+			continue
+		}
+		ref := columnName(i) + row
+		if numberPattern.MatchString(value) {
+			sw.buf.WriteString(`<c r="` + ref + `"><v>` + value + `</v></c>`)
+			continue
+		}
+		sw.buf.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(sw.buf, []byte(value))
+		sw.buf.WriteString(`</t></is></c>`)
+	}
+	_, sw.err = sw.buf.WriteString(`</row>`)
+	return sw.err
+}
+
+// Flush is a no-op: rows are buffered until the workbook is closed
+func (sw *sheetWriter) Flush() {}
+
+// Error returns the first error encountered while writing rows
+func (sw *sheetWriter) Error() error {
+	return sw.err
+}
+
+// close ends the worksheet and the zip archive
+func (sw *sheetWriter) close() error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if _, err := sw.buf.WriteString(sheetEndXML); err != nil {
+		return err
+	}
+	if err := sw.buf.Flush(); err != nil {
+		return err
+	}
+	return sw.zw.Close()
+}
+
+// columnName converts a zero-based column index to its letters, e.g. 27 to "AB"
+func columnName(col int) string {
+	var name []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = append([]byte{byte('A' + (col-1)%26)}, name...)
+	}
+	return string(name)
+}
@@ -0,0 +1,81 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/notnil/rowboat/xlsx"
+)
+
+type Account struct {
+	Code    string    `csv:"code"`
+	Owner   string    `csv:"owner"`
+	Balance float64   `csv:"balance"`
+	Active  bool      `csv:"active"`
+	Opened  time.Time `csv:"opened"`
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	accounts := []Account{
+		{Code: "00042", Owner: "Alice & <Co>", Balance: 1234.5, Active: true, Opened: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Code: "00043", Owner: "  Bob  ", Balance: -7, Active: false, Opened: time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	writer, err := xlsx.NewWriter[Account](&buf, "Accounts")
+	if err != nil {
+		t.Fatalf("Failed to create Writer: %v", err)
+	}
+	if err := writer.WriteHeader(); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := writer.WriteAll(slices.Values(accounts)); err != nil {
+		t.Fatalf("Failed to write records: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close Writer: %v", err)
+	}
+
+	// Numbers are stored as number cells, codes as text
+	sheet := readPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	if !strings.Contains(sheet, `<c r="C2"><v>1234.5</v></c>`) {
+		t.Errorf("Expected numeric balance cell, got:\n%s", sheet)
+	}
+	if !strings.Contains(sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">00042</t></is></c>`) {
+		t.Errorf("Expected text code cell, got:\n%s", sheet)
+	}
+
+	rb, err := xlsx.NewReader[Account](bytes.NewReader(buf.Bytes()), int64(buf.Len()), "Accounts")
+	if err != nil {
+		t.Fatalf("Failed to create Reader: %v", err)
+	}
+	results := slices.Collect(rb.All())
+	if !reflect.DeepEqual(results, accounts) {
+		t.Errorf("Read results do not match expected.\nExpected: %+v\nGot: %+v", accounts, results)
+	}
+}
+
+// readPart returns the content of a part of a zip archive
+func readPart(t *testing.T, data []byte, name string) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open archive: %v", err)
+	}
+	rc, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", name, err)
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", name, err)
+	}
+	return string(b)
+}